@@ -0,0 +1,160 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Command seriesdata-bundle fetches the series data published online, the
+// ubuntu distro-info data and the endoflife.date release data (including
+// the Windows Server lifecycle), and packages it into a single signed
+// bundle. Operators of disconnected environments carry the bundle in and
+// apply it with series.LoadBundle.
+//
+// Usage:
+//
+//	seriesdata-bundle [-key file] [-o file] [-definitions file]
+//
+// The key file holds a base64 encoded ed25519 private key, or its seed.
+// Without a key the bundle is written unsigned.
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/os/series"
+)
+
+// defaultDistroInfoURL is the location of the ubuntu distro-info data in
+// the distro-info-data repository.
+const defaultDistroInfoURL = "https://salsa.debian.org/debian/distro-info-data/-/raw/main/ubuntu.csv"
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "seriesdata-bundle: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// config holds the settings of a bundle build.
+type config struct {
+	output          string
+	keyFile         string
+	definitionsFile string
+	distroInfoURL   string
+	endOfLifeURL    string
+	cacheDir        string
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer) error {
+	var cfg config
+	flags := flag.NewFlagSet("seriesdata-bundle", flag.ContinueOnError)
+	flags.StringVar(&cfg.output, "o", "", "write the bundle to `file` rather than stdout")
+	flags.StringVar(&cfg.keyFile, "key", "", "sign the bundle with the ed25519 private key in `file`")
+	flags.StringVar(&cfg.definitionsFile, "definitions", "", "include the series definitions in `file`")
+	flags.StringVar(&cfg.distroInfoURL, "distro-info-url", defaultDistroInfoURL, "fetch the ubuntu distro-info data from `url`")
+	flags.StringVar(&cfg.endOfLifeURL, "endoflife-url", series.EndOfLifeBaseURL, "fetch the endoflife.date data from below `url`")
+	flags.StringVar(&cfg.cacheDir, "cache-dir", "", "cache fetched data in `dir`")
+	if err := flags.Parse(args); err != nil {
+		return errors.Trace(err)
+	}
+	if flags.NArg() > 0 {
+		return errors.Errorf("unexpected arguments %q", flags.Args())
+	}
+
+	var key ed25519.PrivateKey
+	if cfg.keyFile != "" {
+		var err error
+		if key, err = readKey(cfg.keyFile); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	bundle, err := build(ctx, cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cfg.output == "" {
+		return errors.Trace(series.WriteBundle(stdout, bundle, key))
+	}
+	f, err := os.Create(cfg.output)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := series.WriteBundle(f, bundle, key); err != nil {
+		_ = f.Close()
+		return errors.Trace(err)
+	}
+	return errors.Trace(f.Close())
+}
+
+// build fetches the series data and returns it as a bundle.
+func build(ctx context.Context, cfg config) (series.Bundle, error) {
+	bundle := series.Bundle{
+		Schema:  series.BundleSchema,
+		Created: time.Now().UTC(),
+	}
+	add := func(kind, name, url string) error {
+		data, err := series.NewHTTPSource(url, cfg.cacheDir).Fetch(ctx)
+		if err != nil {
+			return errors.Annotatef(err, "fetching %s", url)
+		}
+		bundle.Files = append(bundle.Files, series.BundleFile{
+			Kind:   kind,
+			Name:   name,
+			Source: url,
+			Data:   data,
+		})
+		return nil
+	}
+
+	if err := add(series.BundleDistroInfo, "ubuntu", cfg.distroInfoURL); err != nil {
+		return series.Bundle{}, errors.Trace(err)
+	}
+	base := strings.TrimSuffix(cfg.endOfLifeURL, "/") + "/"
+	for _, product := range series.EndOfLifeProducts() {
+		if err := add(series.BundleEndOfLife, product, base+product+".json"); err != nil {
+			return series.Bundle{}, errors.Trace(err)
+		}
+	}
+	if cfg.definitionsFile != "" {
+		data, err := ioutil.ReadFile(cfg.definitionsFile)
+		if err != nil {
+			return series.Bundle{}, errors.Trace(err)
+		}
+		bundle.Files = append(bundle.Files, series.BundleFile{
+			Kind:   series.BundleDefinitions,
+			Name:   "definitions",
+			Source: cfg.definitionsFile,
+			Data:   data,
+		})
+	}
+	return bundle, nil
+}
+
+// readKey reads a base64 encoded ed25519 private key, or its seed, from
+// path.
+func readKey(path string) (ed25519.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, errors.NotValidf("key encoding in %s", path)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	}
+	return nil, errors.NotValidf("ed25519 key of %d bytes in %s", len(raw), path)
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type mainSuite struct {
+	testing.CleanupSuite
+	server *httptest.Server
+}
+
+var _ = gc.Suite(&mainSuite{})
+
+func (s *mainSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/ubuntu.csv":
+			_, _ = w.Write([]byte("version,codename,series,created,release,eol\n"))
+		case strings.HasPrefix(r.URL.Path, "/api/"):
+			_, _ = w.Write([]byte("[]"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+}
+
+func (s *mainSuite) args(extra ...string) []string {
+	return append([]string{
+		"-distro-info-url", s.server.URL + "/ubuntu.csv",
+		"-endoflife-url", s.server.URL + "/api",
+	}, extra...)
+}
+
+func (s *mainSuite) TestSignedBundle(c *gc.C) {
+	dir := c.MkDir()
+	private := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+	keyFile := filepath.Join(dir, "key")
+	err := ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(private.Seed())+"\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	definitions := filepath.Join(dir, "definitions.json")
+	err = ioutil.WriteFile(definitions, []byte(`{"schema": 1, "series": []}`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	output := filepath.Join(dir, "bundle.json")
+
+	err = run(context.Background(), s.args("-key", keyFile, "-definitions", definitions, "-o", output), ioutil.Discard)
+	c.Assert(err, jc.ErrorIsNil)
+
+	f, err := os.Open(output)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	verifier := series.SignatureVerifier{Keys: []ed25519.PublicKey{private.Public().(ed25519.PublicKey)}}
+	bundle, err := series.ReadBundle(f, verifier)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var names []string
+	for _, file := range bundle.Files {
+		names = append(names, file.Kind+"/"+file.Name)
+	}
+	expected := []string{"distro-info/ubuntu"}
+	for _, product := range series.EndOfLifeProducts() {
+		expected = append(expected, "endoflife/"+product)
+	}
+	c.Check(names, jc.DeepEquals, append(expected, "definitions/definitions"))
+	c.Check(bundle.Files[1].Source, gc.Equals, s.server.URL+"/api/"+series.EndOfLifeProducts()[0]+".json")
+}
+
+func (s *mainSuite) TestUnsignedToStdout(c *gc.C) {
+	var stdout bytes.Buffer
+	err := run(context.Background(), s.args(), &stdout)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = series.ReadBundle(&stdout, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *mainSuite) TestFetchFailure(c *gc.C) {
+	err := run(context.Background(), []string{"-distro-info-url", s.server.URL + "/missing.csv"}, ioutil.Discard)
+	c.Assert(err, gc.ErrorMatches, `fetching .*/missing.csv: .*`)
+}
+
+func (s *mainSuite) TestBadKey(c *gc.C) {
+	keyFile := filepath.Join(c.MkDir(), "key")
+	err := ioutil.WriteFile(keyFile, []byte("c2hvcnQ="), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	err = run(context.Background(), s.args("-key", keyFile), ioutil.Discard)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
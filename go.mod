@@ -14,5 +14,5 @@ require (
 	golang.org/x/sys v0.0.0-20190922100055-0a153f010e69
 	gopkg.in/check.v1 v1.0.0-20160105164936-4f90aeace3a2
 	gopkg.in/mgo.v2 v2.0.0-20160818015218-f2b6f6c918c4 // indirect
-	gopkg.in/yaml.v2 v2.0.0-20170712054546-1be3d31502d6 // indirect
+	gopkg.in/yaml.v2 v2.0.0-20170712054546-1be3d31502d6
 )
@@ -4,29 +4,83 @@
 // Package os provides access to operating system related configuration.
 package os
 
+import (
+	"fmt"
+	"strings"
+)
+
 var HostOS = hostOS // for monkey patching
 
+// OSType identifies an operating system. The zero OSType is Unknown.
 type OSType int
 
 const (
+	// Unknown is the OS type of hosts and series whose operating system
+	// isn't recognized. It is the zero value, so an unset OSType is
+	// Unknown. Unknown is neither a Linux nor a BSD variant, and is only
+	// equivalent to itself. Its String is "Unknown", which ParseOSType
+	// accepts.
 	Unknown OSType = iota
 	Ubuntu
 	Windows
-	OSX
+	MacOS
 	CentOS
 	GenericLinux
 	OpenSUSE
 	Kubernetes
+	OpenBSD
+	NetBSD
+	AIX
+	Android
+	Debian
+	Fedora
+	RHEL
+	Rocky
+	AlmaLinux
 )
 
+// osTypes holds the OS types other than Unknown, in order.
+var osTypes = []OSType{
+	Ubuntu,
+	Windows,
+	MacOS,
+	CentOS,
+	GenericLinux,
+	OpenSUSE,
+	Kubernetes,
+	OpenBSD,
+	NetBSD,
+	AIX,
+	Android,
+	Debian,
+	Fedora,
+	RHEL,
+	Rocky,
+	AlmaLinux,
+}
+
+// OSTypes returns all the OS types other than Unknown. For every OS type
+// t, including Unknown, ParseOSType(t.String()) returns t.
+func OSTypes() []OSType {
+	return append([]OSType(nil), osTypes...)
+}
+
+// OSX is the former name of MacOS.
+//
+// Deprecated: use MacOS.
+const OSX = MacOS
+
+// String returns the name of the OS type. Values that aren't defined are
+// named "Unknown", like Unknown itself. MacOS is named "macos", rather
+// than after the former OSX branding.
 func (t OSType) String() string {
 	switch t {
 	case Ubuntu:
 		return "Ubuntu"
 	case Windows:
 		return "Windows"
-	case OSX:
-		return "OSX"
+	case MacOS:
+		return "macos"
 	case CentOS:
 		return "CentOS"
 	case GenericLinux:
@@ -35,23 +89,93 @@ func (t OSType) String() string {
 		return "OpenSUSE"
 	case Kubernetes:
 		return "Kubernetes"
+	case OpenBSD:
+		return "OpenBSD"
+	case NetBSD:
+		return "NetBSD"
+	case AIX:
+		return "AIX"
+	case Android:
+		return "Android"
+	case Debian:
+		return "Debian"
+	case Fedora:
+		return "Fedora"
+	case RHEL:
+		return "RHEL"
+	case Rocky:
+		return "Rocky"
+	case AlmaLinux:
+		return "AlmaLinux"
 	}
 	return "Unknown"
 }
 
+// FormatOSType returns the canonical string form of the OS type, the
+// lowercase form of its name, for example "ubuntu". This is the form used
+// in series data, platforms and constraints, and the form that OS types
+// should be compared in.
+func FormatOSType(t OSType) string {
+	return strings.ToLower(t.String())
+}
+
+// ParseOSType returns the OS type named by name, ignoring case, as
+// returned by OSType.String or FormatOSType. The former name of MacOS,
+// "osx", is also accepted, and "unknown" gives Unknown. Other names give
+// Unknown and an error.
+func ParseOSType(name string) (OSType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "osx":
+		return MacOS, nil
+	case FormatOSType(Unknown):
+		return Unknown, nil
+	}
+	for _, t := range osTypes {
+		if name == FormatOSType(t) {
+			return t, nil
+		}
+	}
+	return Unknown, fmt.Errorf("unknown OS type %q", name)
+}
+
+// IsKnown returns true if the OS type is one of those returned by OSTypes,
+// rather than Unknown or an undefined value.
+func (t OSType) IsKnown() bool {
+	for _, known := range osTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
 // EquivalentTo returns true if the OS type is equivalent to another
-// OS type.
+// OS type. Every OS type is equivalent to itself, including Unknown, but
+// unknown OS types aren't equivalent to any other OS type.
 func (t OSType) EquivalentTo(t2 OSType) bool {
 	if t == t2 {
 		return true
 	}
+	if !t.IsKnown() || !t2.IsKnown() {
+		return false
+	}
 	return t.IsLinux() && t2.IsLinux()
 }
 
+// IsBSD returns true if the OS type is a BSD variant.
+func (t OSType) IsBSD() bool {
+	switch t {
+	case OpenBSD, NetBSD:
+		return true
+	}
+	return false
+}
+
 // IsLinux returns true if the OS type is a Linux variant.
 func (t OSType) IsLinux() bool {
 	switch t {
-	case Ubuntu, CentOS, GenericLinux, OpenSUSE:
+	case Ubuntu, CentOS, GenericLinux, OpenSUSE, Debian, Fedora, RHEL, Rocky, AlmaLinux:
 		return true
 	}
 	return false
@@ -4,5 +4,5 @@
 package os
 
 func hostOS() OSType {
-	return OSX
+	return MacOS
 }
@@ -0,0 +1,32 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build go1.18
+
+package os
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func FuzzParseOSRelease(f *testing.F) {
+	f.Add([]byte("NAME=\"Ubuntu\"\nID=ubuntu\nVERSION_ID=\"20.04\"\n"))
+	f.Add([]byte("ID='\"'\"'\"\n"))
+	f.Add([]byte(strings.Repeat("=", 1000)))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		values, err := parseOSRelease(data)
+		if err != nil {
+			return
+		}
+		for key, value := range values {
+			if key == "" {
+				t.Errorf("empty key")
+			}
+			if !utf8.ValidString(key) || !utf8.ValidString(value) {
+				t.Errorf("key %q has invalid UTF-8", key)
+			}
+		}
+	})
+}
@@ -5,7 +5,10 @@ package os
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	goos "os"
 	"strings"
 	"sync"
 )
@@ -14,12 +17,19 @@ var (
 	// osReleaseFile is the name of the file that is read in order to determine
 	// the linux type release version.
 	osReleaseFile = "/etc/os-release"
-	osOnce        sync.Once
-	os            OSType // filled in by the first call to hostOS
+	// androidBuildPropFile holds the Android system properties. It only
+	// exists on Android, which usually has no os-release file.
+	androidBuildPropFile = "/system/build.prop"
+	osOnce               sync.Once
+	os                   OSType // filled in by the first call to hostOS
 )
 
 func hostOS() OSType {
 	osOnce.Do(func() {
+		if isAndroid(androidBuildPropFile) {
+			os = Android
+			return
+		}
 		var err error
 		os, err = updateOS(osReleaseFile)
 		if err != nil {
@@ -29,42 +39,123 @@ func hostOS() OSType {
 	return os
 }
 
+// isAndroid reports whether the host runs Android, including from a Termux
+// or adb shell, given the path of the Android system properties file. The
+// file isn't always readable, so only its existence is checked.
+func isAndroid(buildPropFile string) bool {
+	_, err := goos.Stat(buildPropFile)
+	return err == nil
+}
+
+// openSUSELeapID is the os-release ID of openSUSE Leap 15 onwards; earlier
+// releases use "opensuse".
+const openSUSELeapID = "opensuse-leap"
+
 func updateOS(f string) (OSType, error) {
 	values, err := ReadOSRelease(f)
 	if err != nil {
 		return Unknown, err
 	}
 	switch values["ID"] {
-	case strings.ToLower(Ubuntu.String()):
+	case FormatOSType(Ubuntu):
 		return Ubuntu, nil
-	case strings.ToLower(CentOS.String()):
+	case FormatOSType(CentOS):
 		return CentOS, nil
-	case strings.ToLower(OpenSUSE.String()):
+	case FormatOSType(Debian):
+		return Debian, nil
+	case FormatOSType(Fedora):
+		return Fedora, nil
+	case FormatOSType(RHEL):
+		return RHEL, nil
+	case FormatOSType(Rocky):
+		return Rocky, nil
+	case FormatOSType(AlmaLinux):
+		return AlmaLinux, nil
+	case FormatOSType(OpenSUSE), openSUSELeapID:
 		return OpenSUSE, nil
 	default:
 		return GenericLinux, nil
 	}
 }
 
+// maxOSReleaseSize bounds how much of an os-release file is read. Real
+// files are a few hundred bytes, so anything larger is corrupt or hostile.
+const maxOSReleaseSize = 64 << 10
+
 // ReadOSRelease parses the information in the os-release file.
 //
 // See http://www.freedesktop.org/software/systemd/man/os-release.html.
 func ReadOSRelease(f string) (map[string]string, error) {
-	contents, err := ioutil.ReadFile(f)
+	file, err := goos.Open(f)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	contents, err := ioutil.ReadAll(io.LimitReader(file, maxOSReleaseSize+1))
 	if err != nil {
 		return nil, err
 	}
+	if len(contents) > maxOSReleaseSize {
+		return nil, fmt.Errorf("OS release file exceeds %d bytes", maxOSReleaseSize)
+	}
+	return parseOSRelease(contents)
+}
+
+// parseOSRelease parses the contents of an os-release file. Comments and
+// malformed lines are skipped, and invalid UTF-8 is replaced, so that a
+// corrupt file can't produce values that upset later processing.
+func parseOSRelease(contents []byte) (map[string]string, error) {
 	values := make(map[string]string)
 	releaseDetails := strings.Split(string(contents), "\n")
 	for _, val := range releaseDetails {
+		val = strings.TrimSpace(val)
+		if strings.HasPrefix(val, "#") {
+			continue
+		}
 		c := strings.SplitN(val, "=", 2)
-		if len(c) != 2 {
+		if len(c) != 2 || c[0] == "" {
 			continue
 		}
-		values[c[0]] = strings.Trim(c[1], "\t '\"")
+		key := strings.ToValidUTF8(c[0], "")
+		values[key] = strings.ToValidUTF8(strings.Trim(c[1], "\t '\""), "\uFFFD")
 	}
 	if _, ok := values["ID"]; !ok {
 		return nil, errors.New("OS release file is missing ID")
 	}
 	return values, nil
 }
+
+// maxBuildPropSize bounds how much of an Android system properties file is
+// read.
+const maxBuildPropSize = 1 << 20
+
+// ReadAndroidProperties parses the Android system properties in f, usually
+// /system/build.prop, which holds lines like
+// "ro.build.version.release=14".
+func ReadAndroidProperties(f string) (map[string]string, error) {
+	file, err := goos.Open(f)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	contents, err := ioutil.ReadAll(io.LimitReader(file, maxBuildPropSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) > maxBuildPropSize {
+		return nil, fmt.Errorf("android properties file exceeds %d bytes", maxBuildPropSize)
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		c := strings.SplitN(line, "=", 2)
+		if len(c) != 2 || c[0] == "" {
+			continue
+		}
+		values[strings.TrimSpace(c[0])] = strings.ToValidUTF8(strings.TrimSpace(c[1]), "\uFFFD")
+	}
+	return values, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package os
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type linuxSuite struct {
+}
+
+var _ = gc.Suite(&linuxSuite{})
+
+func (s *linuxSuite) TestParseOSRelease(c *gc.C) {
+	values, err := parseOSRelease([]byte(`# comment
+NAME="Ubuntu"
+ID=ubuntu
+  VERSION_ID="20.04"
+=orphan
+junk
+` + "PRETTY_NAME=\"Ubuntu \xff\"\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{
+		"NAME":        "Ubuntu",
+		"ID":          "ubuntu",
+		"VERSION_ID":  "20.04",
+		"PRETTY_NAME": "Ubuntu \uFFFD",
+	})
+}
+
+func (s *linuxSuite) TestParseOSReleaseMissingID(c *gc.C) {
+	_, err := parseOSRelease([]byte("# ID=ubuntu\n"))
+	c.Assert(err, gc.ErrorMatches, "OS release file is missing ID")
+}
+
+func (s *linuxSuite) TestReadOSReleaseTooLarge(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "os-release")
+	data := "ID=ubuntu\n" + strings.Repeat("#", maxOSReleaseSize)
+	err := ioutil.WriteFile(path, []byte(data), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = ReadOSRelease(path)
+	c.Assert(err, gc.ErrorMatches, "OS release file exceeds 65536 bytes")
+}
+
+func (s *linuxSuite) TestUpdateOSOpenSUSE(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "os-release")
+	for _, id := range []string{"opensuse", "opensuse-leap"} {
+		err := ioutil.WriteFile(path, []byte("ID="+id+"\n"), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+		osType, err := updateOS(path)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(osType, gc.Equals, OpenSUSE)
+	}
+}
+
+func (s *linuxSuite) TestIsAndroid(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "build.prop")
+	c.Check(isAndroid(path), jc.IsFalse)
+	err := ioutil.WriteFile(path, nil, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(isAndroid(path), jc.IsTrue)
+}
+
+func (s *linuxSuite) TestReadAndroidProperties(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "build.prop")
+	err := ioutil.WriteFile(path, []byte(`# begin build properties
+ro.build.version.release=14
+ro.build.version.sdk = 34
+junk
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	values, err := ReadAndroidProperties(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{
+		"ro.build.version.release": "14",
+		"ro.build.version.sdk":     "34",
+	})
+}
+
+func (s *linuxSuite) TestUpdateOSDebian(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "os-release")
+	err := ioutil.WriteFile(path, []byte("ID=debian\nVERSION_ID=\"12\"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	osType, err := updateOS(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(osType, gc.Equals, Debian)
+}
+
+func (s *linuxSuite) TestUpdateOSFedora(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "os-release")
+	err := ioutil.WriteFile(path, []byte("ID=fedora\nVERSION_ID=39\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	osType, err := updateOS(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(osType, gc.Equals, Fedora)
+}
+
+func (s *linuxSuite) TestUpdateOSRHEL(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "os-release")
+	err := ioutil.WriteFile(path, []byte("ID=\"rhel\"\nID_LIKE=\"fedora\"\nVERSION_ID=\"9.2\"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	osType, err := updateOS(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(osType, gc.Equals, RHEL)
+}
+
+func (s *linuxSuite) TestUpdateOSRocky(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "os-release")
+	err := ioutil.WriteFile(path, []byte("ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\nVERSION_ID=\"9.3\"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	osType, err := updateOS(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(osType, gc.Equals, Rocky)
+}
+
+func (s *linuxSuite) TestUpdateOSAlmaLinux(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "os-release")
+	err := ioutil.WriteFile(path, []byte("ID=\"almalinux\"\nID_LIKE=\"rhel centos fedora\"\nVERSION_ID=\"8.10\"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	osType, err := updateOS(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(osType, gc.Equals, AlmaLinux)
+}
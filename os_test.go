@@ -21,12 +21,20 @@ func (s *osSuite) TestHostOS(c *gc.C) {
 	case "windows":
 		c.Assert(os, gc.Equals, Windows)
 	case "darwin":
-		c.Assert(os, gc.Equals, OSX)
+		c.Assert(os, gc.Equals, MacOS)
+	case "openbsd":
+		c.Assert(os, gc.Equals, OpenBSD)
+	case "netbsd":
+		c.Assert(os, gc.Equals, NetBSD)
+	case "aix":
+		c.Assert(os, gc.Equals, AIX)
+	case "android":
+		c.Assert(os, gc.Equals, Android)
 	case "linux":
 		// TODO(mjs) - this should really do more by patching out
 		// osReleaseFile and testing the corner cases.
 		switch os {
-		case Ubuntu, CentOS, GenericLinux:
+		case Ubuntu, CentOS, GenericLinux, Debian, Fedora, RHEL, Rocky, AlmaLinux:
 		case OpenSUSE:
 			c.Assert(os, gc.Equals, OpenSUSE)
 		default:
@@ -45,10 +53,11 @@ func (s *osSuite) TestEquivalentTo(c *gc.C) {
 	c.Check(GenericLinux.EquivalentTo(OpenSUSE), jc.IsTrue)
 	c.Check(CentOS.EquivalentTo(CentOS), jc.IsTrue)
 	c.Check(CentOS.EquivalentTo(OpenSUSE), jc.IsTrue)
+	c.Check(Debian.EquivalentTo(Ubuntu), jc.IsTrue)
 
-	c.Check(OSX.EquivalentTo(Ubuntu), jc.IsFalse)
-	c.Check(OSX.EquivalentTo(Windows), jc.IsFalse)
-	c.Check(GenericLinux.EquivalentTo(OSX), jc.IsFalse)
+	c.Check(MacOS.EquivalentTo(Ubuntu), jc.IsFalse)
+	c.Check(MacOS.EquivalentTo(Windows), jc.IsFalse)
+	c.Check(GenericLinux.EquivalentTo(MacOS), jc.IsFalse)
 }
 
 func (s *osSuite) TestIsLinux(c *gc.C) {
@@ -56,8 +65,84 @@ func (s *osSuite) TestIsLinux(c *gc.C) {
 	c.Check(CentOS.IsLinux(), jc.IsTrue)
 	c.Check(GenericLinux.IsLinux(), jc.IsTrue)
 	c.Check(OpenSUSE.IsLinux(), jc.IsTrue)
+	c.Check(Debian.IsLinux(), jc.IsTrue)
+	c.Check(Fedora.IsLinux(), jc.IsTrue)
+	c.Check(RHEL.IsLinux(), jc.IsTrue)
+	c.Check(Rocky.IsLinux(), jc.IsTrue)
+	c.Check(AlmaLinux.IsLinux(), jc.IsTrue)
 
-	c.Check(OSX.IsLinux(), jc.IsFalse)
+	c.Check(MacOS.IsLinux(), jc.IsFalse)
 	c.Check(Windows.IsLinux(), jc.IsFalse)
 	c.Check(Unknown.IsLinux(), jc.IsFalse)
+	c.Check(OpenBSD.IsLinux(), jc.IsFalse)
+	c.Check(NetBSD.IsLinux(), jc.IsFalse)
+	c.Check(AIX.IsLinux(), jc.IsFalse)
+	c.Check(Android.IsLinux(), jc.IsFalse)
+}
+
+func (s *osSuite) TestIsBSD(c *gc.C) {
+	c.Check(OpenBSD.IsBSD(), jc.IsTrue)
+	c.Check(NetBSD.IsBSD(), jc.IsTrue)
+
+	c.Check(Ubuntu.IsBSD(), jc.IsFalse)
+	c.Check(MacOS.IsBSD(), jc.IsFalse)
+	c.Check(Unknown.IsBSD(), jc.IsFalse)
+}
+
+func (s *osSuite) TestParseOSType(c *gc.C) {
+	for _, t := range append(OSTypes(), Unknown) {
+		got, err := ParseOSType(t.String())
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(got, gc.Equals, t)
+	}
+
+	got, err := ParseOSType("osx")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, gc.Equals, MacOS)
+	c.Assert(OSX, gc.Equals, MacOS)
+	c.Assert(MacOS.String(), gc.Equals, "macos")
+
+	_, err = ParseOSType("beos")
+	c.Assert(err, gc.ErrorMatches, `unknown OS type "beos"`)
+}
+
+func (s *osSuite) TestOSTypesComplete(c *gc.C) {
+	// Every defined OS type has its own name, and OSTypes lists them all,
+	// so the round trip through ParseOSType covers every one.
+	names := make(map[string]OSType)
+	for t := Unknown; t.String() != "Unknown" || t == Unknown; t++ {
+		names[t.String()] = t
+	}
+	c.Check(names, gc.HasLen, len(OSTypes())+1)
+	for _, t := range OSTypes() {
+		c.Check(t.IsKnown(), jc.IsTrue)
+		c.Check(names[t.String()], gc.Equals, t)
+	}
+}
+
+func (s *osSuite) TestFormatOSType(c *gc.C) {
+	c.Check(FormatOSType(Ubuntu), gc.Equals, "ubuntu")
+	c.Check(FormatOSType(MacOS), gc.Equals, "macos")
+	c.Check(FormatOSType(RHEL), gc.Equals, "rhel")
+	c.Check(FormatOSType(Unknown), gc.Equals, "unknown")
+	for _, t := range append(OSTypes(), Unknown) {
+		parsed, err := ParseOSType(FormatOSType(t))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(parsed, gc.Equals, t)
+	}
+}
+
+func (s *osSuite) TestUnknown(c *gc.C) {
+	var zero OSType
+	c.Check(zero, gc.Equals, Unknown)
+	for _, t := range []OSType{Unknown, OSType(-1), OSType(1000)} {
+		c.Check(t.String(), gc.Equals, "Unknown")
+		c.Check(t.IsKnown(), jc.IsFalse)
+		c.Check(t.IsLinux(), jc.IsFalse)
+		c.Check(t.IsBSD(), jc.IsFalse)
+		c.Check(t.EquivalentTo(t), jc.IsTrue)
+		c.Check(t.EquivalentTo(Ubuntu), jc.IsFalse)
+		c.Check(Ubuntu.EquivalentTo(t), jc.IsFalse)
+	}
+	c.Check(Unknown.EquivalentTo(OSType(1000)), jc.IsFalse)
 }
@@ -1,7 +1,7 @@
 // Copyright 2015 Canonical Ltd.
 // Licensed under the LGPLv3, see LICENCE file for details.
 
-// +build !windows,!darwin,!linux
+// +build !windows,!darwin,!linux,!openbsd,!netbsd,!aix
 
 package os
 
@@ -0,0 +1,114 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// AgentBuild describes the agent binaries expected to run on a series.
+type AgentBuild struct {
+	OS os.OSType
+	// CGO is true if the binaries may be linked against glibc. Series
+	// without a known glibc, including every series that isn't linux, need
+	// binaries built with cgo disabled.
+	CGO bool
+	// Glibc is the glibc version the binaries must run with, for example
+	// "2.17", when CGO is true.
+	Glibc string
+}
+
+// AgentBuildTarget is an agent build along with the series it serves.
+type AgentBuildTarget struct {
+	AgentBuild
+	Series []Name
+}
+
+// seriesGlibcVersions holds the glibc version shipped by the linux series
+// that agent binaries linked with cgo are built for.
+var seriesGlibcVersions = map[string]string{
+	"trusty":         "2.19",
+	"xenial":         "2.23",
+	"bionic":         "2.27",
+	"cosmic":         "2.28",
+	"disco":          "2.29",
+	"eoan":           "2.30",
+	"focal":          "2.31",
+	"groovy":         "2.32",
+	"hirsute":        "2.33",
+	"jammy":          "2.35",
+	"noble":          "2.39",
+	"centos7":        "2.17",
+	"centos8":        "2.28",
+	"centos8-stream": "2.28",
+	"centos9":        "2.34",
+	"rhel8":          "2.28",
+	"rhel9":          "2.34",
+	"rocky8":         "2.28",
+	"rocky9":         "2.34",
+	"alma8":          "2.28",
+	"alma9":          "2.34",
+	"opensuseleap":   "2.22",
+	"opensuse15.4":   "2.31",
+	"opensuse15.5":   "2.31",
+	"opensuse15.6":   "2.38",
+}
+
+// AgentBuildForSeries returns the agent build expected to run on the
+// series.
+func AgentBuildForSeries(series string) (AgentBuild, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return AgentBuild{}, errors.Trace(err)
+	}
+	osType, err := GetOSFromSeries(string(name))
+	if err != nil {
+		return AgentBuild{}, errors.Trace(err)
+	}
+	build := AgentBuild{OS: osType}
+	if glibc, ok := seriesGlibcVersions[string(name)]; ok && osType.IsLinux() {
+		build.CGO = true
+		build.Glibc = glibc
+	}
+	return build, nil
+}
+
+// AgentBuildMatrix returns the distinct agent builds needed to cover the
+// series, each with the series it serves, so that release tooling can
+// derive its build matrix. The builds are ordered by operating system, then
+// cgo-free builds first, then by glibc version.
+func AgentBuildMatrix(series []string) ([]AgentBuildTarget, error) {
+	var targets []AgentBuildTarget
+	index := make(map[AgentBuild]int)
+	for _, s := range series {
+		build, err := AgentBuildForSeries(s)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		name, _ := canonicalSeries(s)
+		i, ok := index[build]
+		if !ok {
+			i = len(targets)
+			index[build] = i
+			targets = append(targets, AgentBuildTarget{AgentBuild: build})
+		}
+		targets[i].Series = append(targets[i].Series, name)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		a, b := targets[i], targets[j]
+		if a.OS != b.OS {
+			return a.OS < b.OS
+		}
+		if a.CGO != b.CGO {
+			return !a.CGO
+		}
+		va, _ := parseConstraintVersion(a.Glibc)
+		vb, _ := parseConstraintVersion(b.Glibc)
+		return compareVersions(va, vb) < 0
+	})
+	return targets, nil
+}
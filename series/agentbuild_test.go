@@ -0,0 +1,66 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type agentBuildSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&agentBuildSuite{})
+
+func (s *agentBuildSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *agentBuildSuite) TestAgentBuildForSeries(c *gc.C) {
+	build, err := series.AgentBuildForSeries("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(build, jc.DeepEquals, series.AgentBuild{OS: os.CentOS, CGO: true, Glibc: "2.17"})
+
+	build, err = series.AgentBuildForSeries("Focal Fossa")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(build, jc.DeepEquals, series.AgentBuild{OS: os.Ubuntu, CGO: true, Glibc: "2.31"})
+
+	for _, name := range []string{"win2019", "genericlinux", "kubernetes"} {
+		build, err = series.AgentBuildForSeries(name)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(build.CGO, jc.IsFalse)
+		c.Check(build.Glibc, gc.Equals, "")
+	}
+
+	_, err = series.AgentBuildForSeries("sulu")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *agentBuildSuite) TestAgentBuildMatrix(c *gc.C) {
+	targets, err := series.AgentBuildMatrix([]string{"focal", "win2019", "bionic", "win2016", "genericlinux"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(targets, jc.DeepEquals, []series.AgentBuildTarget{{
+		AgentBuild: series.AgentBuild{OS: os.Ubuntu, CGO: true, Glibc: "2.27"},
+		Series:     []series.Name{"bionic"},
+	}, {
+		AgentBuild: series.AgentBuild{OS: os.Ubuntu, CGO: true, Glibc: "2.31"},
+		Series:     []series.Name{"focal"},
+	}, {
+		AgentBuild: series.AgentBuild{OS: os.Windows},
+		Series:     []series.Name{"win2019", "win2016"},
+	}, {
+		AgentBuild: series.AgentBuild{OS: os.GenericLinux},
+		Series:     []series.Name{"genericlinux"},
+	}})
+
+	_, err = series.AgentBuildMatrix([]string{"focal", "sulu"})
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// aixSeriesFromUname returns the series of an AIX or IBM i host from the
+// operating system name, version and release reported by uname -s, -v and
+// -r. For example AIX version "7" release "3" gives "aix7.3", and OS400,
+// the name IBM i reports, version "7" release "5" gives "ibmi7.5".
+func aixSeriesFromUname(sysname, version, release string) (string, error) {
+	version, release = strings.TrimSpace(version), strings.TrimSpace(release)
+	for _, part := range []string{version, release} {
+		if n, err := strconv.Atoi(part); err != nil || n < 0 {
+			return "unknown", errors.NotValidf("%s version %q release %q", sysname, version, release)
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(sysname)) {
+	case "aix":
+		return "aix" + version + "." + release, nil
+	case "os400":
+		return "ibmi" + version + "." + release, nil
+	}
+	return "unknown", errors.NotSupportedf("operating system %q", sysname)
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// The package archives of ubuntu and Debian.
+const (
+	UbuntuArchiveURL          = "http://archive.ubuntu.com/ubuntu"
+	UbuntuSecurityURL         = "http://security.ubuntu.com/ubuntu"
+	UbuntuPortsURL            = "http://ports.ubuntu.com/ubuntu-ports"
+	UbuntuOldReleasesURL      = "http://old-releases.ubuntu.com/ubuntu"
+	DebianArchiveURL          = "http://deb.debian.org/debian"
+	DebianSecurityURL         = "http://deb.debian.org/debian-security"
+	DebianArchivedURL         = "http://archive.debian.org/debian"
+	DebianArchivedSecurityURL = "http://archive.debian.org/debian-security"
+)
+
+// ubuntuPrimaryArches holds the architectures in the primary ubuntu
+// archive; the others are in the ports archive.
+var ubuntuPrimaryArches = map[string]bool{
+	"amd64": true,
+	"i386":  true,
+}
+
+// firstDebianSecuritySuiteVersion is the first Debian release whose
+// security updates are in the "<series>-security" suite, rather than
+// "<series>/updates".
+const firstDebianSecuritySuiteVersion = 11
+
+// Archive describes where the packages of a series are published, so that
+// mirror and proxy configuration can be derived from the series.
+type Archive struct {
+	// Series is the codename of the series, which Debian meta names such
+	// as "stable" are resolved to.
+	Series string
+	// URL is the archive holding the release and updates suites.
+	URL string
+	// SecurityURL is the archive holding the security suite. It is empty
+	// if the series gets no security updates, as with Debian unstable.
+	SecurityURL string
+	// SecuritySuite is the suite of the security updates, for example
+	// "focal-security" or "buster/updates".
+	SecuritySuite string
+}
+
+// SeriesArchive returns the package archive of an ubuntu or Debian series
+// for the architecture, such as "arm64"; an empty architecture is taken
+// as amd64. Ubuntu series past their end of life without extended
+// security maintenance are served from old-releases.ubuntu.com, and
+// Debian series past the end of their LTS support from archive.debian.org.
+// Debian series may be given by a meta name, such as "stable", as for
+// DebianSeriesVersion. An error satisfying errors.IsNotSupported is
+// returned for series of other operating systems.
+func SeriesArchive(series, arch string) (Archive, error) {
+	if FormatSeries(series) == "" {
+		return Archive{}, errors.Trace(EmptyInputError{Input: "series"})
+	}
+	name, err := canonicalSeries(series)
+	if err == nil {
+		return knownSeriesArchive(string(name), arch)
+	}
+	if !IsUnknownOSForSeriesError(err) {
+		return Archive{}, errors.Trace(err)
+	}
+	releases, debianErr := readDebianReleases()
+	if debianErr != nil {
+		logger.Debugf("cannot read debian releases: %v", debianErr)
+		return Archive{}, errors.Trace(err)
+	}
+	release, ok := resolveDebianRelease(releases, FormatSeries(series), DefaultRegistry().Now())
+	if !ok {
+		return Archive{}, errors.Trace(err)
+	}
+	return debianArchive(release), nil
+}
+
+// knownSeriesArchive returns the archive of the known series.
+func knownSeriesArchive(name, arch string) (Archive, error) {
+	seriesVersionsMutex.Lock()
+	updateSeriesVersionsOnce()
+	record := knownSeries()[name]
+	today := defaultRegistry.today()
+	seriesVersionsMutex.Unlock()
+
+	switch record.OS {
+	case os.Ubuntu:
+		return ubuntuArchive(name, record, arch, today), nil
+	case os.Debian:
+		return debianArchive(debianRelease{
+			version:  record.Version,
+			series:   name,
+			released: record.Released,
+			eol:      record.EOL,
+		}), nil
+	}
+	return Archive{}, errors.NotSupportedf("package archive of %s series %q", record.OS, name)
+}
+
+// ubuntuArchive returns the archive of the ubuntu series as of today.
+func ubuntuArchive(name string, record seriesRecord, arch string, today time.Time) Archive {
+	archive := Archive{
+		Series:        name,
+		URL:           UbuntuArchiveURL,
+		SecurityURL:   UbuntuSecurityURL,
+		SecuritySuite: name + "-security",
+	}
+	switch {
+	case !record.futureAt(today) && !record.supportedAt(today) && !record.ESMSupported:
+		archive.URL = UbuntuOldReleasesURL
+		archive.SecurityURL = UbuntuOldReleasesURL
+	case arch != "" && !ubuntuPrimaryArches[arch]:
+		archive.URL = UbuntuPortsURL
+		archive.SecurityURL = UbuntuPortsURL
+	}
+	return archive
+}
+
+// debianArchive returns the archive of the Debian release. Debian archives
+// hold every architecture.
+func debianArchive(release debianRelease) Archive {
+	archive := Archive{
+		Series:        release.series,
+		URL:           DebianArchiveURL,
+		SecurityURL:   DebianSecurityURL,
+		SecuritySuite: release.series + "-security",
+	}
+	version, err := strconv.Atoi(release.version)
+	switch {
+	case err != nil:
+		// Unstable and experimental get no security updates.
+		archive.SecurityURL, archive.SecuritySuite = "", ""
+		return archive
+	case version < firstDebianSecuritySuiteVersion:
+		archive.SecuritySuite = release.series + "/updates"
+	}
+	if !release.eol.IsZero() && DefaultRegistry().Now().After(release.eol) {
+		archive.URL = DebianArchivedURL
+		archive.SecurityURL = DebianArchivedSecurityURL
+	}
+	return archive
+}
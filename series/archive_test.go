@@ -0,0 +1,161 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type archiveSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&archiveSuite{})
+
+func (s *archiveSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	path := filepath.Join(c.MkDir(), "debian.csv")
+	err := ioutil.WriteFile(path, []byte(debianDistroInfo), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(&series.DebianDistroInfo, path)
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+}
+
+func (s *archiveSuite) TestUbuntu(c *gc.C) {
+	for _, test := range []struct {
+		series  string
+		arch    string
+		archive series.Archive
+	}{{
+		series: "focal",
+		arch:   "amd64",
+		archive: series.Archive{
+			Series:        "focal",
+			URL:           series.UbuntuArchiveURL,
+			SecurityURL:   series.UbuntuSecurityURL,
+			SecuritySuite: "focal-security",
+		},
+	}, {
+		series: "Focal",
+		archive: series.Archive{
+			Series:        "focal",
+			URL:           series.UbuntuArchiveURL,
+			SecurityURL:   series.UbuntuSecurityURL,
+			SecuritySuite: "focal-security",
+		},
+	}, {
+		series: "focal",
+		arch:   "arm64",
+		archive: series.Archive{
+			Series:        "focal",
+			URL:           series.UbuntuPortsURL,
+			SecurityURL:   series.UbuntuPortsURL,
+			SecuritySuite: "focal-security",
+		},
+	}, {
+		series: "trusty",
+		arch:   "amd64",
+		archive: series.Archive{
+			Series:        "trusty",
+			URL:           series.UbuntuArchiveURL,
+			SecurityURL:   series.UbuntuSecurityURL,
+			SecuritySuite: "trusty-security",
+		},
+	}, {
+		series: "quantal",
+		arch:   "armhf",
+		archive: series.Archive{
+			Series:        "quantal",
+			URL:           series.UbuntuOldReleasesURL,
+			SecurityURL:   series.UbuntuOldReleasesURL,
+			SecuritySuite: "quantal-security",
+		},
+	}} {
+		c.Logf("series %q arch %q", test.series, test.arch)
+		archive, err := series.SeriesArchive(test.series, test.arch)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(archive, jc.DeepEquals, test.archive)
+	}
+}
+
+func (s *archiveSuite) TestDebian(c *gc.C) {
+	for _, test := range []struct {
+		series  string
+		archive series.Archive
+	}{{
+		series: "bookworm",
+		archive: series.Archive{
+			Series:        "bookworm",
+			URL:           series.DebianArchiveURL,
+			SecurityURL:   series.DebianSecurityURL,
+			SecuritySuite: "bookworm-security",
+		},
+	}, {
+		series: "oldstable",
+		archive: series.Archive{
+			Series:        "bullseye",
+			URL:           series.DebianArchiveURL,
+			SecurityURL:   series.DebianSecurityURL,
+			SecuritySuite: "bullseye-security",
+		},
+	}, {
+		series: "buster",
+		archive: series.Archive{
+			Series:        "buster",
+			URL:           series.DebianArchiveURL,
+			SecurityURL:   series.DebianSecurityURL,
+			SecuritySuite: "buster/updates",
+		},
+	}, {
+		series: "unstable",
+		archive: series.Archive{
+			Series: "sid",
+			URL:    series.DebianArchiveURL,
+		},
+	}} {
+		c.Logf("series %q", test.series)
+		archive, err := series.SeriesArchive(test.series, "arm64")
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(archive, jc.DeepEquals, test.archive)
+	}
+}
+
+func (s *archiveSuite) TestDebianArchived(c *gc.C) {
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+	archive, err := series.SeriesArchive("buster", "amd64")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archive, jc.DeepEquals, series.Archive{
+		Series:        "buster",
+		URL:           series.DebianArchivedURL,
+		SecurityURL:   series.DebianArchivedSecurityURL,
+		SecuritySuite: "buster/updates",
+	})
+}
+
+func (s *archiveSuite) TestErrors(c *gc.C) {
+	_, err := series.SeriesArchive("centos8", "amd64")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `package archive of CentOS series "centos8" not supported`)
+
+	_, err = series.SeriesArchive("firewolf", "amd64")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+
+	_, err = series.SeriesArchive("", "amd64")
+	c.Assert(err, jc.Satisfies, series.IsEmptyInputError)
+}
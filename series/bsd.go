@@ -0,0 +1,32 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// bsdSeriesFromUname returns the series of a BSD host from the operating
+// system name and release reported by uname, for example "OpenBSD" and
+// "7.4" give "openbsd7.4", and "NetBSD" and "10.0" give "netbsd10".
+func bsdSeriesFromUname(sysname, release string) (string, error) {
+	// Releases may carry a suffix, as in "7.4-current" or "10.0_RC1".
+	release = strings.TrimSpace(release)
+	if i := strings.IndexAny(release, "-_"); i >= 0 {
+		release = release[:i]
+	}
+	version, ok := parseConstraintVersion(release)
+	if !ok || len(version) < 2 {
+		return "unknown", errors.NotValidf("%s release %q", sysname, release)
+	}
+	switch strings.ToLower(strings.TrimSpace(sysname)) {
+	case "openbsd":
+		return "openbsd" + release, nil
+	case "netbsd":
+		return "netbsd" + strings.SplitN(release, ".", 2)[0], nil
+	}
+	return "unknown", errors.NotSupportedf("operating system %q", sysname)
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// BundleSchema is the version of the series data bundle format written by
+// WriteBundle.
+const BundleSchema = 1
+
+// The kinds of data held in a series data bundle.
+const (
+	// BundleDistroInfo is ubuntu distro-info CSV data.
+	BundleDistroInfo = "distro-info"
+	// BundleEndOfLife is endoflife.date API data of the product given by
+	// the name of the file.
+	BundleEndOfLife = "endoflife"
+	// BundleDefinitions is a series definitions document, as read by
+	// LoadDefinitions.
+	BundleDefinitions = "definitions"
+)
+
+// bundleSource is the source of the series data version recorded when a
+// bundle is loaded.
+const bundleSource = "bundle"
+
+// maxBundleSize limits how much of a bundle is read. A bundle holds a
+// handful of files of series data, each bounded by maxRemoteDataSize.
+const maxBundleSize = 4 * maxRemoteDataSize
+
+// Bundle holds series data fetched from several sources, so that it can be
+// carried into environments that can't reach them and applied there with
+// LoadBundle.
+type Bundle struct {
+	// Schema is the version of the bundle format.
+	Schema int `json:"schema"`
+	// Created is when the data was fetched.
+	Created time.Time `json:"created"`
+	// Files holds the series data, which is applied in order.
+	Files []BundleFile `json:"files"`
+}
+
+// BundleFile is the series data of one source held in a Bundle.
+type BundleFile struct {
+	// Kind is the kind of data, such as BundleDistroInfo.
+	Kind string `json:"kind"`
+	// Name identifies the data within its kind, for example the
+	// endoflife.date product.
+	Name string `json:"name"`
+	// Source records where the data was fetched from.
+	Source string `json:"source,omitempty"`
+	// Data is the series data as fetched.
+	Data []byte `json:"data"`
+}
+
+// signedBundle is the encoded form of a Bundle. The signature covers the
+// compact JSON encoding of the bundle, so that reformatting the document
+// doesn't invalidate it.
+type signedBundle struct {
+	Bundle    json.RawMessage `json:"bundle"`
+	Signature string          `json:"signature,omitempty"`
+}
+
+// WriteBundle writes the bundle to w, signed with key so that it can be
+// checked with a SignatureVerifier holding the public key. If key is nil
+// the bundle is written unsigned.
+func WriteBundle(w io.Writer, bundle Bundle, key ed25519.PrivateKey) error {
+	if bundle.Schema == 0 {
+		bundle.Schema = BundleSchema
+	}
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	signed := signedBundle{Bundle: data}
+	if key != nil {
+		if len(key) != ed25519.PrivateKeySize {
+			return errors.NotValidf("ed25519 private key of %d bytes", len(key))
+		}
+		signed.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.Trace(encoder.Encode(signed))
+}
+
+// ReadBundle reads a bundle written by WriteBundle. When verifier isn't
+// nil, a bundle that isn't signed, or whose signature doesn't verify, is
+// rejected.
+func ReadBundle(r io.Reader, verifier Verifier) (Bundle, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxBundleSize+1))
+	if err != nil {
+		return Bundle{}, errors.Trace(err)
+	}
+	if len(data) > maxBundleSize {
+		return Bundle{}, errors.Errorf("series data bundle exceeds %d bytes", maxBundleSize)
+	}
+	var signed signedBundle
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return Bundle{}, errors.Annotate(err, "decoding series data bundle")
+	}
+	if verifier != nil {
+		if signed.Signature == "" {
+			return Bundle{}, errors.Unauthorizedf("series data bundle is not signed")
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, signed.Bundle); err != nil {
+			return Bundle{}, errors.Annotate(err, "decoding series data bundle")
+		}
+		if err := verifier.Verify(compact.Bytes(), []byte(signed.Signature)); err != nil {
+			return Bundle{}, errors.Annotate(err, "verifying series data bundle")
+		}
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(signed.Bundle, &bundle); err != nil {
+		return Bundle{}, errors.Annotate(err, "decoding series data bundle")
+	}
+	if bundle.Schema != BundleSchema {
+		return Bundle{}, errors.NotSupportedf("series data bundle schema %d", bundle.Schema)
+	}
+	return bundle, nil
+}
+
+// LoadBundle reads a bundle, as ReadBundle does, and applies all of its
+// series data. Either all of the data is applied or, if any of it can't
+// be, none is.
+func LoadBundle(r io.Reader, verifier Verifier) error {
+	bundle, err := ReadBundle(r, verifier)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(update(func() error {
+		for _, file := range bundle.Files {
+			if err := applyBundleFile(file); err != nil {
+				return errors.Annotatef(err, "applying %s %q", file.Kind, file.Name)
+			}
+		}
+		updateVersionSeries()
+		latestLtsSeries = ""
+		setDataVersion(DataVersionInfo{
+			Source:    bundleSource,
+			Timestamp: bundle.Created,
+		})
+		return nil
+	}))
+}
+
+// applyBundleFile applies the series data of a bundled file. The caller
+// must hold seriesVersionsMutex.
+func applyBundleFile(file BundleFile) error {
+	switch file.Kind {
+	case BundleDistroInfo:
+		info, err := parseDistroInfo(bytes.NewReader(file.Data))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		applyDistroInfo(info)
+		return nil
+	case BundleEndOfLife:
+		osType, ok := endOfLifeProducts[file.Name]
+		if !ok {
+			return errors.NotSupportedf("endoflife.date product %q", file.Name)
+		}
+		var cycles []endOfLifeCycle
+		if err := json.Unmarshal(file.Data, &cycles); err != nil {
+			return errors.Trace(err)
+		}
+		applyEndOfLife(osType, cycles)
+		return nil
+	case BundleDefinitions:
+		doc, err := decodeDefinitions(bytes.NewReader(file.Data))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Trace(applyDefinitions(doc.Series, SourceDefinitions))
+	}
+	return errors.NotSupportedf("series data of kind %q", file.Kind)
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type bundleSuite struct {
+	testing.CleanupSuite
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+}
+
+var _ = gc.Suite(&bundleSuite{})
+
+const bundleDistroInfo = `version,codename,series,created,release,eol
+12.04 LTS,Precise Pangolin,precise,2011-10-13,2012-04-26,2017-04-26
+97.04 LTS,Picard Penguin,picard,2096-10-20,2097-04-20,2102-04-30
+`
+
+const bundleEndOfLife = `[{
+	"cycle": "96.10",
+	"codename": "Riker Raccoon",
+	"releaseDate": "2096-10-10",
+	"eol": "2097-07-10"
+}]`
+
+func (s *bundleSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2098, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+	s.private = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	s.public = s.private.Public().(ed25519.PublicKey)
+}
+
+func (s *bundleSuite) bundle(files ...series.BundleFile) series.Bundle {
+	return series.Bundle{
+		Created: time.Date(2097, 12, 1, 0, 0, 0, 0, time.UTC),
+		Files:   files,
+	}
+}
+
+func (s *bundleSuite) write(c *gc.C, bundle series.Bundle, key ed25519.PrivateKey) []byte {
+	var buf bytes.Buffer
+	err := series.WriteBundle(&buf, bundle, key)
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *bundleSuite) TestLoadBundle(c *gc.C) {
+	data := s.write(c, s.bundle(
+		series.BundleFile{Kind: series.BundleDistroInfo, Name: "ubuntu", Data: []byte(bundleDistroInfo)},
+		series.BundleFile{Kind: series.BundleEndOfLife, Name: "ubuntu", Data: []byte(bundleEndOfLife)},
+	), s.private)
+
+	verifier := series.SignatureVerifier{Keys: []ed25519.PublicKey{s.public}}
+	err := series.LoadBundle(bytes.NewReader(data), verifier)
+	c.Assert(err, jc.ErrorIsNil)
+
+	picard, err := series.GetSeries("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(picard.Version, gc.Equals, "97.04")
+	c.Check(picard.LTS, jc.IsTrue)
+	riker, err := series.GetSeries("riker")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(riker.Version, gc.Equals, "96.10")
+	c.Check(series.DataVersion(), jc.DeepEquals, series.DataVersionInfo{
+		Source:    "bundle",
+		Timestamp: time.Date(2097, 12, 1, 0, 0, 0, 0, time.UTC),
+	})
+}
+
+func (s *bundleSuite) TestReadBundleVerification(c *gc.C) {
+	bundle := s.bundle(series.BundleFile{Kind: series.BundleDistroInfo, Name: "ubuntu", Data: []byte(bundleDistroInfo)})
+	verifier := series.SignatureVerifier{Keys: []ed25519.PublicKey{s.public}}
+
+	read, err := series.ReadBundle(bytes.NewReader(s.write(c, bundle, s.private)), verifier)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(read.Schema, gc.Equals, series.BundleSchema)
+	c.Check(read.Files, jc.DeepEquals, bundle.Files)
+
+	_, err = series.ReadBundle(bytes.NewReader(s.write(c, bundle, nil)), verifier)
+	c.Check(err, jc.Satisfies, errors.IsUnauthorized)
+	_, err = series.ReadBundle(bytes.NewReader(s.write(c, bundle, nil)), nil)
+	c.Check(err, jc.ErrorIsNil)
+
+	tampered := bytes.Replace(s.write(c, bundle, s.private), []byte(`"ubuntu"`), []byte(`"debian"`), 1)
+	_, err = series.ReadBundle(bytes.NewReader(tampered), verifier)
+	c.Check(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *bundleSuite) TestLoadBundleIsAtomic(c *gc.C) {
+	data := s.write(c, s.bundle(
+		series.BundleFile{Kind: series.BundleDistroInfo, Name: "ubuntu", Data: []byte(bundleDistroInfo)},
+		series.BundleFile{Kind: "floppy", Name: "disk"},
+	), nil)
+
+	err := series.LoadBundle(bytes.NewReader(data), nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `applying floppy "disk": series data of kind "floppy" not supported`)
+	_, err = series.GetSeries("picard")
+	c.Check(err, gc.NotNil)
+}
+
+func (s *bundleSuite) TestReadBundleSchema(c *gc.C) {
+	data := s.write(c, series.Bundle{Schema: 2}, nil)
+	_, err := series.ReadBundle(bytes.NewReader(data), nil)
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Cache persists the series data fetched by an HTTPSource. Embedders can
+// implement it on shared storage, such as etcd or S3, so that the
+// controllers of a cluster share one fetched dataset rather than each
+// fetching it independently. Implementations must be safe for concurrent
+// use.
+type Cache interface {
+	// Get returns the entry cached under the key. An error satisfying
+	// errors.IsNotFound is returned if there is none.
+	Get(ctx context.Context, key string) (CacheEntry, error)
+	// Put caches the entry under the key, replacing any existing entry.
+	Put(ctx context.Context, key string, entry CacheEntry) error
+}
+
+// CacheEntry is series data held in a Cache, with the validators needed to
+// revalidate it.
+type CacheEntry struct {
+	// Data is the fetched series data.
+	Data []byte
+	// ETag and LastModified are the validators of the response that held
+	// the data, if the server gave any.
+	ETag         string
+	LastModified string
+	// Fetched is when the data was last fetched or revalidated.
+	Fetched time.Time
+}
+
+// DirCache is a Cache holding its entries as files in a directory. Several
+// sources may share a directory.
+type DirCache string
+
+// cacheMetadata holds the validators of an entry of a DirCache.
+type cacheMetadata struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last-modified,omitempty"`
+	Fetched      time.Time `json:"fetched,omitempty"`
+}
+
+// path returns the path of a cache file for the key.
+func (dir DirCache) path(key, ext string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(string(dir), fmt.Sprintf("series-%s.%s", hex.EncodeToString(sum[:8]), ext))
+}
+
+// Get is part of the Cache interface.
+func (dir DirCache) Get(_ context.Context, key string) (CacheEntry, error) {
+	data, err := ioutil.ReadFile(dir.path(key, "data"))
+	if os.IsNotExist(err) {
+		return CacheEntry{}, errors.NotFoundf("cached series data for %q", key)
+	} else if err != nil {
+		return CacheEntry{}, errors.Trace(err)
+	}
+	var meta cacheMetadata
+	metaData, err := ioutil.ReadFile(dir.path(key, "meta"))
+	if err == nil {
+		err = json.Unmarshal(metaData, &meta)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		// The data is still usable, it just can't be revalidated.
+		logger.Debugf("ignoring series data cache metadata for %s: %v", key, err)
+		meta = cacheMetadata{}
+	}
+	return CacheEntry{
+		Data:         data,
+		ETag:         meta.ETag,
+		LastModified: meta.LastModified,
+		Fetched:      meta.Fetched,
+	}, nil
+}
+
+// Put is part of the Cache interface.
+func (dir DirCache) Put(_ context.Context, key string, entry CacheEntry) error {
+	if err := os.MkdirAll(string(dir), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	metaData, err := json.Marshal(cacheMetadata{
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		Fetched:      entry.Fetched,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := writeFileAtomic(dir.path(key, "data"), entry.Data); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(writeFileAtomic(dir.path(key, "meta"), metaData))
+}
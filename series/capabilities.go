@@ -0,0 +1,70 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// WorkloadCapabilities describes the kinds of Windows workload a series can
+// run. Nano Server and Hyper-V Server lack much of a full Windows Server
+// installation, so schedulers should check the capability a workload
+// needs rather than whether the series is a nano series.
+type WorkloadCapabilities struct {
+	// DotNetFramework is true if the full .NET Framework is available.
+	// Nano Server only runs .NET Core.
+	DotNetFramework bool
+	// GUI is true if the series has a graphical desktop.
+	GUI bool
+	// MSI is true if packages can be installed with Windows Installer.
+	MSI bool
+	// LocalLogon is true if users can log on at the console.
+	LocalLogon bool
+}
+
+var (
+	// fullCapabilities are those of a full Windows Server installation.
+	fullCapabilities = WorkloadCapabilities{
+		DotNetFramework: true,
+		GUI:             true,
+		MSI:             true,
+		LocalLogon:      true,
+	}
+
+	// nanoCapabilities are those of Nano Server, which is managed
+	// remotely and only runs .NET Core workloads.
+	nanoCapabilities = WorkloadCapabilities{}
+)
+
+// windowsCapabilities holds the capabilities of the Windows series that
+// are neither full installations nor nano series.
+var windowsCapabilities = map[string]WorkloadCapabilities{
+	// Hyper-V Server has no desktop, but otherwise runs what Server
+	// Core does.
+	"win2012hv":   {DotNetFramework: true, MSI: true, LocalLogon: true},
+	"win2012hvr2": {DotNetFramework: true, MSI: true, LocalLogon: true},
+	"win2016hv":   {DotNetFramework: true, MSI: true, LocalLogon: true},
+}
+
+// Capabilities returns the workload capabilities of a known series.
+// Capabilities only describe Windows workloads, so none are reported for
+// series of other operating systems.
+func Capabilities(series string) (WorkloadCapabilities, error) {
+	osType, err := GetOSFromSeries(series)
+	if err != nil {
+		return WorkloadCapabilities{}, errors.Trace(err)
+	}
+	if osType != os.Windows {
+		return WorkloadCapabilities{}, nil
+	}
+	name := FormatSeries(series)
+	if caps, ok := windowsCapabilities[name]; ok {
+		return caps, nil
+	}
+	if IsWindowsNano(name) {
+		return nanoCapabilities, nil
+	}
+	return fullCapabilities, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type capabilitiesSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&capabilitiesSuite{})
+
+func (s *capabilitiesSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *capabilitiesSuite) TestCapabilities(c *gc.C) {
+	full := series.WorkloadCapabilities{DotNetFramework: true, GUI: true, MSI: true, LocalLogon: true}
+	for i, test := range []struct {
+		series   string
+		expected series.WorkloadCapabilities
+	}{{
+		series:   "win2019",
+		expected: full,
+	}, {
+		series:   "Win2012R2",
+		expected: full,
+	}, {
+		series:   "win2016nano",
+		expected: series.WorkloadCapabilities{},
+	}, {
+		series:   "win2016hv",
+		expected: series.WorkloadCapabilities{DotNetFramework: true, MSI: true, LocalLogon: true},
+	}, {
+		series:   "focal",
+		expected: series.WorkloadCapabilities{},
+	}} {
+		c.Logf("test %d: %s", i, test.series)
+		caps, err := series.Capabilities(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caps, jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *capabilitiesSuite) TestCapabilitiesUnknownSeries(c *gc.C) {
+	_, err := series.Capabilities("sulu")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ChangelogEntry is an entry of the changelog written to the path given to
+// Registry.SetChangelogPath, recording one update of the series data.
+type ChangelogEntry struct {
+	// Timestamp is when the update was made.
+	Timestamp time.Time `json:"timestamp"`
+	// Source is the source of the series data after the update, as
+	// reported by DataVersion.
+	Source string `json:"source"`
+	// Version is the version declared by the series data after the
+	// update, if any.
+	Version string `json:"version,omitempty"`
+	// Changes holds how the known series changed.
+	Changes ChangeSet `json:"changes"`
+}
+
+// SetChangelogPath sets the path of a changelog that every update of the
+// series data that changes the known series is appended to, as a line of
+// JSON holding a ChangelogEntry, so that operators can audit when and how
+// the series data changed. Passing "" stops writing the changelog, which
+// is the default. Failing to write the changelog doesn't fail the update,
+// but is logged.
+func (r *Registry) SetChangelogPath(path string) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	r.changelogPath = path
+}
+
+// ChangelogPath returns the path the changelog is written to, or "" if it
+// isn't written.
+func (r *Registry) ChangelogPath() string {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	return r.changelogPath
+}
+
+// writeChangelog appends an entry for the changes to the changelog, if
+// there is one. The caller must hold seriesVersionsMutex, so that entries
+// are written in the order the updates were made.
+func (r *Registry) writeChangelog(changes ChangeSet) {
+	if r.changelogPath == "" || changes.Empty() {
+		return
+	}
+	entry := ChangelogEntry{
+		Timestamp: time.Now().UTC(),
+		Source:    dataVersion.Source,
+		Version:   dataVersion.Version,
+		Changes:   changes,
+	}
+	if err := appendChangelogEntry(r.changelogPath, entry); err != nil {
+		logger.Warningf("cannot write series changelog %s: %v", r.changelogPath, err)
+	}
+}
+
+func appendChangelogEntry(path string, entry ChangelogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return errors.Trace(err)
+	}
+	return errors.Trace(f.Close())
+}
+
+// ReadChangelog reads the entries of a changelog written by the registry,
+// oldest first.
+func ReadChangelog(r io.Reader) ([]ChangelogEntry, error) {
+	var entries []ChangelogEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxRemoteDataSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry ChangelogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, errors.Annotatef(err, "changelog line %d", line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, errors.Trace(scanner.Err())
+}
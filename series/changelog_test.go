@@ -0,0 +1,100 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type changelogSuite struct {
+	testing.CleanupSuite
+	path string
+}
+
+var _ = gc.Suite(&changelogSuite{})
+
+func (s *changelogSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	// Load the distro-info data first, so that it isn't in the changelog.
+	series.CurrentSnapshot()
+	s.path = filepath.Join(c.MkDir(), "changelog.jsonl")
+	series.DefaultRegistry().SetChangelogPath(s.path)
+}
+
+func (s *changelogSuite) readChangelog(c *gc.C) []series.ChangelogEntry {
+	f, err := os.Open(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	entries, err := series.ReadChangelog(f)
+	c.Assert(err, jc.ErrorIsNil)
+	return entries
+}
+
+func (s *changelogSuite) TestUpdatesRecorded(c *gc.C) {
+	c.Check(series.DefaultRegistry().ChangelogPath(), gc.Equals, s.path)
+	start := time.Now().UTC()
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "version": "7", "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04"}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.LoadDefinitions(strings.NewReader(`{"schema": 1, "version": "8", "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04", "lts": true}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	entries := s.readChangelog(c)
+	c.Assert(entries, gc.HasLen, 2)
+	c.Check(entries[0].Timestamp.Before(start), jc.IsFalse)
+	c.Check(entries[0].Source, gc.Equals, "definitions")
+	c.Check(entries[0].Version, gc.Equals, "7")
+	c.Check(entries[0].Changes, jc.DeepEquals, series.ChangeSet{Added: []series.Name{"picard"}})
+	c.Check(entries[1].Version, gc.Equals, "8")
+	c.Check(entries[1].Changes, jc.DeepEquals, series.ChangeSet{
+		Updated: []series.Name{"picard"},
+		Fields: []series.FieldChange{
+			{Series: "picard", Field: "LTS", Old: "false", New: "true"},
+		},
+	})
+}
+
+func (s *changelogSuite) TestNoChangesNotRecorded(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": []}`))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(s.path)
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (s *changelogSuite) TestDisabled(c *gc.C) {
+	series.DefaultRegistry().SetChangelogPath("")
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04"}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(s.path)
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (s *changelogSuite) TestWriteFailureDoesNotFailUpdate(c *gc.C) {
+	series.DefaultRegistry().SetChangelogPath(filepath.Join(c.MkDir(), "missing", "changelog.jsonl"))
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04"}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *changelogSuite) TestReadChangelogInvalid(c *gc.C) {
+	_, err := series.ReadChangelog(strings.NewReader("{}\n\nnot json\n"))
+	c.Check(err, gc.ErrorMatches, `changelog line 3: .*`)
+}
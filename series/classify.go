@@ -0,0 +1,154 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// MatchKind describes how ClassifySeries matched its input to a series.
+type MatchKind string
+
+const (
+	// MatchNone is reported when the input doesn't match any series.
+	MatchNone MatchKind = "none"
+	// MatchExact is reported when the input is a series name.
+	MatchExact MatchKind = "exact"
+	// MatchAlias is reported when the input names a series in another
+	// way: with different case or surrounding whitespace, by version, or
+	// by the codename of an ubuntu series.
+	MatchAlias MatchKind = "alias"
+	// MatchFuzzy is reported when the input is a close misspelling of a
+	// single series name.
+	MatchFuzzy MatchKind = "fuzzy"
+)
+
+// maxSuggestionDistance is the largest edit distance between an input and
+// a series name for the series to be suggested.
+const maxSuggestionDistance = 2
+
+// Classification is the result of ClassifySeries.
+type Classification struct {
+	OS     os.OSType
+	Series Name
+	Match  MatchKind
+	// Suggestions holds the series with names close to the input, closest
+	// first, if the input wasn't an exact or alias match.
+	Suggestions []Name
+}
+
+// ClassifySeries determines the series meant by input, which is usually
+// entered by a user, and how it was matched. Inputs that aren't series
+// names are matched as aliases where possible, then as misspellings. If
+// nothing matches, an error satisfying IsUnknownOSForSeriesError is
+// returned along with a Classification holding any suggestions, for
+// reporting "did you mean" errors.
+func ClassifySeries(input string) (Classification, error) {
+	if osType, err := GetOSFromSeries(input); err == nil {
+		match := MatchAlias
+		if input == FormatSeries(input) {
+			match = MatchExact
+		}
+		return Classification{OS: osType, Series: Name(FormatSeries(input)), Match: match}, nil
+	} else if IsRetiredSeriesError(err) {
+		return Classification{Match: MatchNone}, errors.Trace(err)
+	}
+	if name, err := canonicalSeries(input); err == nil {
+		return classified(name, MatchAlias)
+	}
+	if series, err := resolveVersionSeries(input); err == nil {
+		return classified(Name(series), MatchAlias)
+	}
+
+	name := FormatSeries(input)
+	if name == "" {
+		return Classification{Match: MatchNone}, errors.Trace(unknownOSForSeriesError{series: input})
+	}
+	err := unknownSeries(input, CurrentSnapshot().get().series)
+	suggestions := SeriesSuggestions(err)
+	result := Classification{Match: MatchNone}
+	for _, suggestion := range suggestions {
+		result.Suggestions = append(result.Suggestions, Name(suggestion))
+	}
+	// Only a single closest series is taken as the intended one, and only
+	// for inputs long enough that the misspelling is unlikely to be another
+	// word entirely.
+	if len(suggestions) > 0 && len(name) > maxSuggestionDistance*2 {
+		closest := editDistance(name, suggestions[0])
+		if len(suggestions) == 1 || editDistance(name, suggestions[1]) > closest {
+			fuzzy, err := classified(Name(suggestions[0]), MatchFuzzy)
+			fuzzy.Suggestions = result.Suggestions
+			return fuzzy, errors.Trace(err)
+		}
+	}
+	return result, errors.Trace(err)
+}
+
+// classified returns the classification of a known series.
+func classified(series Name, match MatchKind) (Classification, error) {
+	osType, err := GetOSFromSeries(string(series))
+	if err != nil {
+		return Classification{Match: MatchNone}, errors.Trace(err)
+	}
+	return Classification{OS: osType, Series: series, Match: match}, nil
+}
+
+// suggestSeries returns the names in known that are within
+// maxSuggestionDistance edits of name, closest first and then sorted.
+func suggestSeries(name string, known []string) []string {
+	if name == "" {
+		return nil
+	}
+	distances := make(map[string]int)
+	var suggestions []string
+	for _, candidate := range known {
+		if distance := editDistance(name, candidate); distance <= maxSuggestionDistance {
+			distances[candidate] = distance
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if distances[a] != distances[b] {
+			return distances[a] < distances[b]
+		}
+		return a < b
+	})
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/collections/set"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type classifySuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&classifySuite{})
+
+func (s *classifySuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *classifySuite) TestClassifySeries(c *gc.C) {
+	for i, test := range []struct {
+		input  string
+		series series.Name
+		os     os.OSType
+		match  series.MatchKind
+	}{
+		{"focal", "focal", os.Ubuntu, series.MatchExact},
+		{"win2019", "win2019", os.Windows, series.MatchExact},
+		{" Focal", "focal", os.Ubuntu, series.MatchAlias},
+		{"20.04", "focal", os.Ubuntu, series.MatchAlias},
+		{"Focal Fossa", "focal", os.Ubuntu, series.MatchAlias},
+		{"bionc", "bionic", os.Ubuntu, series.MatchFuzzy},
+		{"xenail", "xenial", os.Ubuntu, series.MatchFuzzy},
+	} {
+		c.Logf("test %d: %q", i, test.input)
+		result, err := series.ClassifySeries(test.input)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(result.Series, gc.Equals, test.series)
+		c.Check(result.OS, gc.Equals, test.os)
+		c.Check(result.Match, gc.Equals, test.match)
+	}
+}
+
+func (s *classifySuite) TestClassifySeriesSuggestions(c *gc.C) {
+	result, err := series.ClassifySeries("win201")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+	c.Assert(result.Match, gc.Equals, series.MatchNone)
+	suggestions := set.NewStrings()
+	for _, name := range result.Suggestions {
+		suggestions.Add(name.String())
+	}
+	c.Assert(suggestions.Contains("win2012"), jc.IsTrue)
+	c.Assert(suggestions.Contains("win2019"), jc.IsTrue)
+
+	result, err = series.ClassifySeries("beos")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+	c.Assert(result.Match, gc.Equals, series.MatchNone)
+	c.Assert(result.Suggestions, gc.HasLen, 0)
+}
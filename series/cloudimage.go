@@ -0,0 +1,203 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// The confidence contributed by each kind of evidence found in cloud image
+// metadata. An ubuntu image naming both its codename and version is
+// guessed with full confidence.
+const (
+	codenameConfidence = 0.6
+	versionConfidence  = 0.4
+	productConfidence  = 0.8
+	publisherBonus     = 0.1
+)
+
+// SeriesGuess is a series guessed from cloud image metadata.
+type SeriesGuess struct {
+	Series string
+	OS     os.OSType
+	// Confidence ranges from 0 to 1, which is only reached when several
+	// independent parts of the metadata agree on the series.
+	Confidence float64
+	// Evidence describes the parts of the metadata the guess is based on.
+	Evidence []string
+}
+
+// AzureImageReference holds the imageReference of an Azure virtual
+// machine.
+type AzureImageReference struct {
+	Publisher string
+	Offer     string
+	SKU       string
+	Version   string
+}
+
+// azurePublishers maps the publishers of Azure marketplace images to the
+// OS of their images.
+var azurePublishers = map[string]os.OSType{
+	"canonical":               os.Ubuntu,
+	"microsoftwindowsserver":  os.Windows,
+	"microsoftwindowsdesktop": os.Windows,
+	"openlogic":               os.CentOS,
+	"suse":                    os.OpenSUSE,
+}
+
+// GuessSeriesFromEC2Image guesses the series of an EC2 instance from the
+// name and description of its AMI, for example
+// "ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20230516". An
+// error satisfying errors.IsNotFound is returned if no series is evident.
+func GuessSeriesFromEC2Image(name, description string) (SeriesGuess, error) {
+	guess, err := guessSeries(name + " " + description)
+	return guess, errors.Annotatef(err, "EC2 image %q", name)
+}
+
+// GuessSeriesFromAzureImage guesses the series of an Azure virtual machine
+// from its image reference. An error satisfying errors.IsNotFound is
+// returned if no series is evident.
+func GuessSeriesFromAzureImage(ref AzureImageReference) (SeriesGuess, error) {
+	guess, err := guessSeries(ref.Offer + " " + ref.SKU)
+	if err != nil {
+		return SeriesGuess{}, errors.Annotatef(err, "Azure image %s:%s:%s", ref.Publisher, ref.Offer, ref.SKU)
+	}
+	if osType, ok := azurePublishers[strings.ToLower(ref.Publisher)]; ok && osType == guess.OS {
+		guess.Confidence = capConfidence(guess.Confidence + publisherBonus)
+		guess.Evidence = append(guess.Evidence, fmt.Sprintf("%s images published by %s", osType, ref.Publisher))
+	}
+	return guess, nil
+}
+
+// GuessSeriesFromGCEImage guesses the series of a GCE instance from the
+// name or URL of its image, for example
+// "projects/ubuntu-os-cloud/global/images/ubuntu-2204-jammy-v20230517".
+// An error satisfying errors.IsNotFound is returned if no series is
+// evident.
+func GuessSeriesFromGCEImage(image string) (SeriesGuess, error) {
+	guess, err := guessSeries(image[strings.LastIndex(image, "/")+1:])
+	return guess, errors.Annotatef(err, "GCE image %q", image)
+}
+
+var (
+	ubuntuVersionPattern  = regexp.MustCompile(`(?:^|[^0-9])(\d{2})[._]?(04|10)(?:[^0-9]|$)`)
+	centosPattern         = regexp.MustCompile(`centos[^a-z0-9]*(stream[^a-z0-9]*)?(?:linux[^a-z0-9]*)?(\d+)`)
+	windowsServerPattern  = regexp.MustCompile(`windows[^a-z0-9]*server[^a-z0-9]*(\d{4})([^a-z0-9]*r2)?`)
+	windowsDesktopPattern = regexp.MustCompile(`windows[^a-z0-9]*(10|81|8\.1|8|7)(?:[^0-9]|$)`)
+)
+
+// guessSeries guesses the series described by text, from the series
+// codenames, versions and product names it holds.
+func guessSeries(text string) (SeriesGuess, error) {
+	text = strings.ToLower(text)
+	scores := make(map[string]float64)
+	evidence := make(map[string][]string)
+	add := func(series string, confidence float64, what string) {
+		scores[series] += confidence
+		evidence[series] = append(evidence[series], what)
+	}
+
+	seriesVersionsMutex.Lock()
+	updateSeriesVersionsOnce()
+	for name := range ubuntuSeries {
+		if containsWord(text, name) {
+			add(name, codenameConfidence, fmt.Sprintf("codename %q", name))
+		}
+	}
+	if strings.Contains(text, "ubuntu") {
+		for _, match := range ubuntuVersionPattern.FindAllStringSubmatch(text, -1) {
+			version := match[1] + "." + match[2]
+			for _, series := range versionSeries[version] {
+				if _, ok := ubuntuSeries[series]; ok {
+					add(series, versionConfidence, fmt.Sprintf("ubuntu version %s", version))
+				}
+			}
+		}
+	}
+	if match := centosPattern.FindStringSubmatch(text); match != nil {
+		series := "centos" + match[2]
+		if match[1] != "" && series == "centos8" {
+			series = "centos8-stream"
+		}
+		add(series, productConfidence, fmt.Sprintf("product %q", strings.TrimSpace(match[0])))
+	}
+	if match := windowsServerPattern.FindStringSubmatch(text); match != nil {
+		series := "win" + match[1]
+		if match[2] != "" {
+			series += "r2"
+		}
+		add(series, productConfidence, fmt.Sprintf("product %q", strings.TrimSpace(match[0])))
+	} else if match := windowsDesktopPattern.FindStringSubmatch(text); match != nil {
+		series := "win" + strings.Replace(match[1], ".", "", 1)
+		add(series, productConfidence, fmt.Sprintf("product %q", strings.TrimSpace(match[0])))
+	}
+
+	var candidates []SeriesGuess
+	for series, score := range scores {
+		osType, err := getOSFromSeries(series)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, SeriesGuess{
+			Series:     series,
+			OS:         osType,
+			Confidence: capConfidence(score),
+			Evidence:   evidence[series],
+		})
+	}
+	seriesVersionsMutex.Unlock()
+
+	if len(candidates) == 0 {
+		return SeriesGuess{}, errors.NotFoundf("series")
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Series < candidates[j].Series
+	})
+	guess := candidates[0]
+	if len(candidates) > 1 {
+		// Conflicting evidence makes any guess less certain.
+		guess.Confidence /= 2
+		guess.Evidence = append(guess.Evidence, fmt.Sprintf("conflicts with series %q", candidates[1].Series))
+	}
+	return guess, nil
+}
+
+// containsWord returns true if text holds word delimited by characters
+// other than letters.
+func containsWord(text, word string) bool {
+	for start := 0; ; {
+		i := strings.Index(text[start:], word)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(word)
+		if (i == 0 || !isLetter(text[i-1])) && (end == len(text) || !isLetter(text[end])) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z'
+}
+
+// capConfidence caps a confidence at 1.
+func capConfidence(confidence float64) float64 {
+	if confidence > 1 {
+		return 1
+	}
+	return confidence
+}
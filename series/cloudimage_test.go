@@ -0,0 +1,94 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type cloudImageSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&cloudImageSuite{})
+
+func (s *cloudImageSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *cloudImageSuite) TestGuessSeriesFromEC2Image(c *gc.C) {
+	guess, err := series.GuessSeriesFromEC2Image(
+		"ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20230516",
+		"Canonical, Ubuntu, 22.04 LTS, amd64 jammy image build on 2023-05-16",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(guess.Series, gc.Equals, "jammy")
+	c.Assert(guess.OS, gc.Equals, os.Ubuntu)
+	c.Assert(guess.Confidence, gc.Equals, 1.0)
+
+	guess, err = series.GuessSeriesFromEC2Image("CentOS Linux 7 x86_64 HVM EBS ENA 2002_01", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(guess.Series, gc.Equals, "centos7")
+	c.Assert(guess.Confidence, gc.Equals, 0.8)
+
+	guess, err = series.GuessSeriesFromEC2Image("Windows_Server-2012-R2_RTM-English-64Bit-Base-2023.05.10", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(guess.Series, gc.Equals, "win2012r2")
+	c.Assert(guess.OS, gc.Equals, os.Windows)
+}
+
+func (s *cloudImageSuite) TestGuessSeriesFromAzureImage(c *gc.C) {
+	guess, err := series.GuessSeriesFromAzureImage(series.AzureImageReference{
+		Publisher: "Canonical",
+		Offer:     "UbuntuServer",
+		SKU:       "18.04-LTS",
+		Version:   "latest",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(guess.Series, gc.Equals, "bionic")
+	c.Assert(guess.Confidence, gc.Equals, 0.5)
+	c.Assert(guess.Evidence, jc.DeepEquals, []string{
+		"ubuntu version 18.04",
+		"Ubuntu images published by Canonical",
+	})
+
+	guess, err = series.GuessSeriesFromAzureImage(series.AzureImageReference{
+		Publisher: "MicrosoftWindowsServer",
+		Offer:     "WindowsServer",
+		SKU:       "2019-Datacenter",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(guess.Series, gc.Equals, "win2019")
+	c.Assert(guess.Confidence, gc.Equals, 0.9)
+}
+
+func (s *cloudImageSuite) TestGuessSeriesFromGCEImage(c *gc.C) {
+	guess, err := series.GuessSeriesFromGCEImage("projects/ubuntu-os-cloud/global/images/ubuntu-2004-focal-v20230517")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(guess.Series, gc.Equals, "focal")
+	c.Assert(guess.Confidence, gc.Equals, 1.0)
+	c.Assert(guess.Evidence, jc.DeepEquals, []string{`codename "focal"`, "ubuntu version 20.04"})
+}
+
+func (s *cloudImageSuite) TestGuessSeriesConflicting(c *gc.C) {
+	guess, err := series.GuessSeriesFromGCEImage("ubuntu-2004-jammy-v20230517")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(guess.Series, gc.Equals, "jammy")
+	c.Assert(guess.Confidence, gc.Equals, 0.3)
+	c.Assert(guess.Evidence, jc.DeepEquals, []string{`codename "jammy"`, `conflicts with series "focal"`})
+}
+
+func (s *cloudImageSuite) TestGuessSeriesNotFound(c *gc.C) {
+	_, err := series.GuessSeriesFromGCEImage("projects/debian-cloud/global/images/debian-12-bookworm-v20230515")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `GCE image ".*debian-12-bookworm-v20230515": series not found`)
+}
@@ -0,0 +1,276 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// CompatibilityPolicy determines which host series a workload built for a
+// series can run on.
+type CompatibilityPolicy int
+
+const (
+	// CompatibleExact allows only hosts of the workload's own series.
+	// This is the default.
+	CompatibleExact CompatibilityPolicy = iota
+	// CompatibleLTSOrNewer allows hosts of the workload's series and of
+	// later LTS releases of the same operating system, for example a
+	// focal workload on jammy or noble hosts. Operating systems without
+	// LTS releases only allow the workload's series.
+	CompatibleLTSOrNewer
+	// CompatibleFamily allows hosts of any operating system in the
+	// workload's family with the same major version, for example a rhel8
+	// workload on centos8, rocky8 or alma8 hosts. Operating systems
+	// without a family only allow the workload's series.
+	CompatibleFamily
+)
+
+func (p CompatibilityPolicy) String() string {
+	switch p {
+	case CompatibleExact:
+		return "exact"
+	case CompatibleLTSOrNewer:
+		return "lts-or-newer"
+	case CompatibleFamily:
+		return "family"
+	}
+	return "unknown"
+}
+
+// Validate returns an error satisfying errors.IsNotValid if the policy
+// isn't one of the defined policies.
+func (p CompatibilityPolicy) Validate() error {
+	switch p {
+	case CompatibleExact, CompatibleLTSOrNewer, CompatibleFamily:
+		return nil
+	}
+	return errors.NotValidf("compatibility policy %d", int(p))
+}
+
+// osFamilies groups the operating systems whose releases of the same
+// major version are binary compatible, as the enterprise linux rebuilds
+// are with RHEL.
+var osFamilies = map[os.OSType]string{
+	os.RHEL:      "el",
+	os.CentOS:    "el",
+	os.Rocky:     "el",
+	os.AlmaLinux: "el",
+}
+
+// majorVersionPattern matches the major version in the version of a
+// series, such as the 8 of "rhel8" or "centos8-stream".
+var majorVersionPattern = regexp.MustCompile(`\d+`)
+
+// compatibilityPair is a workload series that is allowed to run on a host
+// series.
+type compatibilityPair struct {
+	workload string
+	host     string
+}
+
+// compatibilityRules holds the compatibility rules registered with a
+// Registry.
+type compatibilityRules struct {
+	// series holds the policies of workload series.
+	series map[string]CompatibilityPolicy
+	// os holds the policies of the workload series of operating systems,
+	// which apply to series without a policy of their own.
+	os map[os.OSType]CompatibilityPolicy
+	// allowed holds the pairs allowed regardless of policy.
+	allowed map[compatibilityPair]bool
+}
+
+func (c compatibilityRules) copy() compatibilityRules {
+	result := compatibilityRules{
+		series:  make(map[string]CompatibilityPolicy, len(c.series)),
+		os:      make(map[os.OSType]CompatibilityPolicy, len(c.os)),
+		allowed: make(map[compatibilityPair]bool, len(c.allowed)),
+	}
+	for k, v := range c.series {
+		result.series[k] = v
+	}
+	for k, v := range c.os {
+		result.os[k] = v
+	}
+	for k, v := range c.allowed {
+		result.allowed[k] = v
+	}
+	return result
+}
+
+// SetCompatibilityPolicy sets which host series workloads built for the
+// series can run on, overriding any policy of its operating system.
+func (r *Registry) SetCompatibilityPolicy(workload string, policy CompatibilityPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	name, err := canonicalSeries(workload)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	r.compatibility = r.compatibility.copy()
+	r.compatibility.series[string(name)] = policy
+	return nil
+}
+
+// SetOSCompatibilityPolicy sets which host series workloads built for
+// series of the operating system can run on, for series without a policy
+// of their own.
+func (r *Registry) SetOSCompatibilityPolicy(osType os.OSType, policy CompatibilityPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if osType == os.Unknown {
+		return errors.NotValidf("compatibility policy of unknown OS")
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	r.compatibility = r.compatibility.copy()
+	r.compatibility.os[osType] = policy
+	return nil
+}
+
+// AllowCompatibility allows workloads built for the workload series to
+// run on hosts of the host series, whatever the policy of the workload
+// series, for exceptions that no policy describes.
+func (r *Registry) AllowCompatibility(workload, host string) error {
+	workloadName, err := canonicalSeries(workload)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hostName, err := canonicalSeries(host)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	r.compatibility = r.compatibility.copy()
+	r.compatibility.allowed[compatibilityPair{workload: string(workloadName), host: string(hostName)}] = true
+	return nil
+}
+
+// CompatibilityPolicy returns the policy that applies to workloads built
+// for the series.
+func (r *Registry) CompatibilityPolicy(workload string) (CompatibilityPolicy, error) {
+	name, err := canonicalSeries(workload)
+	if err != nil {
+		return CompatibleExact, errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	return r.compatibilityPolicyLocked(string(name), knownSeries()[string(name)].OS), nil
+}
+
+// compatibilityPolicyLocked returns the policy of the workload series of
+// the operating system. The caller must hold seriesVersionsMutex.
+func (r *Registry) compatibilityPolicyLocked(workload string, osType os.OSType) CompatibilityPolicy {
+	if policy, ok := r.compatibility.series[workload]; ok {
+		return policy
+	}
+	return r.compatibility.os[osType]
+}
+
+// compatibleLocked reports whether workloads built for the workload series
+// can run on hosts of the host series. The caller must hold
+// seriesVersionsMutex.
+func (r *Registry) compatibleLocked(workload, host string, known map[string]seriesRecord) bool {
+	if workload == host || r.compatibility.allowed[compatibilityPair{workload: workload, host: host}] {
+		return true
+	}
+	workloadRecord, hostRecord := known[workload], known[host]
+	switch r.compatibilityPolicyLocked(workload, workloadRecord.OS) {
+	case CompatibleLTSOrNewer:
+		if workloadRecord.OS != hostRecord.OS || !hostRecord.LTS {
+			return false
+		}
+		workloadVersion, ok := parseConstraintVersion(strings.TrimSuffix(workloadRecord.Version, " LTS"))
+		if !ok {
+			return false
+		}
+		hostVersion, ok := parseConstraintVersion(strings.TrimSuffix(hostRecord.Version, " LTS"))
+		return ok && compareVersions(hostVersion, workloadVersion) > 0
+	case CompatibleFamily:
+		family, ok := osFamilies[workloadRecord.OS]
+		if !ok || osFamilies[hostRecord.OS] != family {
+			return false
+		}
+		workloadMajor, ok := majorVersion(workloadRecord.Version)
+		if !ok {
+			return false
+		}
+		hostMajor, ok := majorVersion(hostRecord.Version)
+		return ok && hostMajor == workloadMajor
+	}
+	return false
+}
+
+// majorVersion returns the major version in the version of a series.
+func majorVersion(version string) (int, bool) {
+	match := majorVersionPattern.FindString(version)
+	if match == "" {
+		return 0, false
+	}
+	major, err := strconv.Atoi(match)
+	return major, err == nil
+}
+
+// CanRunOn reports whether a workload built for the workload series can
+// run on a host of the host series, according to the compatibility rules
+// of the default registry. A workload can always run on hosts of its own
+// series.
+func CanRunOn(workload, host string) (bool, error) {
+	workloadName, err := canonicalSeries(workload)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	hostName, err := canonicalSeries(host)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	return defaultRegistry.compatibleLocked(string(workloadName), string(hostName), knownSeries()), nil
+}
+
+// CompatibleHostSeries returns the known series, sorted, whose hosts a
+// workload built for the series can run on, according to the
+// compatibility rules of the default registry. Series rejected as retired
+// are left out.
+func CompatibleHostSeries(workload string) ([]string, error) {
+	name, err := canonicalSeries(workload)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	known := knownSeries()
+	var hosts []string
+	for host := range known {
+		if defaultRegistry.checkRetiredLocked(host) != nil {
+			continue
+		}
+		if defaultRegistry.compatibleLocked(string(name), host, known) {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts, nil
+}
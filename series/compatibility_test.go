@@ -0,0 +1,136 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type compatibilitySuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&compatibilitySuite{})
+
+func (s *compatibilitySuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *compatibilitySuite) checkCanRunOn(c *gc.C, workload, host string, expected bool) {
+	ok, err := series.CanRunOn(workload, host)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ok, gc.Equals, expected, gc.Commentf("%s workload on %s host", workload, host))
+}
+
+func (s *compatibilitySuite) TestExactByDefault(c *gc.C) {
+	s.checkCanRunOn(c, "focal", "focal", true)
+	s.checkCanRunOn(c, "focal", "jammy", false)
+	s.checkCanRunOn(c, "rhel8", "rocky8", false)
+
+	hosts, err := series.CompatibleHostSeries("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hosts, jc.DeepEquals, []string{"focal"})
+}
+
+func (s *compatibilitySuite) TestLTSOrNewer(c *gc.C) {
+	err := series.DefaultRegistry().SetCompatibilityPolicy("focal", series.CompatibleLTSOrNewer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkCanRunOn(c, "focal", "jammy", true)
+	s.checkCanRunOn(c, "focal", "noble", true)
+	// Interim releases and older releases aren't allowed.
+	s.checkCanRunOn(c, "focal", "mantic", false)
+	s.checkCanRunOn(c, "focal", "bionic", false)
+	s.checkCanRunOn(c, "focal", "centos8", false)
+	// The policy only applies to the focal workloads.
+	s.checkCanRunOn(c, "bionic", "focal", false)
+}
+
+func (s *compatibilitySuite) TestLTSOrNewerWithoutLTS(c *gc.C) {
+	err := series.DefaultRegistry().SetOSCompatibilityPolicy(os.Debian, series.CompatibleLTSOrNewer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkCanRunOn(c, "buster", "buster", true)
+	s.checkCanRunOn(c, "buster", "bullseye", false)
+}
+
+func (s *compatibilitySuite) TestFamily(c *gc.C) {
+	err := series.DefaultRegistry().SetOSCompatibilityPolicy(os.RHEL, series.CompatibleFamily)
+	c.Assert(err, jc.ErrorIsNil)
+
+	hosts, err := series.CompatibleHostSeries("rhel8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hosts, jc.DeepEquals, []string{"alma8", "centos8", "centos8-stream", "rhel8", "rocky8"})
+	s.checkCanRunOn(c, "rhel9", "alma9", true)
+	s.checkCanRunOn(c, "rhel9", "rocky8", false)
+	s.checkCanRunOn(c, "rhel9", "fedora40", false)
+	// The policy is of the RHEL workloads.
+	s.checkCanRunOn(c, "rocky9", "rhel9", false)
+}
+
+func (s *compatibilitySuite) TestFamilyWithoutFamily(c *gc.C) {
+	err := series.DefaultRegistry().SetCompatibilityPolicy("bookworm", series.CompatibleFamily)
+	c.Assert(err, jc.ErrorIsNil)
+	hosts, err := series.CompatibleHostSeries("bookworm")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hosts, jc.DeepEquals, []string{"bookworm"})
+}
+
+func (s *compatibilitySuite) TestSeriesPolicyOverridesOSPolicy(c *gc.C) {
+	registry := series.DefaultRegistry()
+	err := registry.SetOSCompatibilityPolicy(os.Ubuntu, series.CompatibleLTSOrNewer)
+	c.Assert(err, jc.ErrorIsNil)
+	err = registry.SetCompatibilityPolicy("jammy", series.CompatibleExact)
+	c.Assert(err, jc.ErrorIsNil)
+
+	policy, err := registry.CompatibilityPolicy("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(policy, gc.Equals, series.CompatibleLTSOrNewer)
+	policy, err = registry.CompatibilityPolicy("jammy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(policy, gc.Equals, series.CompatibleExact)
+
+	s.checkCanRunOn(c, "focal", "noble", true)
+	s.checkCanRunOn(c, "jammy", "noble", false)
+}
+
+func (s *compatibilitySuite) TestAllowCompatibility(c *gc.C) {
+	err := series.DefaultRegistry().AllowCompatibility("centos7", "rhel8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkCanRunOn(c, "centos7", "rhel8", true)
+	s.checkCanRunOn(c, "rhel8", "centos7", false)
+
+	hosts, err := series.CompatibleHostSeries("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hosts, jc.DeepEquals, []string{"centos7", "rhel8"})
+}
+
+func (s *compatibilitySuite) TestUnknownSeries(c *gc.C) {
+	_, err := series.CanRunOn("focal", "nonsense")
+	c.Check(err, gc.ErrorMatches, `.*"nonsense".*`)
+	_, err = series.CompatibleHostSeries("nonsense")
+	c.Check(err, gc.ErrorMatches, `.*"nonsense".*`)
+	err = series.DefaultRegistry().AllowCompatibility("nonsense", "focal")
+	c.Check(err, gc.ErrorMatches, `.*"nonsense".*`)
+}
+
+func (s *compatibilitySuite) TestInvalidPolicy(c *gc.C) {
+	err := series.DefaultRegistry().SetCompatibilityPolicy("focal", series.CompatibilityPolicy(42))
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	err = series.DefaultRegistry().SetOSCompatibilityPolicy(os.Unknown, series.CompatibleExact)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *compatibilitySuite) TestPolicyString(c *gc.C) {
+	c.Check(series.CompatibleExact.String(), gc.Equals, "exact")
+	c.Check(series.CompatibleLTSOrNewer.String(), gc.Equals, "lts-or-newer")
+	c.Check(series.CompatibleFamily.String(), gc.Equals, "family")
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/os"
+)
+
+// MatchConstraint reports whether target satisfies the constraint, which
+// names an operating system followed by any number of version comparisons
+// that must all hold, for example "ubuntu >=20.04 <24.10". Alternatives
+// are separated by "||", as in "ubuntu >=20.04 || centos >=8". The
+// comparison operators are =, !=, <, <=, > and >=.
+//
+// The target is either a series, such as "focal", or an operating system
+// and version separated by "@", such as "ubuntu@20.04". The versions of
+// series that aren't numeric, like "centos7", are compared by their
+// numeric part.
+func MatchConstraint(constraint, target string) (bool, error) {
+	alternatives, err := parseConstraint(constraint)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	osName, version, err := constraintTarget(target)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, alt := range alternatives {
+		if alt.matches(osName, version) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// constraintAlternative is one of the "||" separated parts of a constraint.
+type constraintAlternative struct {
+	os    string
+	terms []constraintTerm
+}
+
+// constraintTerm is a single version comparison.
+type constraintTerm struct {
+	op      string
+	version []int
+}
+
+func (a constraintAlternative) matches(osName string, version []int) bool {
+	if a.os != osName {
+		return false
+	}
+	if len(a.terms) > 0 && version == nil {
+		return false
+	}
+	for _, term := range a.terms {
+		cmp := compareVersions(version, term.version)
+		var ok bool
+		switch term.op {
+		case "=", "==":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// constraintOperators holds the comparison operators, longest first so
+// that prefixes match correctly.
+var constraintOperators = []string{"==", "!=", "<=", ">=", "=", "<", ">"}
+
+func parseConstraint(constraint string) ([]constraintAlternative, error) {
+	var alternatives []constraintAlternative
+	for _, part := range strings.Split(constraint, "||") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			return nil, errors.NotValidf("series constraint %q", constraint)
+		}
+		alt := constraintAlternative{os: strings.ToLower(fields[0])}
+		if _, err := parseDefinitionOS(alt.os); err != nil {
+			return nil, errors.NotValidf("series constraint %q", constraint)
+		}
+		// Allow a space between an operator and its version.
+		for i := 1; i < len(fields); i++ {
+			field := fields[i]
+			var op string
+			for _, candidate := range constraintOperators {
+				if strings.HasPrefix(field, candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.NotValidf("series constraint %q", constraint)
+			}
+			versionText := strings.TrimPrefix(field, op)
+			if versionText == "" && i+1 < len(fields) {
+				i++
+				versionText = fields[i]
+			}
+			version, ok := parseConstraintVersion(versionText)
+			if !ok {
+				return nil, errors.NotValidf("series constraint %q", constraint)
+			}
+			alt.terms = append(alt.terms, constraintTerm{op: op, version: version})
+		}
+		alternatives = append(alternatives, alt)
+	}
+	return alternatives, nil
+}
+
+// constraintTarget returns the OS name and version of a constraint target.
+// The version is nil if it isn't numeric.
+func constraintTarget(target string) (string, []int, error) {
+	if parts := strings.SplitN(target, "@", 2); len(parts) == 2 {
+		osName := FormatSeries(parts[0])
+		if _, err := parseDefinitionOS(osName); err != nil {
+			return "", nil, errors.NotValidf("series constraint target %q", target)
+		}
+		version, ok := parseConstraintVersion(strings.TrimSpace(parts[1]))
+		if !ok {
+			return "", nil, errors.NotValidf("series constraint target %q", target)
+		}
+		return osName, version, nil
+	}
+
+	info, err := GetSeries(target)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	// Versions like "centos7" and "opensuse15.2" are compared by their
+	// numeric part.
+	numeric := strings.TrimLeftFunc(info.Version, unicode.IsLetter)
+	version, _ := parseConstraintVersion(strings.TrimSuffix(numeric, "-stream"))
+	return os.FormatOSType(info.OS), version, nil
+}
+
+// parseConstraintVersion parses a dotted numeric version like "20.04".
+func parseConstraintVersion(s string) ([]int, bool) {
+	if s == "" {
+		return nil, false
+	}
+	parts := strings.Split(s, ".")
+	version := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		version[i] = n
+	}
+	return version, true
+}
+
+// compareVersions compares dotted versions, treating missing components as
+// zero, so "20.04" equals "20.04.0".
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type constraintSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&constraintSuite{})
+
+func (s *constraintSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *constraintSuite) TestMatchConstraint(c *gc.C) {
+	for i, test := range []struct {
+		constraint string
+		target     string
+		match      bool
+	}{
+		{"ubuntu >=20.04 <24.10", "focal", true},
+		{"ubuntu >=20.04 <24.10", "bionic", false},
+		{"ubuntu >=20.04 <24.10", "ubuntu@24.04", true},
+		{"ubuntu >=20.04 <24.10", "ubuntu@24.10", false},
+		{"ubuntu >= 20.04", "ubuntu@20.04.3", true},
+		{"ubuntu", "xenial", true},
+		{"ubuntu", "centos7", false},
+		{"ubuntu =18.04", "bionic", true},
+		{"ubuntu != 18.04", "bionic", false},
+		{"ubuntu >=20.04 || centos >=8", "centos8", true},
+		{"ubuntu >=20.04 || centos >=8", "centos7", false},
+		{"centos >8", "centos8-stream", false},
+		{"centos >=9", "centos9", true},
+		{"opensuse >=15.5", "opensuse15.6", true},
+		{"windows", "win2019", true},
+		{"windows >=2016", "win2019", true},
+		{"kubernetes >1", "kubernetes", false},
+		{"Ubuntu <=16.04", "Xenial", true},
+	} {
+		c.Logf("test %d: %q matching %q", i, test.constraint, test.target)
+		match, err := series.MatchConstraint(test.constraint, test.target)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(match, gc.Equals, test.match)
+	}
+}
+
+func (s *constraintSuite) TestMatchConstraintInvalid(c *gc.C) {
+	for _, constraint := range []string{"", "beos >=1", "ubuntu 20.04", "ubuntu >=", "ubuntu >=twenty", "ubuntu ||"} {
+		_, err := series.MatchConstraint(constraint, "focal")
+		c.Check(err, jc.Satisfies, errors.IsNotValid, gc.Commentf("constraint %q", constraint))
+	}
+	_, err := series.MatchConstraint("ubuntu", "beos@5")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	_, err = series.MatchConstraint("ubuntu", "ubuntu@latest")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	_, err = series.MatchConstraint("ubuntu", "firewolf")
+	c.Check(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"time"
+)
+
+const (
+	// DefinitionsSchemaVersion is the version of the series definitions
+	// format understood by this package.
+	DefinitionsSchemaVersion = 1
+
+	// embeddedDataVersion identifies the vintage of the series data
+	// compiled into this package. It should be updated whenever the static
+	// series tables change.
+	embeddedDataVersion = "2026.10"
+
+	// embeddedSource is the DataVersionInfo source of the compiled in data.
+	embeddedSource = "embedded"
+)
+
+// embeddedDataTimestamp is when the compiled in series data was last
+// updated.
+var embeddedDataTimestamp = time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+// DataVersionInfo describes where the most recently applied series data
+// came from.
+type DataVersionInfo struct {
+	// Source is "embedded" for the compiled in data, the path of the
+	// distro-info file, "definitions" for loaded definition files,
+	// "bundle" for loaded series data bundles, or the URL the data was
+	// fetched from.
+	Source string `json:"source"`
+	// Version is the version declared by the data, if any.
+	Version string `json:"version,omitempty"`
+	// Timestamp is when the data was produced, if known.
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+var dataVersion = DataVersionInfo{
+	Source:    embeddedSource,
+	Version:   embeddedDataVersion,
+	Timestamp: embeddedDataTimestamp,
+}
+
+// DataVersion returns the source, version and timestamp of the most
+// recently applied series data, so that it's possible to audit which
+// vintage of OS data decisions are based on.
+func DataVersion() DataVersionInfo {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	return dataVersion
+}
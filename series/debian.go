@@ -0,0 +1,293 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujuos "github.com/juju/os"
+)
+
+// DebianDistroInfo references the distro-info csv holding the Debian
+// releases.
+var DebianDistroInfo = "/usr/share/distro-info/debian.csv"
+
+// The meta names of Debian releases, which refer to different releases as
+// time passes.
+const (
+	DebianOldStable = "oldstable"
+	DebianStable    = "stable"
+	DebianTesting   = "testing"
+	DebianUnstable  = "unstable"
+	// DebianSid is the codename of unstable, which is always sid.
+	DebianSid = "sid"
+)
+
+// debianRelease is a Debian release from distro-info. Unlike ubuntu
+// releases, unreleased ones have no release date, and sid has no version.
+// The end of life is that of LTS support, if the release has it.
+type debianRelease struct {
+	version  string
+	series   string
+	created  time.Time
+	released time.Time
+	eol      time.Time
+}
+
+// DebianSeriesVersion returns the version of a Debian series, given by
+// codename, such as "bookworm", or by one of the meta names "oldstable",
+// "stable" and "testing", which are resolved from the Debian distro-info
+// data as of the registry's clock. The known Debian series are used for
+// releases missing from the distro-info data, or if there is none. The
+// unstable series, sid, has no version.
+func DebianSeriesVersion(series string) (string, error) {
+	name := FormatSeries(series)
+	if name == "" {
+		return "", errors.Trace(EmptyInputError{Input: "series"})
+	}
+	releases, err := debianReleases()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	release, ok := resolveDebianRelease(releases, name, DefaultRegistry().Now())
+	if !ok || release.version == "" {
+		return "", errors.Trace(unknownSeriesVersionError(series))
+	}
+	return release.version, nil
+}
+
+// VersionDebianSeries returns the codename of the Debian series with the
+// version, for example "bookworm" for "12". Point releases, such as
+// "12.5", give the series of their major version. The meta names accepted
+// by DebianSeriesVersion are resolved to a codename too, so "unstable"
+// gives "sid". Releases are found as they are by DebianSeriesVersion.
+func VersionDebianSeries(version string) (string, error) {
+	trimmed := FormatSeries(version)
+	if trimmed == "" {
+		return "", errors.Trace(EmptyInputError{Input: "version"})
+	}
+	releases, err := debianReleases()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if release, ok := resolveDebianRelease(releases, trimmed, DefaultRegistry().Now()); ok {
+		return release.series, nil
+	}
+	major := strings.SplitN(trimmed, ".", 2)[0]
+	for _, release := range releases {
+		if release.version != "" && release.version == major {
+			return release.series, nil
+		}
+	}
+	return "", errors.Trace(unknownVersionSeriesError(version))
+}
+
+// resolveDebianRelease returns the release with the codename or meta name
+// at now.
+func resolveDebianRelease(releases []debianRelease, name string, now time.Time) (debianRelease, bool) {
+	var released []debianRelease
+	var testing *debianRelease
+	for i, release := range releases {
+		switch {
+		case release.series == name:
+			return release, true
+		case release.created.After(now) || release.version == "":
+		case !release.released.IsZero() && !release.released.After(now):
+			released = append(released, release)
+		case testing == nil || release.created.After(testing.created):
+			testing = &releases[i]
+		}
+	}
+	switch name {
+	case DebianUnstable:
+		return resolveDebianRelease(releases, DebianSid, now)
+	case DebianStable, DebianOldStable:
+		// The releases are sorted oldest first.
+		index := len(released) - 1
+		if name == DebianOldStable {
+			index--
+		}
+		if index < 0 {
+			return debianRelease{}, false
+		}
+		return released[index], true
+	case DebianTesting:
+		if testing != nil {
+			return *testing, true
+		}
+	}
+	return debianRelease{}, false
+}
+
+// debianReleases returns the Debian releases from DebianDistroInfo, with
+// the known Debian series it lacks, oldest first. The known series are
+// used alone if there is no distro-info data.
+func debianReleases() ([]debianRelease, error) {
+	releases, err := readDebianReleases()
+	if errors.IsNotFound(err) {
+		logger.Debugf("%v, using the known Debian series", err)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	listed := make(map[string]bool, len(releases))
+	for _, release := range releases {
+		listed[release.series] = true
+	}
+	for _, release := range knownDebianReleases() {
+		if !listed[release.series] {
+			releases = append(releases, release)
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].created.Before(releases[j].created)
+	})
+	return releases, nil
+}
+
+// knownDebianReleases returns the known Debian series as releases. They
+// have no creation dates, so they are taken to be created when released.
+func knownDebianReleases() []debianRelease {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	var releases []debianRelease
+	for name, version := range nonUbuntuSeries {
+		if osType, err := getOSFromSeries(name); err != nil || osType != jujuos.Debian {
+			continue
+		}
+		releases = append(releases, debianRelease{
+			version:  version.Version,
+			series:   name,
+			created:  version.Released,
+			released: version.Released,
+			eol:      version.EOL,
+		})
+	}
+	return releases
+}
+
+// readDebianReleases reads the Debian releases from DebianDistroInfo,
+// oldest first.
+func readDebianReleases() ([]debianRelease, error) {
+	f, err := os.Open(DebianDistroInfo)
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("debian distro-info %q", DebianDistroInfo)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	releases, err := parseDebianDistroInfo(f)
+	if IsDistroInfoDateError(err) {
+		logger.Warningf("ignoring series in %s: %v", DebianDistroInfo, err)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "reading %s", DebianDistroInfo)
+	}
+	return releases, nil
+}
+
+// parseDebianDistroInfo parses Debian distro-info CSV data. Records
+// without a valid series are skipped. Records whose dates can't be parsed
+// are skipped too, but are reported, as they are by parseDistroInfo. The
+// release and end of life dates may be empty, as they are for releases
+// still in development.
+func parseDebianDistroInfo(r io.Reader) ([]debianRelease, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxDistroInfoSize+1))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) > maxDistroInfoSize {
+		return nil, errors.Errorf("distro-info data exceeds %d bytes", maxDistroInfoSize)
+	}
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	csvReader.FieldsPerRecord = -1
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var releases []debianRelease
+	var dateErrors DistroInfoDateErrors
+	headers := records[0]
+	for _, fields := range records[1:] {
+		var raw record
+		var eolLTS string
+		for i, field := range fields {
+			if i >= len(headers) {
+				break
+			}
+			switch headers[i] {
+			case "version":
+				raw.Version = field
+			case "series":
+				raw.Series = field
+			case "created":
+				raw.Created = field
+			case "release":
+				raw.Released = field
+			case "eol":
+				raw.EOL = field
+			case "eol-lts":
+				eolLTS = field
+			}
+		}
+		if !validSeriesName.MatchString(raw.Series) {
+			continue
+		}
+		var created, released, eol, ltsEOL time.Time
+		var dateErr *DistroInfoDateError
+		for _, date := range []struct {
+			field    string
+			value    string
+			parsed   *time.Time
+			optional bool
+		}{
+			{"created", raw.Created, &created, false},
+			{"release", raw.Released, &released, true},
+			{"eol", raw.EOL, &eol, true},
+			{"eol-lts", eolLTS, &ltsEOL, true},
+		} {
+			if date.optional && strings.TrimSpace(date.value) == "" {
+				continue
+			}
+			parsed, ok := parseDistroInfoDate(date.value)
+			if !ok {
+				dateErr = &DistroInfoDateError{Series: raw.Series, Field: date.field, Value: date.value}
+				break
+			}
+			*date.parsed = parsed
+		}
+		if dateErr != nil {
+			dateErrors = append(dateErrors, dateErr)
+			continue
+		}
+		if !ltsEOL.IsZero() {
+			eol = ltsEOL
+		}
+		releases = append(releases, debianRelease{
+			version:  raw.Version,
+			series:   raw.Series,
+			created:  created,
+			released: released,
+			eol:      eol,
+		})
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].created.Before(releases[j].created)
+	})
+	if len(dateErrors) > 0 {
+		return releases, errors.Trace(dateErrors)
+	}
+	return releases, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type debianSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&debianSuite{})
+
+const debianDistroInfo = `version,codename,series,created,release,eol,eol-lts,eol-elts
+10,Buster,buster,2017-06-17,2019-07-06,2022-09-10,2024-06-30,2029-06-30
+11,Bullseye,bullseye,2019-07-06,2021-08-14,2024-08-14,2026-08-31,2031-06-30
+12,Bookworm,bookworm,2021-08-14,2023-06-10,2026-06-10,2028-06-30,2033-06-30
+13,Trixie,trixie,2023-06-10
+14,Forky,forky,2025-08-09
+,Sid,sid,1993-08-16
+,Experimental,experimental,1993-08-16
+`
+
+func (s *debianSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	path := filepath.Join(c.MkDir(), "debian.csv")
+	err := ioutil.WriteFile(path, []byte(debianDistroInfo), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(&series.DebianDistroInfo, path)
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+}
+
+func (s *debianSuite) TestDebianOSSeries(c *gc.C) {
+	for _, name := range []string{"stretch", "buster", "bullseye", "bookworm", "trixie"} {
+		osType, err := series.GetOSFromSeries(name)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(osType, gc.Equals, os.Debian)
+	}
+	version, err := series.SeriesVersion("bullseye")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(version, gc.Equals, "11")
+	name, err := series.VersionSeries("12")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "bookworm")
+	c.Check(series.OSSupportedSeries(os.Debian), jc.SameContents, []string{"stretch", "buster", "bullseye", "bookworm", "trixie"})
+}
+
+func (s *debianSuite) TestDebianSeriesVersion(c *gc.C) {
+	for _, test := range []struct {
+		series  string
+		version string
+	}{
+		{"bookworm", "12"},
+		{"Bullseye", "11"},
+		{"trixie", "13"},
+		{"stable", "12"},
+		{"oldstable", "11"},
+		{"testing", "13"},
+	} {
+		c.Logf("series %q", test.series)
+		version, err := series.DebianSeriesVersion(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(version, gc.Equals, test.version)
+	}
+}
+
+func (s *debianSuite) TestDebianSeriesVersionErrors(c *gc.C) {
+	_, err := series.DebianSeriesVersion("sid")
+	c.Check(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+	_, err = series.DebianSeriesVersion("picard")
+	c.Check(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+	_, err = series.DebianSeriesVersion(" ")
+	c.Check(err, jc.Satisfies, series.IsEmptyInputError)
+}
+
+func (s *debianSuite) TestWithoutDistroInfo(c *gc.C) {
+	s.PatchValue(&series.DebianDistroInfo, "/does/not/exist")
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+	for _, test := range []struct {
+		series  string
+		version string
+	}{
+		{"bookworm", "12"},
+		{"trixie", "13"},
+		{"stable", "13"},
+		{"oldstable", "12"},
+	} {
+		c.Logf("series %q", test.series)
+		version, err := series.DebianSeriesVersion(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(version, gc.Equals, test.version)
+		// The versions match those of the known series.
+		if known, err := series.SeriesVersion(test.series); err == nil {
+			c.Check(version, gc.Equals, known)
+		}
+	}
+	for _, test := range []struct {
+		version string
+		series  string
+	}{
+		{"12", "bookworm"},
+		{"13.1", "trixie"},
+		{"stable", "trixie"},
+	} {
+		c.Logf("version %q", test.version)
+		name, err := series.VersionDebianSeries(test.version)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(name, gc.Equals, test.series)
+	}
+	_, err := series.DebianSeriesVersion("testing")
+	c.Check(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+}
+
+func (s *debianSuite) TestDistroInfoLackingKnownSeries(c *gc.C) {
+	// The distro-info data has no stretch.
+	version, err := series.DebianSeriesVersion("stretch")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(version, gc.Equals, "9")
+	name, err := series.VersionDebianSeries("9")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "stretch")
+}
+
+func (s *debianSuite) TestVersionDebianSeries(c *gc.C) {
+	for _, test := range []struct {
+		version string
+		series  string
+	}{
+		{"12", "bookworm"},
+		{"12.4", "bookworm"},
+		{"10", "buster"},
+		{"stable", "bookworm"},
+		{"testing", "trixie"},
+		{"unstable", "sid"},
+		{"sid", "sid"},
+	} {
+		c.Logf("version %q", test.version)
+		name, err := series.VersionDebianSeries(test.version)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(name, gc.Equals, test.series)
+	}
+	_, err := series.VersionDebianSeries("99")
+	c.Check(err, jc.Satisfies, series.IsUnknownVersionSeriesError)
+}
+
+func (s *debianSuite) TestMetaNamesFollowClock(c *gc.C) {
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+	name, err := series.VersionDebianSeries("stable")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "bullseye")
+	name, err = series.VersionDebianSeries("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "bookworm")
+}
+
+func (s *debianSuite) TestInvalidDatesIgnored(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "debian.csv")
+	err := ioutil.WriteFile(path, []byte(`version,codename,series,created,release,eol,eol-lts
+12,Bookworm,bookworm,2021-08-14,2023-06-10,2026-06-10,2028-06-30
+14,Forky,forky,someday
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(&series.DebianDistroInfo, path)
+
+	version, err := series.DebianSeriesVersion("bookworm")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(version, gc.Equals, "12")
+	_, err = series.DebianSeriesVersion("forky")
+	c.Check(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+}
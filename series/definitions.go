@@ -0,0 +1,171 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// Definition describes a single series, as found in an external series
+// definitions file or fetched from a remote data source.
+type Definition struct {
+	// Series is the name of the series, for example "focal".
+	Series Name `json:"series"`
+	// OS is the lowercase name of the operating system of the series, for
+	// example "ubuntu" or "centos".
+	OS string `json:"os"`
+	// Version is the version of the series, for example "20.04".
+	Version string `json:"version"`
+	// LTS is true if the series is a long term support release.
+	LTS bool `json:"lts,omitempty"`
+	// Supported is true if Juju officially supports the series.
+	Supported bool `json:"supported,omitempty"`
+	// ESMSupported is true if the series has extended security maintenance.
+	ESMSupported bool `json:"esm-supported,omitempty"`
+	// Resources holds the minimum resources recommended for the series,
+	// if there is a recommendation.
+	Resources *Resources `json:"resources,omitempty"`
+	// Tags are attached to the series, replacing any it had, if given.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// definitions is the top level document of a series definitions file.
+type definitions struct {
+	// Schema is the version of the definitions format, which must not be
+	// newer than DefinitionsSchemaVersion.
+	Schema int `json:"schema"`
+	// Version identifies the release of the data, for auditing.
+	Version string `json:"version,omitempty"`
+	// Timestamp is when the data was produced.
+	Timestamp time.Time    `json:"timestamp,omitempty"`
+	Series    []Definition `json:"series"`
+}
+
+// definedSeriesOS records the OS of non-ubuntu series that were added by
+// definitions, as they don't appear in any of the static OS tables.
+var definedSeriesOS = map[string]os.OSType{}
+
+// LoadDefinitions reads series definitions in JSON format from r and adds
+// them to the known series, overwriting any existing series of the same
+// name. The definitions must conform to DefinitionsJSONSchema. Either all
+// the definitions are applied or none of them are; every problem found is
+// reported, and can be listed with DefinitionProblems.
+func LoadDefinitions(r io.Reader) error {
+	return errors.Trace(loadDefinitions(r, "definitions"))
+}
+
+// loadDefinitions reads and applies series definitions from r, recording
+// source as the origin of the data.
+func loadDefinitions(r io.Reader, source string) error {
+	doc, err := decodeDefinitions(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(applyDefinitionsDocument(doc, source))
+}
+
+// PreviewDefinitions reads series definitions as LoadDefinitions does, and
+// returns the changes that loading them would make to the known series,
+// without applying them. Subscribers aren't notified.
+func PreviewDefinitions(r io.Reader) (ChangeSet, error) {
+	doc, err := decodeDefinitions(r)
+	if err != nil {
+		return ChangeSet{}, errors.Trace(err)
+	}
+	return previewDefinitionsDocument(doc)
+}
+
+// previewDefinitionsDocument applies the definitions in doc, and then
+// undoes them before any reader can see them, returning the changes they
+// made.
+func previewDefinitionsDocument(doc definitions) (ChangeSet, error) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+
+	updateSeriesVersionsOnce()
+	before := knownSeries()
+	defer backupSeriesState().restore()
+	if err := applyDefinitions(doc.Series, SourceDefinitions); err != nil {
+		return ChangeSet{}, errors.Trace(err)
+	}
+	return diffKnownSeries(before, knownSeries()), nil
+}
+
+// applyDefinitionsDocument applies the decoded series definitions, recording
+// source as the origin of the data. The definitions are applied atomically.
+func applyDefinitionsDocument(doc definitions, source string) error {
+	return errors.Trace(update(func() error {
+		if err := applyDefinitions(doc.Series, SourceDefinitions); err != nil {
+			return errors.Trace(err)
+		}
+		setDataVersion(DataVersionInfo{
+			Source:    source,
+			Version:   doc.Version,
+			Timestamp: doc.Timestamp,
+		})
+		return nil
+	}))
+}
+
+// applyDefinitions applies validated definitions to the global series
+// state, skipping any series already provided by a source with a higher
+// precedence. The caller must hold seriesVersionsMutex.
+func applyDefinitions(defs []Definition, source Source) error {
+	var problems DefinitionErrors
+	osTypes := make([]os.OSType, len(defs))
+	for i, def := range defs {
+		osType, err := parseDefinitionOS(def.OS)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if existing, err := getOSFromSeries(def.Series.String()); err == nil && existing != osType {
+			problems = append(problems, &DefinitionError{
+				Index:   i,
+				Series:  def.Series.String(),
+				Field:   "os",
+				Message: fmt.Sprintf("%q redefines the %s series as %s", def.OS, existing, osType),
+			})
+		}
+		osTypes[i] = osType
+	}
+	if len(problems) > 0 {
+		return errors.Trace(problems)
+	}
+
+	for i, def := range defs {
+		name := def.Series.String()
+		if !canOverwrite(name, source) {
+			logger.Debugf("ignoring %s series %q, already provided by %s", source, name, seriesSources[name])
+			continue
+		}
+		seriesSources[name] = source
+		seriesVersions[name] = def.Version
+		version := seriesVersion{
+			Version:      def.Version,
+			LTS:          def.LTS,
+			Supported:    def.Supported,
+			ESMSupported: def.ESMSupported,
+		}
+		if def.Resources != nil {
+			setSeriesResources(name, *def.Resources)
+		}
+		if def.Tags != nil {
+			setSeriesTags(name, def.Tags)
+		}
+		if osTypes[i] == os.Ubuntu {
+			ubuntuSeries[name] = version
+			continue
+		}
+		nonUbuntuSeries[name] = version
+		definedSeriesOS[name] = osTypes[i]
+	}
+	updateVersionSeries()
+	latestLtsSeries = ""
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type definitionsSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&definitionsSuite{})
+
+func (s *definitionsSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	cleanup := series.SetSeriesVersions(make(map[string]string))
+	s.AddCleanup(func(*gc.C) { cleanup() })
+}
+
+const definitionsData = `{
+	"schema": 1,
+	"version": "2020.11",
+	"timestamp": "2020-11-01T00:00:00Z",
+	"series": [
+		{"series": "spock", "os": "ubuntu", "version": "99.04", "lts": true, "supported": true},
+		{"series": "centos9", "os": "centos", "version": "centos9", "supported": true}
+	]
+}`
+
+func (s *definitionsSuite) TestLoadDefinitions(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(definitionsData))
+	c.Assert(err, jc.ErrorIsNil)
+
+	osType, err := series.GetOSFromSeries("spock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.Ubuntu)
+	version, err := series.SeriesVersion("spock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "99.04")
+	name, err := series.VersionSeries("99.04")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "spock")
+
+	osType, err = series.GetOSFromSeries("centos9")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.CentOS)
+	workloadSeries := set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+	c.Assert(workloadSeries.Contains("centos9"), jc.IsTrue)
+}
+
+func (s *definitionsSuite) TestLoadDefinitionsDataVersion(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(definitionsData))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(series.DataVersion(), jc.DeepEquals, series.DataVersionInfo{
+		Source:    "definitions",
+		Version:   "2020.11",
+		Timestamp: time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC),
+	})
+}
+
+func (s *definitionsSuite) TestPreviewDefinitions(c *gc.C) {
+	before := series.DataVersion()
+	changes, err := series.PreviewDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04"},
+		{"series": "focal", "os": "ubuntu", "version": "20.04", "lts": true, "supported": false}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, jc.DeepEquals, []series.Name{"picard"})
+	c.Assert(changes.Updated, jc.DeepEquals, []series.Name{"focal"})
+
+	_, err = series.GetOSFromSeries("picard")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+	c.Assert(series.DataVersion(), jc.DeepEquals, before)
+
+	_, err = series.PreviewDefinitions(strings.NewReader(`{"schema": 1, "series": [{"series": "picard"}]}`))
+	c.Assert(err, jc.Satisfies, series.IsDefinitionError)
+}
+
+func (s *definitionsSuite) TestLoadDefinitionsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		data string
+		err  string
+	}{{
+		data: `{"schema": 1, "series": [{"os": "ubuntu", "version": "99.04"}]}`,
+		err:  `series\[0\]: series is required`,
+	}, {
+		data: `{"schema": 1, "series": [{"series": "Spock", "os": "ubuntu", "version": "99.04"}]}`,
+		err:  `series\[0\] \("Spock"\): series must match .*`,
+	}, {
+		data: `{"schema": 1, "series": [{"series": "spock", "version": "99.04"}]}`,
+		err:  `series\[0\] \("spock"\): os is required`,
+	}, {
+		data: `{"schema": 1, "series": [{"series": "spock", "os": "ubuntu"}]}`,
+		err:  `series\[0\] \("spock"\): version is required`,
+	}, {
+		data: `{"schema": 1, "series": [{"series": "spock", "os": "beos", "version": "5"}]}`,
+		err:  `series\[0\] \("spock"\): os "beos" is not one of ubuntu, windows, macos, centos, genericlinux, opensuse, kubernetes, openbsd, netbsd, aix, android, debian, fedora, rhel, rocky, almalinux`,
+	}, {
+		data: `{"schema": 1, "series": [
+			{"series": "spock", "os": "ubuntu", "version": "99.04"},
+			{"series": "spock", "os": "ubuntu", "version": "99.10"}
+		]}`,
+		err: `series\[1\] \("spock"\): series duplicates series\[0\]`,
+	}, {
+		data: `{"schema": 1, "series": [{"series": "bionic", "os": "centos", "version": "centos18"}]}`,
+		err:  `series\[0\] \("bionic"\): os "centos" redefines the Ubuntu series as CentOS`,
+	}, {
+		data: "{\"schema\": 1,\n \"series\": [{\"series\": \"spock\", \"codename\": \"x\"}]}",
+		err:  `decoding series definitions: json: unknown field "codename"`,
+	}, {
+		data: "{\"schema\": 1,\n \"series\": [{\"series\": \"spock\", \"lts\": \"yes\"}]}",
+		err:  `decoding series definitions at line 2, column 45: json: cannot unmarshal string .*`,
+	}, {
+		data: `{"series": [`,
+		err:  `decoding series definitions: unexpected EOF`,
+	}, {
+		data: `{"series": []}`,
+		err:  `series definitions without schema version not valid`,
+	}, {
+		data: `{"schema": 2, "series": []}`,
+		err:  `series definitions schema version 2 not supported`,
+	}} {
+		c.Logf("test %d", i)
+		err := series.LoadDefinitions(strings.NewReader(test.data))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	_, err := series.SeriesVersion("spock")
+	c.Assert(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+}
+
+func (s *definitionsSuite) TestLoadDefinitionsMacOS(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "sonoma", "os": "macos", "version": "sonoma"},
+		{"series": "ventura", "os": "osx", "version": "ventura"}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range []string{"sonoma", "ventura"} {
+		osType, err := series.GetOSFromSeries(name)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(osType, gc.Equals, os.MacOS)
+	}
+}
+
+func (s *definitionsSuite) TestDefinitionError(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [{"series": "spock", "os": "ubuntu"}]}`))
+	c.Assert(err, jc.Satisfies, series.IsDefinitionError)
+
+	err = series.RegisterSeries(series.Definition{Series: "spock", OS: "Ubuntu", Version: "99.04"})
+	c.Assert(err, gc.ErrorMatches, `series\[0\] \("spock"\): os "Ubuntu" is not one of .*`)
+	c.Assert(err, jc.Satisfies, series.IsDefinitionError)
+}
+
+func (s *definitionsSuite) TestDefinitionErrorsAggregated(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "picard", "os": "ubuntu", "version": "99.04"},
+		{"os": "beos"},
+		{"series": "riker", "os": "ubuntu", "version": "99.10"},
+		{"series": "picard", "os": "ubuntu", "version": "99.10", "tags": ["Bad Tag"]}
+	]}`))
+	c.Assert(err, jc.Satisfies, series.IsDefinitionError)
+	c.Assert(err, gc.ErrorMatches, `5 problems with series definitions: series\[1\]: series is required; .*`)
+
+	var problems []string
+	for _, problem := range series.DefinitionProblems(err) {
+		problems = append(problems, fmt.Sprintf("%d %s %s", problem.Index, problem.Series, problem.Field))
+	}
+	c.Assert(problems, jc.DeepEquals, []string{
+		"1  series",
+		"1  os",
+		"1  version",
+		"3 picard tags",
+		"3 picard series",
+	})
+	_, err = series.SeriesVersion("riker")
+	c.Assert(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+
+	err = series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "bionic", "os": "centos", "version": "centos18"},
+		{"series": "focal", "os": "windows", "version": "win20"}
+	]}`))
+	c.Assert(series.DefinitionProblems(err), gc.HasLen, 2)
+}
+
+func (s *definitionsSuite) TestDefinitionsJSONSchema(c *gc.C) {
+	var schema struct {
+		Properties struct {
+			Series struct {
+				Items struct {
+					Properties struct {
+						OS struct {
+							Enum []string `json:"enum"`
+						} `json:"os"`
+					} `json:"properties"`
+				} `json:"items"`
+			} `json:"series"`
+		} `json:"properties"`
+	}
+	err := json.Unmarshal([]byte(series.DefinitionsJSONSchema), &schema)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Every OS allowed by the schema must be accepted when loading.
+	for _, name := range schema.Properties.Series.Items.Properties.OS.Enum {
+		def := series.Definition{Series: series.Name("spock-" + name), OS: name, Version: "spock-" + name}
+		err := series.RegisterSeries(def)
+		c.Check(err, jc.ErrorIsNil, gc.Commentf("os %q", name))
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"context"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// DetectionProvider determines the operating system and series of the
+// host from one source of information, such as /etc/os-release.
+type DetectionProvider interface {
+	// Name identifies the provider, for example "os-release".
+	Name() string
+	// Detect returns information about the host, which holds at least the
+	// OS and series, and true if the provider recognized the host.
+	Detect(ctx context.Context) (HostInfo, bool)
+}
+
+// The names of the built-in detection providers, in the order they are
+// consulted. Only those meaningful on the host's platform are used.
+const (
+	ProviderOSRelease     = "os-release"
+	ProviderLSBRelease    = "lsb-release"
+	ProviderRedHatRelease = "redhat-release"
+	ProviderUname         = "uname"
+	ProviderRegistry      = "registry"
+)
+
+var providerOrder = []string{
+	ProviderOSRelease,
+	ProviderLSBRelease,
+	ProviderRedHatRelease,
+	ProviderUname,
+	ProviderRegistry,
+}
+
+// detectorFunc is a DetectionProvider implemented by a function.
+type detectorFunc struct {
+	name   string
+	detect func(context.Context) (HostInfo, bool)
+}
+
+// Name is part of the DetectionProvider interface.
+func (d detectorFunc) Name() string {
+	return d.name
+}
+
+// Detect is part of the DetectionProvider interface.
+func (d detectorFunc) Detect(ctx context.Context) (HostInfo, bool) {
+	return d.detect(ctx)
+}
+
+var (
+	detectionMutex sync.Mutex
+
+	// builtinProviders holds the built-in providers available on the
+	// host's platform, in priority order.
+	builtinProviders = newBuiltinProviders()
+
+	// customProviders holds the registered providers, in the order they
+	// were registered.
+	customProviders []DetectionProvider
+)
+
+func newBuiltinProviders() []DetectionProvider {
+	var providers []DetectionProvider
+	for _, name := range providerOrder {
+		if detect, ok := platformDetectors[name]; ok {
+			providers = append(providers, detectorFunc{name: name, detect: detect})
+		}
+	}
+	return providers
+}
+
+// RegisterDetectionProvider adds a provider to the detection chain, to be
+// consulted after the built-in providers and any providers registered
+// before it. This lets hosts that the built-in providers don't recognize
+// be detected, by DetectHost and by HostSeries, and so GetHostInfo. It
+// returns a function that removes the provider again.
+func RegisterDetectionProvider(p DetectionProvider) (func(), error) {
+	if p == nil || p.Name() == "" {
+		return nil, errors.NotValidf("detection provider without a name")
+	}
+	detectionMutex.Lock()
+	defer detectionMutex.Unlock()
+	for _, existing := range detectionChain() {
+		if existing.Name() == p.Name() {
+			return nil, errors.AlreadyExistsf("detection provider %q", p.Name())
+		}
+	}
+	customProviders = append(customProviders, p)
+	return func() {
+		detectionMutex.Lock()
+		defer detectionMutex.Unlock()
+		for i, existing := range customProviders {
+			if existing.Name() == p.Name() {
+				customProviders = append(customProviders[:i:i], customProviders[i+1:]...)
+				return
+			}
+		}
+	}, nil
+}
+
+// DetectionProviders returns the names of the providers in the detection
+// chain, in the order they are consulted.
+func DetectionProviders() []string {
+	detectionMutex.Lock()
+	defer detectionMutex.Unlock()
+	var names []string
+	for _, p := range detectionChain() {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// DetectHost consults the providers in the detection chain in turn, and
+// returns the information from the first that recognizes the host. An
+// error satisfying errors.IsNotFound is returned if none does. The steps
+// taken are recorded in any trace or span added to ctx with
+// WithDetectionTrace or WithDetectionSpan.
+func DetectHost(ctx context.Context) (HostInfo, error) {
+	detectionMutex.Lock()
+	providers := detectionChain()
+	detectionMutex.Unlock()
+
+	recorder := newDetectionRecorder(ctx)
+	defer recorder.finish()
+	for _, p := range providers {
+		if err := ctx.Err(); err != nil {
+			return HostInfo{}, errors.Trace(err)
+		}
+		if info, ok := recorder.detect(ctx, p); ok {
+			logger.Debugf("host detected as %s %q by %s", info.OS, info.Series, p.Name())
+			return info, nil
+		}
+		logger.Tracef("host not recognized by %s", p.Name())
+	}
+	return HostInfo{}, errors.NotFoundf("detection provider recognizing the host")
+}
+
+// detectHostSeries returns the series of the host, as HostSeries does.
+// The registered providers are consulted, in order, if the host isn't
+// otherwise recognized.
+func detectHostSeries() (string, error) {
+	series, err := readSeries()
+	if err == nil && series != genericLinuxSeries {
+		return series, nil
+	}
+
+	detectionMutex.Lock()
+	providers := append([]DetectionProvider(nil), customProviders...)
+	detectionMutex.Unlock()
+	for _, p := range providers {
+		if info, ok := p.Detect(context.Background()); ok && info.Series != "" {
+			logger.Debugf("host detected as %s %q by %s", info.OS, info.Series, p.Name())
+			return info.Series, nil
+		}
+	}
+	return series, err
+}
+
+// detectionChain returns the built-in providers followed by the custom
+// ones. The caller must hold detectionMutex.
+func detectionChain() []DetectionProvider {
+	chain := make([]DetectionProvider, 0, len(builtinProviders)+len(customProviders))
+	chain = append(chain, builtinProviders...)
+	return append(chain, customProviders...)
+}
+
+// detectedSeries returns the host information for a series found by a
+// built-in provider.
+func detectedSeries(series string) (HostInfo, bool) {
+	osType, err := GetOSFromSeries(series)
+	if err != nil {
+		return HostInfo{}, false
+	}
+	return HostInfo{OS: osType, Series: series}, true
+}
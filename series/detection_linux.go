@@ -0,0 +1,93 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"context"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	jujuos "github.com/juju/os"
+)
+
+var (
+	// lsbReleaseFile describes the distribution on hosts with the LSB
+	// tools, which older distributions have in place of os-release.
+	lsbReleaseFile = "/etc/lsb-release"
+	// redhatReleaseFile names the release of Red Hat derived
+	// distributions.
+	redhatReleaseFile = "/etc/redhat-release"
+)
+
+var platformDetectors = map[string]func(context.Context) (HostInfo, bool){
+	ProviderOSRelease:     detectOSRelease,
+	ProviderLSBRelease:    detectLSBRelease,
+	ProviderRedHatRelease: detectRedHatRelease,
+}
+
+// detectOSRelease detects the host from /etc/os-release.
+func detectOSRelease(context.Context) (HostInfo, bool) {
+	values, err := jujuos.ReadOSRelease(osReleaseFile)
+	if err != nil {
+		return HostInfo{}, false
+	}
+	series, err := hostSeriesFromOSRelease(values)
+	if err != nil {
+		return HostInfo{}, false
+	}
+	info, ok := detectedSeries(series)
+	info.PrettyName = values["PRETTY_NAME"]
+	return info, ok
+}
+
+// detectLSBRelease detects ubuntu hosts from /etc/lsb-release, which holds
+// lines like "DISTRIB_RELEASE=20.04".
+func detectLSBRelease(context.Context) (HostInfo, bool) {
+	contents, err := ioutil.ReadFile(lsbReleaseFile)
+	if err != nil {
+		return HostInfo{}, false
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(contents), "\n") {
+		c := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(c) == 2 {
+			values[c[0]] = strings.Trim(c[1], "\t '\"")
+		}
+	}
+	if !strings.EqualFold(values["DISTRIB_ID"], jujuos.Ubuntu.String()) {
+		return HostInfo{}, false
+	}
+	series, err := resolveVersionSeries(values["DISTRIB_RELEASE"])
+	if err != nil {
+		return HostInfo{}, false
+	}
+	info, ok := detectedSeries(series)
+	info.PrettyName = values["DISTRIB_DESCRIPTION"]
+	return info, ok
+}
+
+// redhatReleasePattern matches the contents of /etc/redhat-release on
+// CentOS, for example "CentOS Linux release 7.9.2009 (Core)".
+var redhatReleasePattern = regexp.MustCompile(`^CentOS (Linux|Stream) release (\d+)`)
+
+// detectRedHatRelease detects CentOS hosts from /etc/redhat-release.
+func detectRedHatRelease(context.Context) (HostInfo, bool) {
+	contents, err := ioutil.ReadFile(redhatReleaseFile)
+	if err != nil {
+		return HostInfo{}, false
+	}
+	release := strings.TrimSpace(string(contents))
+	match := redhatReleasePattern.FindStringSubmatch(release)
+	if match == nil {
+		return HostInfo{}, false
+	}
+	series := "centos" + match[2]
+	if match[1] == "Stream" && series == "centos8" {
+		series = "centos8-stream"
+	}
+	info, ok := detectedSeries(series)
+	info.PrettyName = release
+	return info, ok
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type linuxDetectionSuite struct {
+	testing.CleanupSuite
+	dir string
+}
+
+var _ = gc.Suite(&linuxDetectionSuite{})
+
+func (s *linuxDetectionSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	s.dir = c.MkDir()
+	s.PatchValue(series.OSReleaseFile, filepath.Join(s.dir, "os-release"))
+	s.PatchValue(series.CentOSReleaseFile, filepath.Join(s.dir, "centos-release"))
+	s.PatchValue(series.LSBReleaseFile, filepath.Join(s.dir, "lsb-release"))
+	s.PatchValue(series.RedHatReleaseFile, filepath.Join(s.dir, "redhat-release"))
+}
+
+func (s *linuxDetectionSuite) writeFile(c *gc.C, name, contents string) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, name), []byte(contents), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *linuxDetectionSuite) TestDetectionProviders(c *gc.C) {
+	c.Assert(series.DetectionProviders(), jc.DeepEquals, []string{"os-release", "lsb-release", "redhat-release"})
+}
+
+func (s *linuxDetectionSuite) TestDetectOSRelease(c *gc.C) {
+	s.writeFile(c, "os-release", "ID=ubuntu\nVERSION_ID=\"20.04\"\nPRETTY_NAME=\"Ubuntu 20.04.1 LTS\"\n")
+	s.writeFile(c, "lsb-release", "DISTRIB_ID=Ubuntu\nDISTRIB_RELEASE=18.04\n")
+	info, err := series.DetectHost(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, series.HostInfo{OS: os.Ubuntu, Series: "focal", PrettyName: "Ubuntu 20.04.1 LTS"})
+}
+
+func (s *linuxDetectionSuite) TestDetectLSBRelease(c *gc.C) {
+	s.writeFile(c, "lsb-release", "DISTRIB_ID=Ubuntu\nDISTRIB_RELEASE=18.04\nDISTRIB_CODENAME=bionic\nDISTRIB_DESCRIPTION=\"Ubuntu 18.04.5 LTS\"\n")
+	info, err := series.DetectHost(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, series.HostInfo{OS: os.Ubuntu, Series: "bionic", PrettyName: "Ubuntu 18.04.5 LTS"})
+}
+
+func (s *linuxDetectionSuite) TestDetectRedHatRelease(c *gc.C) {
+	for i, test := range []struct {
+		release string
+		series  string
+	}{
+		{"CentOS Linux release 7.9.2009 (Core)", "centos7"},
+		{"CentOS Stream release 8", "centos8-stream"},
+		{"CentOS Stream release 9", "centos9"},
+	} {
+		c.Logf("test %d: %q", i, test.release)
+		s.writeFile(c, "redhat-release", test.release+"\n")
+		info, err := series.DetectHost(context.Background())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(info, jc.DeepEquals, series.HostInfo{OS: os.CentOS, Series: test.series, PrettyName: test.release})
+	}
+}
+
+func (s *linuxDetectionSuite) TestDetectNothing(c *gc.C) {
+	s.writeFile(c, "redhat-release", "Fedora release 38 (Thirty Eight)\n")
+	_, err := series.DetectHost(context.Background())
+	c.Assert(err, gc.ErrorMatches, "detection provider recognizing the host not found")
+}
+
+func (s *linuxDetectionSuite) TestHostSeriesConsultsRegisteredProviders(c *gc.C) {
+	s.PatchValue(series.AndroidBuildPropFile, filepath.Join(s.dir, "build.prop"))
+	s.writeFile(c, "os-release", "ID=gentoo\nVERSION_ID=2.15\n")
+	hostSeries, err := series.DetectHostSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostSeries, gc.Equals, "genericlinux")
+
+	info := &series.HostInfo{OS: os.GenericLinux, Series: "gentoo2"}
+	unregister, err := series.RegisterDetectionProvider(fakeProvider{name: "gentoo", info: info})
+	c.Assert(err, jc.ErrorIsNil)
+	defer unregister()
+	hostSeries, err = series.DetectHostSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostSeries, gc.Equals, "gentoo2")
+
+	// Recognized hosts aren't passed to the registered providers.
+	s.writeFile(c, "os-release", "ID=ubuntu\nVERSION_ID=\"20.04\"\n")
+	hostSeries, err = series.DetectHostSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostSeries, gc.Equals, "focal")
+}
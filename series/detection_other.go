@@ -0,0 +1,22 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !linux,!windows
+
+package series
+
+import "context"
+
+var platformDetectors = map[string]func(context.Context) (HostInfo, bool){
+	ProviderUname: detectUname,
+}
+
+// detectUname detects the host from the system name and release reported
+// by the kernel.
+func detectUname(context.Context) (HostInfo, bool) {
+	series, err := readSeries()
+	if err != nil {
+		return HostInfo{}, false
+	}
+	return detectedSeries(series)
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type detectionSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&detectionSuite{})
+
+func (s *detectionSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.PatchValue(series.BuiltinProviders, []series.DetectionProvider{
+		fakeProvider{name: "os-release"},
+	})
+}
+
+type fakeProvider struct {
+	name string
+	info *series.HostInfo
+}
+
+func (p fakeProvider) Name() string {
+	return p.name
+}
+
+func (p fakeProvider) Detect(context.Context) (series.HostInfo, bool) {
+	if p.info == nil {
+		return series.HostInfo{}, false
+	}
+	return *p.info, true
+}
+
+func (s *detectionSuite) TestRegisterDetectionProvider(c *gc.C) {
+	_, err := series.DetectHost(context.Background())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	unregister, err := series.RegisterDetectionProvider(fakeProvider{name: "picard"})
+	c.Assert(err, jc.ErrorIsNil)
+	defer unregister()
+	info := &series.HostInfo{OS: os.GenericLinux, Series: "genericlinux"}
+	unregisterRiker, err := series.RegisterDetectionProvider(fakeProvider{name: "riker", info: info})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(series.DetectionProviders(), jc.DeepEquals, []string{"os-release", "picard", "riker"})
+
+	got, err := series.DetectHost(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, *info)
+
+	unregisterRiker()
+	c.Assert(series.DetectionProviders(), jc.DeepEquals, []string{"os-release", "picard"})
+	_, err = series.DetectHost(context.Background())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *detectionSuite) TestRegisterDetectionProviderInvalid(c *gc.C) {
+	_, err := series.RegisterDetectionProvider(fakeProvider{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	_, err = series.RegisterDetectionProvider(fakeProvider{name: "os-release"})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *detectionSuite) TestDetectHostCancelled(c *gc.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := series.DetectHost(ctx)
+	c.Assert(errors.Cause(err), gc.Equals, context.Canceled)
+}
+
+type fakeSpan struct {
+	events []map[string]string
+}
+
+func (s *fakeSpan) AddEvent(name string, attributes map[string]string) {
+	attributes["event"] = name
+	s.events = append(s.events, attributes)
+}
+
+func (s *detectionSuite) TestDetectHostTrace(c *gc.C) {
+	info := &series.HostInfo{OS: os.GenericLinux, Series: "genericlinux"}
+	unregister, err := series.RegisterDetectionProvider(fakeProvider{name: "riker", info: info})
+	c.Assert(err, jc.ErrorIsNil)
+	defer unregister()
+
+	var trace series.DetectionTrace
+	span := &fakeSpan{}
+	ctx := series.WithDetectionTrace(context.Background(), &trace)
+	ctx = series.WithDetectionSpan(ctx, span)
+	_, err = series.DetectHost(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(trace.Steps, gc.HasLen, 2)
+	c.Check(trace.Steps[0].Provider, gc.Equals, "os-release")
+	c.Check(trace.Steps[0].Matched, jc.IsFalse)
+	c.Check(trace.Steps[1].Provider, gc.Equals, "riker")
+	c.Check(trace.Steps[1].Matched, jc.IsTrue)
+	c.Check(trace.Steps[1].Series, gc.Equals, "genericlinux")
+	c.Check(trace.Duration >= trace.Steps[0].Duration+trace.Steps[1].Duration, jc.IsTrue)
+
+	c.Assert(span.events, gc.HasLen, 2)
+	c.Check(span.events[0]["event"], gc.Equals, series.DetectionStepEvent)
+	c.Check(span.events[0]["matched"], gc.Equals, "false")
+	c.Check(span.events[1]["provider"], gc.Equals, "riker")
+	c.Check(span.events[1]["series"], gc.Equals, "genericlinux")
+}
@@ -0,0 +1,21 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import "context"
+
+var platformDetectors = map[string]func(context.Context) (HostInfo, bool){
+	ProviderRegistry: detectRegistry,
+}
+
+// detectRegistry detects the host from the product name in the registry.
+func detectRegistry(context.Context) (HostInfo, bool) {
+	series, err := readSeries()
+	if err != nil {
+		return HostInfo{}, false
+	}
+	info, ok := detectedSeries(series)
+	info.PrettyName = detectPrettyName()
+	return info, ok
+}
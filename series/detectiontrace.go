@@ -0,0 +1,113 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/juju/os"
+)
+
+// DetectionStep records the consultation of one detection provider.
+type DetectionStep struct {
+	Provider string
+	// Duration is how long the provider took to answer.
+	Duration time.Duration
+	// Matched is true if the provider recognized the host, in which case
+	// OS and Series hold what it detected.
+	Matched bool
+	OS      os.OSType
+	Series  string
+}
+
+// DetectionTrace records how DetectHost reached its answer, for diagnosing
+// slow or misbehaving providers on broken hosts.
+type DetectionTrace struct {
+	// Steps holds a step for each provider consulted, in order.
+	Steps []DetectionStep
+	// Duration is how long detection took in total.
+	Duration time.Duration
+}
+
+// DetectionSpan is the part of a tracing span used by DetectHost to report
+// its steps. Adapters for the spans of tracing libraries such as
+// OpenTelemetry implement it.
+type DetectionSpan interface {
+	// AddEvent records an event on the span.
+	AddEvent(name string, attributes map[string]string)
+}
+
+// DetectionStepEvent is the name of the span event added for each
+// provider consulted by DetectHost.
+const DetectionStepEvent = "series.detection-step"
+
+type traceKey struct{}
+
+type spanKey struct{}
+
+// WithDetectionTrace returns a context that makes DetectHost record its
+// steps in trace.
+func WithDetectionTrace(ctx context.Context, trace *DetectionTrace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+// WithDetectionSpan returns a context that makes DetectHost add an event
+// to span for each provider it consults.
+func WithDetectionSpan(ctx context.Context, span DetectionSpan) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// detectionRecorder records detection steps in the trace and span of a
+// context, if it has them.
+type detectionRecorder struct {
+	trace *DetectionTrace
+	span  DetectionSpan
+	start time.Time
+}
+
+func newDetectionRecorder(ctx context.Context) *detectionRecorder {
+	trace, _ := ctx.Value(traceKey{}).(*DetectionTrace)
+	span, _ := ctx.Value(spanKey{}).(DetectionSpan)
+	return &detectionRecorder{trace: trace, span: span, start: time.Now()}
+}
+
+// detect consults the provider, recording the step.
+func (r *detectionRecorder) detect(ctx context.Context, p DetectionProvider) (HostInfo, bool) {
+	start := time.Now()
+	info, ok := p.Detect(ctx)
+	step := DetectionStep{
+		Provider: p.Name(),
+		Duration: time.Since(start),
+		Matched:  ok,
+	}
+	if ok {
+		step.OS = info.OS
+		step.Series = info.Series
+	}
+	if r.trace != nil {
+		r.trace.Steps = append(r.trace.Steps, step)
+	}
+	if r.span != nil {
+		attributes := map[string]string{
+			"provider": step.Provider,
+			"duration": step.Duration.String(),
+			"matched":  strconv.FormatBool(step.Matched),
+		}
+		if ok {
+			attributes["os"] = step.OS.String()
+			attributes["series"] = step.Series
+		}
+		r.span.AddEvent(DetectionStepEvent, attributes)
+	}
+	return info, ok
+}
+
+// finish records the total duration of detection.
+func (r *detectionRecorder) finish() {
+	if r.trace != nil {
+		r.trace.Duration = time.Since(r.start)
+	}
+}
@@ -4,13 +4,19 @@
 package series
 
 import (
+	"bytes"
 	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/juju/errors"
+	jujuos "github.com/juju/os"
 )
 
 // UbuntuDistroInfo references a csv that contains all the distro information
@@ -54,6 +60,7 @@ type DistroInfo struct {
 	mutex      sync.RWMutex
 	path       string
 	info       map[string]DistroInfoSerie
+	modTime    time.Time
 	fileSystem FileSystem
 }
 
@@ -80,40 +87,118 @@ func (d *DistroInfo) Refresh() error {
 	defer func() {
 		_ = f.Close()
 	}()
+	var modTime time.Time
+	if fi, err := f.Stat(); err == nil {
+		modTime = fi.ModTime()
+	}
+
+	result, err := parseDistroInfo(f)
+	if IsDistroInfoDateError(err) {
+		logger.Warningf("ignoring series in %s: %v", d.path, err)
+	} else if err != nil {
+		return errors.Annotatef(err, "reading %s", d.path)
+	}
+
+	// Lock the distro info, as we're going to be updating it.
+	d.mutex.Lock()
+	d.info = result
+	d.modTime = modTime
+	d.mutex.Unlock()
+
+	return nil
+}
+
+// applyDistroInfo records the ubuntu series of parsed distro-info data,
+// which has the precedence of SourceDistroInfo. The caller must hold
+// seriesVersionsMutex.
+func applyDistroInfo(info map[string]DistroInfoSerie) {
+	now := defaultRegistry.today()
 
-	csvReader := csv.NewReader(f)
+	for seriesName, version := range info {
+		if !canOverwrite(seriesName, SourceDistroInfo) {
+			continue
+		}
+		seriesSources[seriesName] = SourceDistroInfo
+
+		var esm bool
+		if existing, ok := ubuntuSeries[seriesName]; ok {
+			esm = existing.ESMSupported
+		}
+
+		// The numeric version may contain a LTS moniker so strip that out.
+		trimmedVersion := strings.TrimSuffix(version.Version, " LTS")
+		seriesVersions[seriesName] = trimmedVersion
+
+		// If the series already exists inside of ubuntuSeries then don't
+		// overwrite that existing one, except to update the supported status.
+		supported := version.Supported(now)
+
+		if us, ok := ubuntuSeries[seriesName]; ok {
+			us.Supported = supported
+			us.CodeName = version.CodeName
+			us.Created = version.Created
+			us.Released = version.Released
+			us.EOL = version.EOL
+			ubuntuSeries[seriesName] = us
+			continue
+		}
+
+		tagUbuntuImages(seriesName, trimmedVersion)
+		ubuntuSeries[seriesName] = seriesVersion{
+			Version:                  version.Version,
+			Supported:                supported,
+			ESMSupported:             esm,
+			LTS:                      version.LTS(),
+			CreatedByLocalDistroInfo: true,
+			CodeName:                 version.CodeName,
+			Created:                  version.Created,
+			Released:                 version.Released,
+			EOL:                      version.EOL,
+		}
+	}
+}
+
+// maxDistroInfoSize bounds how much of a distro-info file is read, so a
+// corrupt or hostile file can't exhaust memory. The real files are a few
+// kilobytes.
+const maxDistroInfoSize = 1 << 20
+
+// parseDistroInfo parses distro-info CSV data, keyed on series. Records
+// before precise, and records that are malformed, are skipped. Records
+// whose dates can't be parsed are skipped too, but are reported: the
+// other records are returned along with DistroInfoDateErrors describing
+// them.
+func parseDistroInfo(r io.Reader) (map[string]DistroInfoSerie, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxDistroInfoSize+1))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) > maxDistroInfoSize {
+		return nil, errors.Errorf("distro-info data exceeds %d bytes", maxDistroInfoSize)
+	}
+
+	csvReader := csv.NewReader(bytes.NewReader(data))
 	csvReader.FieldsPerRecord = -1
 	records, err := csvReader.ReadAll()
 	if err != nil {
-		return errors.Annotatef(err, "reading %s", d.path)
+		return nil, errors.Trace(err)
 	}
 
+	result := make(map[string]DistroInfoSerie)
+	if len(records) == 0 {
+		return result, nil
+	}
 	fieldNames := records[0]
 	records = records[1:]
 
-	result := make(map[string]DistroInfoSerie)
-
 	// We ignore all series prior to precise.
 	var foundPrecise bool
+	var dateErrors DistroInfoDateErrors
 	for _, fields := range records {
 		record, ok := consumeRecord(fieldNames, fields)
-		if !ok {
+		if !ok || !validSeriesName.MatchString(record.Series) {
 			continue
 		}
-
-		createdDate, err := time.Parse(dateFormat, record.Created)
-		if err != nil {
-			continue
-		}
-		releasedDate, err := time.Parse(dateFormat, record.Released)
-		if err != nil {
-			continue
-		}
-		eolDate, err := time.Parse(dateFormat, record.EOL)
-		if err != nil {
-			continue
-		}
-
 		if !foundPrecise {
 			if record.Series != "precise" {
 				continue
@@ -121,22 +206,111 @@ func (d *DistroInfo) Refresh() error {
 			foundPrecise = true
 		}
 
+		var createdDate, releasedDate, eolDate time.Time
+		var dateErr *DistroInfoDateError
+		for _, date := range []struct {
+			field  string
+			value  string
+			parsed *time.Time
+		}{
+			{"created", record.Created, &createdDate},
+			{"release", record.Released, &releasedDate},
+			{"eol", record.EOL, &eolDate},
+		} {
+			parsed, ok := parseDistroInfoDate(date.value)
+			if !ok {
+				dateErr = &DistroInfoDateError{Series: record.Series, Field: date.field, Value: date.value}
+				break
+			}
+			*date.parsed = parsed
+		}
+		if dateErr != nil {
+			dateErrors = append(dateErrors, dateErr)
+			continue
+		}
+
 		result[record.Series] = DistroInfoSerie{
-			Version:  record.Version,
-			CodeName: record.CodeName,
+			Version:  strings.ToValidUTF8(record.Version, "\uFFFD"),
+			CodeName: strings.ToValidUTF8(record.CodeName, "\uFFFD"),
 			Series:   record.Series,
 			Created:  createdDate,
 			Released: releasedDate,
 			EOL:      eolDate,
 		}
 	}
+	if len(dateErrors) > 0 {
+		return result, errors.Trace(dateErrors)
+	}
+	return result, nil
+}
 
-	// Lock the distro info, as we're going to be updating it.
-	d.mutex.Lock()
-	d.info = result
-	d.mutex.Unlock()
+// monthFormat is the format of dates given to the month only, as some
+// early distro-info-data releases gave them.
+const monthFormat = "2006-01"
 
-	return nil
+// parseDistroInfoDate parses a date from distro-info data. As well as full
+// dates, dates given to the month only are accepted, meaning the last day
+// of the month, as the distro-info tools take them. Parsing doesn't depend
+// on the locale.
+func parseDistroInfoDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(dateFormat, value); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(monthFormat, value); err == nil {
+		return t.AddDate(0, 1, -1), true
+	}
+	return time.Time{}, false
+}
+
+// ErrNoDistroInfo is the cause of the errors returned when the registry
+// is set to require distro-info data, with SetRequireDistroInfo, and none
+// has been loaded.
+var ErrNoDistroInfo = errors.New("distro-info data not available")
+
+// IsNoDistroInfoError returns true if err is caused by ErrNoDistroInfo.
+func IsNoDistroInfoError(err error) bool {
+	return errors.Cause(err) == ErrNoDistroInfo
+}
+
+// DistroInfoDateError describes a date in distro-info data that can't be
+// parsed, which causes its series to be ignored.
+type DistroInfoDateError struct {
+	// Series is the series of the record with the date.
+	Series string
+	// Field is the name of the column holding the date.
+	Field string
+	// Value is the date as given.
+	Value string
+}
+
+func (e *DistroInfoDateError) Error() string {
+	return fmt.Sprintf("series %q: invalid %s date %q", e.Series, e.Field, e.Value)
+}
+
+// DistroInfoDateErrors holds every date in distro-info data that can't be
+// parsed.
+type DistroInfoDateErrors []*DistroInfoDateError
+
+func (e DistroInfoDateErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	messages := make([]string, len(e))
+	for i, problem := range e {
+		messages[i] = problem.Error()
+	}
+	return fmt.Sprintf("%d invalid distro-info dates: %s", len(e), strings.Join(messages, "; "))
+}
+
+// IsDistroInfoDateError returns true if err is caused by a
+// DistroInfoDateError, or by DistroInfoDateErrors.
+func IsDistroInfoDateError(err error) bool {
+	switch errors.Cause(err).(type) {
+	case *DistroInfoDateError, DistroInfoDateErrors:
+		return true
+	}
+	return false
 }
 
 // SeriesInfo returns the DistroInfoSerie for the series name.
@@ -189,3 +363,79 @@ func consumeRecord(headers []string, fields []string) (record, bool) {
 	// If the record is malformed then the validity of the record is not ok.
 	return result, !malformed
 }
+
+// distroInfoHeader holds the columns written by WriteDistroInfoCSV, which
+// are those read by DistroInfo.
+var distroInfoHeader = []string{"version", "codename", "series", "created", "release", "eol"}
+
+// WriteDistroInfoCSV writes the known series of the given OS to w in the
+// distro-info CSV format, oldest first, so that series data gathered from
+// definitions and registrations can be used by the standard tooling.
+// Series without release dates are written with empty date fields; LTS
+// series have " LTS" appended to their version, as distro-info does.
+func WriteDistroInfoCSV(w io.Writer, osType jujuos.OSType) error {
+	seriesVersionsMutex.Lock()
+	updateSeriesVersionsOnce()
+	var records []distroInfoRecord
+	for name, record := range knownSeries() {
+		if record.OS == osType {
+			records = append(records, distroInfoRecord{series: name, version: record.seriesVersion})
+		}
+	}
+	seriesVersionsMutex.Unlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i].version, records[j].version
+		if !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return records[i].series < records[j].series
+	})
+
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(distroInfoHeader); err != nil {
+		return errors.Trace(err)
+	}
+	for _, record := range records {
+		if err := csvWriter.Write(record.fields()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	csvWriter.Flush()
+	return errors.Trace(csvWriter.Error())
+}
+
+// distroInfoRecord is a series to be written as a distro-info record.
+type distroInfoRecord struct {
+	series  string
+	version seriesVersion
+}
+
+func (r distroInfoRecord) fields() []string {
+	version := strings.TrimSuffix(r.version.Version, " LTS")
+	if r.version.LTS {
+		version += " LTS"
+	}
+	codeName := r.version.CodeName
+	if codeName == "" {
+		codeName = strings.Title(r.series)
+	}
+	return []string{
+		version,
+		codeName,
+		r.series,
+		formatDistroInfoDate(r.version.Created),
+		formatDistroInfoDate(r.version.Released),
+		formatDistroInfoDate(r.version.EOL),
+	}
+}
+
+func formatDistroInfoDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateFormat)
+}
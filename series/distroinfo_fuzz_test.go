@@ -0,0 +1,33 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build go1.18
+
+package series
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func FuzzParseDistroInfo(f *testing.F) {
+	f.Add(distroInfoContents)
+	f.Add("")
+	f.Add("version,codename,series,created,release,eol\n\"")
+	f.Add(strings.Repeat(`"""`, 1000))
+	f.Fuzz(func(t *testing.T, data string) {
+		info, err := parseDistroInfo(strings.NewReader(data))
+		if err != nil && !IsDistroInfoDateError(err) {
+			return
+		}
+		for name, serie := range info {
+			if !validSeriesName.MatchString(name) {
+				t.Errorf("series %q not valid", name)
+			}
+			if !utf8.ValidString(serie.Version) || !utf8.ValidString(serie.CodeName) {
+				t.Errorf("series %q has invalid UTF-8", name)
+			}
+		}
+	})
+}
@@ -6,6 +6,7 @@ package series
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang/mock/gomock"
@@ -88,6 +89,87 @@ func (s *DistroInfoSuite) TestRefresh(c *gc.C) {
 	c.Assert(ok, jc.IsFalse)
 }
 
+func (s *DistroInfoSuite) TestParseDistroInfoEmpty(c *gc.C) {
+	info, err := parseDistroInfo(strings.NewReader(""))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, gc.HasLen, 0)
+}
+
+func (s *DistroInfoSuite) TestParseDistroInfoTooLarge(c *gc.C) {
+	data := strings.Repeat("x", maxDistroInfoSize+1)
+	_, err := parseDistroInfo(strings.NewReader(data))
+	c.Assert(err, gc.ErrorMatches, `distro-info data exceeds 1048576 bytes`)
+}
+
+func (s *DistroInfoSuite) TestParseDistroInfoSkipsInvalidSeries(c *gc.C) {
+	info, err := parseDistroInfo(strings.NewReader(`version,codename,series,created,release,eol
+12.04 LTS,Precise Pangolin,precise,2011-10-13,2012-04-26,2017-04-26
+99.04,Bad,Bad Series,2019-04-25,2019-10-17,2365-07-17
+` + "99.10,Star \xffTrek,spock,2019-04-25,2019-10-17,2365-07-17\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, gc.HasLen, 2)
+	c.Assert(info["spock"].CodeName, gc.Equals, "Star \uFFFDTrek")
+}
+
+func (s *DistroInfoSuite) TestParseDistroInfoDates(c *gc.C) {
+	info, err := parseDistroInfo(strings.NewReader(`version,codename,series,created,release,eol
+12.04 LTS,Precise Pangolin,precise,2011-10-13,2012-04-26, 2017-04
+99.04,Star Trek,spock,2019-04,2019-10-17,2365-02
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info["precise"].EOL, gc.Equals, time.Date(2017, 4, 30, 0, 0, 0, 0, time.UTC))
+	c.Check(info["spock"].Created, gc.Equals, time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC))
+	c.Check(info["spock"].EOL, gc.Equals, time.Date(2365, 2, 28, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *DistroInfoSuite) TestParseDistroInfoInvalidDates(c *gc.C) {
+	info, err := parseDistroInfo(strings.NewReader(`version,codename,series,created,release,eol
+12.04 LTS,Precise Pangolin,precise,2011-10-13,2012-04-26,2017-04-26
+99.04,Star Trek,spock,2019-04-25,17/10/2019,2365-07-17
+99.10,Star Trek,kirk,2019-10-17,2020-04-23,soon
+`))
+	c.Assert(err, jc.Satisfies, IsDistroInfoDateError)
+	c.Check(err, gc.ErrorMatches, `2 invalid distro-info dates: series "spock": invalid release date "17/10/2019"; series "kirk": invalid eol date "soon"`)
+	c.Check(info, gc.HasLen, 1)
+	c.Check(info["precise"].Series, gc.Equals, "precise")
+}
+
+func (s *DistroInfoSuite) TestParseDebianDistroInfoInvalidDates(c *gc.C) {
+	releases, err := parseDebianDistroInfo(strings.NewReader(`version,codename,series,created,release,eol,eol-lts
+11,Bullseye,bullseye,2019-07-06,2021-08-14,2024-08-14,2026-08-31
+12,Bookworm,bookworm,2021-08-14,10/06/2023,2026-06-10,2028-06-30
+13,Trixie,trixie,2023-06-10,2025-08-09,2028-08-09,later
+14,Forky,forky,2025-08-09
+,Sid,sid,sometime
+`))
+	c.Assert(err, jc.Satisfies, IsDistroInfoDateError)
+	c.Check(err, gc.ErrorMatches, `3 invalid distro-info dates: series "bookworm": invalid release date "10/06/2023"; `+
+		`series "trixie": invalid eol-lts date "later"; series "sid": invalid created date "sometime"`)
+	c.Assert(releases, gc.HasLen, 2)
+	c.Check(releases[0].series, gc.Equals, "bullseye")
+	c.Check(releases[0].eol, gc.Equals, time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC))
+	c.Check(releases[1].series, gc.Equals, "forky")
+	c.Check(releases[1].released.IsZero(), jc.IsTrue)
+}
+
+func (s *DistroInfoSuite) TestRefreshIgnoresInvalidDates(c *gc.C) {
+	file, err := ioutil.TempFile(c.MkDir(), "ubuntu.csv")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = file.WriteString(`version,codename,series,created,release,eol
+12.04 LTS,Precise Pangolin,precise,2011-10-13,garbage,2017-04-26
+99.04,Star Trek,spock,2019-04-25,2019-10-17,2365-07-17
+`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(file.Close(), jc.ErrorIsNil)
+
+	info := NewDistroInfo(file.Name())
+	c.Assert(info.Refresh(), jc.ErrorIsNil)
+	_, ok := info.SeriesInfo("precise")
+	c.Check(ok, jc.IsFalse)
+	_, ok = info.SeriesInfo("spock")
+	c.Check(ok, jc.IsTrue)
+}
+
 func (s *DistroInfoSuite) TestDistroInfoSerieSupported(c *gc.C) {
 	now := s.fixedTime
 
@@ -0,0 +1,247 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// EndOfLifeBaseURL is the base URL of the endoflife.date API.
+const EndOfLifeBaseURL = "https://endoflife.date/api/"
+
+// endOfLifeProducts maps the endoflife.date products that can be loaded to
+// the operating system of their series.
+var endOfLifeProducts = map[string]os.OSType{
+	"ubuntu":         os.Ubuntu,
+	"centos":         os.CentOS,
+	"debian":         os.Debian,
+	"windows-server": os.Windows,
+	"macos":          os.MacOS,
+}
+
+// EndOfLifeProducts returns the endoflife.date products whose data can be
+// loaded with LoadEndOfLife, sorted.
+func EndOfLifeProducts() []string {
+	products := make([]string, 0, len(endOfLifeProducts))
+	for product := range endOfLifeProducts {
+		products = append(products, product)
+	}
+	sort.Strings(products)
+	return products
+}
+
+// EndOfLifeURL returns the URL of the endoflife.date data for product, for
+// example "https://endoflife.date/api/ubuntu.json".
+func EndOfLifeURL(product string) string {
+	return EndOfLifeBaseURL + product + ".json"
+}
+
+// endOfLifeCycle is a release cycle of a product, in the endoflife.date
+// API format.
+type endOfLifeCycle struct {
+	Cycle       string         `json:"cycle"`
+	Codename    string         `json:"codename"`
+	ReleaseDate endOfLifeDate  `json:"releaseDate"`
+	EOL         endOfLifeDate  `json:"eol"`
+	LTS         endOfLifeDate  `json:"lts"`
+	Extended    *endOfLifeDate `json:"extendedSupport"`
+}
+
+// endOfLifeDate is a field of an endoflife.date cycle that holds either a
+// date or a boolean, such as "eol", which is false for a cycle with no end
+// of life announced.
+type endOfLifeDate struct {
+	Set  bool
+	Date time.Time
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *endOfLifeDate) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.Trace(err)
+	}
+	switch value := value.(type) {
+	case nil:
+		*d = endOfLifeDate{}
+	case bool:
+		*d = endOfLifeDate{Set: value}
+	case string:
+		date, err := time.Parse(dateFormat, value)
+		if err != nil {
+			return errors.NotValidf("date %q", value)
+		}
+		*d = endOfLifeDate{Set: true, Date: date}
+	default:
+		return errors.NotValidf("date %s", data)
+	}
+	return nil
+}
+
+// LoadEndOfLife reads the endoflife.date API data for product from r, and
+// applies it to the known series, recording their release and end of life
+// dates. Cycles that don't correspond to a series of the product are
+// ignored, such as the semi-annual channel releases of Windows Server.
+// The data has the precedence of a definitions file. An error satisfying
+// errors.IsNotSupported is returned for products that aren't modeled by
+// this package.
+func LoadEndOfLife(product string, r io.Reader) error {
+	osType, ok := endOfLifeProducts[product]
+	if !ok {
+		return errors.NotSupportedf("endoflife.date product %q", product)
+	}
+	var cycles []endOfLifeCycle
+	if err := json.NewDecoder(r).Decode(&cycles); err != nil {
+		return errors.Annotatef(err, "decoding endoflife.date %s data", product)
+	}
+	return errors.Trace(update(func() error {
+		applyEndOfLife(osType, cycles)
+		return nil
+	}))
+}
+
+// UpdateFromEndOfLife fetches the endoflife.date data for product and
+// applies it, as LoadEndOfLife does. Fetched data is cached in cacheDir,
+// as it is by UpdateFromURL.
+func UpdateFromEndOfLife(ctx context.Context, product, cacheDir string) error {
+	if _, ok := endOfLifeProducts[product]; !ok {
+		return errors.NotSupportedf("endoflife.date product %q", product)
+	}
+	data, err := NewHTTPSource(EndOfLifeURL(product), cacheDir).Fetch(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(LoadEndOfLife(product, bytes.NewReader(data)))
+}
+
+// applyEndOfLife applies the cycles of a product with series of osType.
+// The caller must hold seriesVersionsMutex.
+func applyEndOfLife(osType os.OSType, cycles []endOfLifeCycle) {
+	now := defaultRegistry.today()
+	for _, cycle := range cycles {
+		name, ok := endOfLifeSeries(osType, cycle)
+		if !ok {
+			logger.Debugf("ignoring endoflife.date %s cycle %q", osType, cycle.Cycle)
+			continue
+		}
+		if existing, err := getOSFromSeries(name); err == nil && existing != osType {
+			logger.Warningf("ignoring endoflife.date %s cycle %q, as %q is a %s series", osType, cycle.Cycle, name, existing)
+			continue
+		}
+		if !canOverwrite(name, SourceDefinitions) {
+			logger.Debugf("ignoring endoflife.date series %q, already provided by %s", name, seriesSources[name])
+			continue
+		}
+		seriesSources[name] = SourceDefinitions
+
+		released, eol := cycle.ReleaseDate.Date, cycle.EOL.Date
+		if osType == os.Debian && cycle.Extended != nil && !cycle.Extended.Date.IsZero() {
+			// The end of life of Debian series is that of LTS support,
+			// as it is in distro-info.
+			eol = cycle.Extended.Date
+		}
+		// The eol field is true, rather than a date, for some cycles that
+		// have ended.
+		ended := cycle.EOL.Set && (eol.IsZero() || !now.Before(eol))
+		supported := !released.IsZero() && now.After(released) && !ended
+		if osType == os.Ubuntu {
+			version := ubuntuSeries[name]
+			version.Version = cycle.Cycle
+			version.LTS = cycle.LTS.Set
+			version.CodeName = cycle.Codename
+			version.Released = released
+			version.EOL = eol
+			version.Supported = supported
+			if cycle.Extended != nil {
+				version.ESMSupported = cycle.Extended.Set
+			}
+			ubuntuSeries[name] = version
+			seriesVersions[name] = cycle.Cycle
+			continue
+		}
+		if _, err := getOSFromSeries(name); err != nil {
+			definedSeriesOS[name] = osType
+		}
+		version := nonUbuntuSeries[name]
+		version.Version = name
+		if osType == os.Debian {
+			// Debian series are named by codename, and versioned by cycle.
+			version.Version = cycle.Cycle
+		}
+		version.Released = released
+		version.EOL = eol
+		version.Supported = supported
+		nonUbuntuSeries[name] = version
+		seriesVersions[name] = version.Version
+	}
+	updateVersionSeries()
+	latestLtsSeries = ""
+}
+
+// windowsServerCycle matches the cycles of the long term releases of
+// Windows Server, for example "2019" and "2012-r2".
+var windowsServerCycle = regexp.MustCompile(`^(\d{4})(-r2)?$`)
+
+// windowsVersionCycle matches the cycles of the Windows Server releases
+// named by version, for example "23h2".
+var windowsVersionCycle = regexp.MustCompile(`^\d{2}h\d$`)
+
+// endOfLifeSeries returns the name of the series of an endoflife.date
+// cycle of a product with series of osType, and false if the cycle
+// doesn't correspond to a series.
+func endOfLifeSeries(osType os.OSType, cycle endOfLifeCycle) (string, bool) {
+	switch osType {
+	case os.Ubuntu, os.Debian:
+		// The series is the first word of the codename, for example
+		// "noble" for "Noble Numbat" or "trixie" for "Trixie".
+		words := strings.Fields(strings.ToLower(cycle.Codename))
+		if len(words) == 0 {
+			return "", false
+		}
+		return words[0], true
+	case os.CentOS:
+		if _, err := strconv.Atoi(cycle.Cycle); err != nil {
+			return "", false
+		}
+		return "centos" + cycle.Cycle, true
+	case os.Windows:
+		// Annual Channel releases are named by version, and are only
+		// modeled if they are known. Semi-annual channel releases are
+		// named by version too, such as "1909" or "20h2", so they aren't.
+		if version := strings.ToLower(cycle.Cycle); windowsVersionCycle.MatchString(version) {
+			name, ok := windowsAnnualChannelSeries[version]
+			return name, ok
+		}
+		// The cycles of other semi-annual channel releases don't match
+		// the year of their release. Long term releases come out within
+		// a year of the year they're named after.
+		match := windowsServerCycle.FindStringSubmatch(cycle.Cycle)
+		if match == nil {
+			return "", false
+		}
+		year, _ := strconv.Atoi(match[1])
+		released := cycle.ReleaseDate.Date.Year()
+		if year < released-1 || year > released+1 {
+			return "", false
+		}
+		return "win" + strings.Replace(cycle.Cycle, "-", "", 1), true
+	case os.MacOS:
+		// The series is the codename without spaces, for example
+		// "highsierra" for "High Sierra".
+		name := strings.Join(strings.Fields(strings.ToLower(cycle.Codename)), "")
+		return name, name != ""
+	}
+	return "", false
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type endOfLifeSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&endOfLifeSuite{})
+
+func (s *endOfLifeSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2098, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+}
+
+func (s *endOfLifeSuite) TestLoadUbuntu(c *gc.C) {
+	err := series.LoadEndOfLife("ubuntu", strings.NewReader(`[{
+		"cycle": "97.04",
+		"codename": "Picard Penguin",
+		"releaseDate": "2097-04-20",
+		"eol": "2102-04-30",
+		"extendedSupport": "2107-04-30",
+		"lts": true
+	}, {
+		"cycle": "96.10",
+		"codename": "Riker Raccoon",
+		"releaseDate": "2096-10-10",
+		"eol": true,
+		"lts": false
+	}]`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	picard, err := series.GetSeries("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(picard.OS, gc.Equals, os.Ubuntu)
+	c.Check(picard.Version, gc.Equals, "97.04")
+	c.Check(picard.LTS, jc.IsTrue)
+	c.Check(picard.CodeName, gc.Equals, "Picard Penguin")
+	c.Check(picard.Released, gc.Equals, time.Date(2097, 4, 20, 0, 0, 0, 0, time.UTC))
+	c.Check(picard.EOL, gc.Equals, time.Date(2102, 4, 30, 0, 0, 0, 0, time.UTC))
+	name, err := series.VersionSeries("97.04")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "picard")
+	source, err := series.SeriesSource("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(source, gc.Equals, series.SourceDefinitions)
+
+	workload := set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+	c.Check(workload.Contains("picard"), jc.IsTrue)
+	c.Check(workload.Contains("riker"), jc.IsFalse)
+}
+
+func (s *endOfLifeSuite) TestLoadWindowsServer(c *gc.C) {
+	err := series.LoadEndOfLife("windows-server", strings.NewReader(`[{
+		"cycle": "2097",
+		"releaseDate": "2096-11-13",
+		"eol": "2105-01-09"
+	}, {
+		"cycle": "2004",
+		"releaseDate": "2096-05-27",
+		"eol": "2099-12-14"
+	}, {
+		"cycle": "23h2",
+		"releaseDate": "2023-10-24",
+		"eol": "2098-10-24"
+	}, {
+		"cycle": "20h2",
+		"releaseDate": "2020-10-20",
+		"eol": "2022-08-09"
+	}]`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := series.GetSeries("win23h2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.EOL, gc.Equals, time.Date(2098, 10, 24, 0, 0, 0, 0, time.UTC))
+	_, err = series.GetOSFromSeries("win20h2")
+	c.Check(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+
+	osType, err := series.GetOSFromSeries("win2097")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(osType, gc.Equals, os.Windows)
+	_, err = series.GetOSFromSeries("win2004")
+	c.Check(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *endOfLifeSuite) TestLoadCentOSAndMacOS(c *gc.C) {
+	err := series.LoadEndOfLife("centos", strings.NewReader(`[
+		{"cycle": "97", "releaseDate": "2097-01-01", "eol": false}
+	]`))
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.LoadEndOfLife("macos", strings.NewReader(`[
+		{"cycle": "10.13", "codename": "High Sierra", "releaseDate": "2017-09-25", "eol": "2020-12-01"}
+	]`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	osType, err := series.GetOSFromSeries("centos97")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(osType, gc.Equals, os.CentOS)
+	highSierra, err := series.GetSeries("highsierra")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(highSierra.OS, gc.Equals, os.MacOS)
+	c.Check(highSierra.EOL, gc.Equals, time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *endOfLifeSuite) TestLoadDebian(c *gc.C) {
+	err := series.LoadEndOfLife("debian", strings.NewReader(`[
+		{"cycle": "14", "codename": "Forky", "releaseDate": "2027-08-01", "eol": false, "extendedSupport": false},
+		{"cycle": "13", "codename": "Trixie", "releaseDate": "2025-08-09", "eol": "2028-08-09", "extendedSupport": "2030-06-30"}
+	]`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	forky, err := series.GetSeries("forky")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(forky.OS, gc.Equals, os.Debian)
+	c.Check(forky.Version, gc.Equals, "14")
+	version, err := series.SeriesVersion("forky")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(version, gc.Equals, "14")
+	name, err := series.VersionSeries("14")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "forky")
+
+	// The end of life is that of LTS support.
+	trixie, err := series.GetSeries("trixie")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(trixie.Version, gc.Equals, "13")
+	c.Check(trixie.EOL, gc.Equals, time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *endOfLifeSuite) TestLoadUnsupportedProduct(c *gc.C) {
+	err := series.LoadEndOfLife("fedora", strings.NewReader(`[]`))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(series.EndOfLifeProducts(), jc.DeepEquals, []string{"centos", "debian", "macos", "ubuntu", "windows-server"})
+}
+
+func (s *endOfLifeSuite) TestLoadInvalid(c *gc.C) {
+	err := series.LoadEndOfLife("ubuntu", strings.NewReader(`[{"cycle": "97.04", "eol": "soon"}]`))
+	c.Assert(err, gc.ErrorMatches, `decoding endoflife.date ubuntu data: date "soon" not valid`)
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+	"gopkg.in/yaml.v2"
+)
+
+// Encoding identifies a format the series data can be exported in.
+type Encoding string
+
+const (
+	// EncodingJSON is the series definitions format read by
+	// LoadDefinitions.
+	EncodingJSON Encoding = "json"
+	// EncodingYAML is the series definitions format as YAML, as read from
+	// the fragments of LoadDefinitionsDir.
+	EncodingYAML Encoding = "yaml"
+	// EncodingCSV has a header row followed by a row for each series, with
+	// the columns series, os, version, lts, supported and esm-supported.
+	// It doesn't include the data version.
+	EncodingCSV Encoding = "csv"
+)
+
+// WriteDefinitions writes the currently known series to w as a series
+// definitions document in the given encoding.
+func WriteDefinitions(w io.Writer, encoding Encoding) error {
+	return errors.Trace(CurrentSnapshot().writeDefinitions(w, encoding))
+}
+
+// WriteDefinitionsJSON writes the currently known series to w as a series
+// definitions document.
+func WriteDefinitionsJSON(w io.Writer) error {
+	return errors.Trace(WriteDefinitions(w, EncodingJSON))
+}
+
+// writeDefinitions writes the series in the snapshot to w in the given
+// encoding.
+func (s Snapshot) writeDefinitions(w io.Writer, encoding Encoding) error {
+	doc := s.definitionsDocument()
+	switch encoding {
+	case EncodingJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return errors.Trace(encoder.Encode(doc))
+	case EncodingYAML:
+		return errors.Trace(writeDefinitionsYAML(w, doc))
+	case EncodingCSV:
+		return errors.Trace(writeDefinitionsCSV(w, doc))
+	}
+	return errors.NotSupportedf("encoding %q", encoding)
+}
+
+// definitionsDocument returns the series in the snapshot as a series
+// definitions document, sorted by name. Series of operating systems that
+// can't be defined are left out.
+func (s Snapshot) definitionsDocument() definitions {
+	data := s.get()
+	doc := definitions{
+		Schema:    DefinitionsSchemaVersion,
+		Version:   data.dataVersion.Version,
+		Timestamp: data.dataVersion.Timestamp,
+		Series:    []Definition{},
+	}
+	for name, record := range data.series {
+		osName := os.FormatOSType(record.OS)
+		if osType, err := parseDefinitionOS(osName); err != nil || osType == os.Unknown {
+			continue
+		}
+		doc.Series = append(doc.Series, Definition{
+			Series:       Name(name),
+			OS:           osName,
+			Version:      record.Version,
+			LTS:          record.LTS,
+			Supported:    record.Supported,
+			ESMSupported: record.ESMSupported,
+		})
+	}
+	sort.Slice(doc.Series, func(i, j int) bool {
+		return doc.Series[i].Series < doc.Series[j].Series
+	})
+	return doc
+}
+
+// writeDefinitionsYAML writes the definitions as YAML. The document is
+// converted from its JSON encoding, so the field names and ordering match
+// the JSON format.
+func writeDefinitionsYAML(w io.Writer, doc definitions) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var ordered yaml.MapSlice
+	if err := yaml.Unmarshal(data, &ordered); err != nil {
+		return errors.Trace(err)
+	}
+	data, err = yaml.Marshal(ordered)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = w.Write(data)
+	return errors.Trace(err)
+}
+
+// definitionsCSVHeader is the header row of the CSV encoding.
+var definitionsCSVHeader = []string{"series", "os", "version", "lts", "supported", "esm-supported"}
+
+// writeDefinitionsCSV writes the definitions as CSV.
+func writeDefinitionsCSV(w io.Writer, doc definitions) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(definitionsCSVHeader); err != nil {
+		return errors.Trace(err)
+	}
+	for _, def := range doc.Series {
+		if err := writer.Write([]string{
+			def.Series.String(),
+			def.OS,
+			def.Version,
+			strconv.FormatBool(def.LTS),
+			strconv.FormatBool(def.Supported),
+			strconv.FormatBool(def.ESMSupported),
+		}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	writer.Flush()
+	return errors.Trace(writer.Error())
+}
@@ -4,18 +4,48 @@
 package series
 
 var (
-	UbuntuDistroInfoPath = &UbuntuDistroInfo
-	ReadSeries           = readSeries
-	OSReleaseFile        = &osReleaseFile
+	UbuntuDistroInfoPath   = &UbuntuDistroInfo
+	ReadSeries             = readSeries
+	OSReleaseFile          = &osReleaseFile
+	CentOSReleaseFile      = &centosReleaseFile
+	LSMFile                = &lsmFile
+	SELinuxEnforceFile     = &selinuxEnforceFile
+	AppArmorEnabledFile    = &appArmorEnabledFile
+	DetectMAC              = detectMAC
+	MountsFile             = &mountsFile
+	DetectCgroupVersion    = detectCgroupVersion
+	SystemdRunDir          = &systemdRunDir
+	SnapdSocketFile        = &snapdSocketFile
+	DetectSnapd            = detectSnapd
+	OSTreeBootedFile       = &ostreeBootedFile
+	DetectImmutable        = detectImmutable
+	FIPSEnabledFile        = &fipsEnabledFile
+	CryptoPolicyFile       = &cryptoPolicyFile
+	DetectFIPS             = detectFIPS
+	UptimeFile             = &uptimeFile
+	DetectUptime           = detectUptime
+	BootIDFile             = &bootIDFile
+	DetectBootID           = detectBootID
+	MachineIDFiles         = &machineIDFiles
+	DetectPrettyName       = detectPrettyName
+	SystemctlVersion       = &systemctlVersion
+	DetectSystemd          = detectSystemd
+	KernelReleaseFile      = &kernelReleaseFile
+	LibcVersion            = &libcVersion
+	LookPath               = &lookPath
+	ProbeLinuxCapabilities = probeLinuxCapabilities
+	AndroidBuildPropFile   = &androidBuildPropFile
+	Getprop                = &getprop
+	ReadAndroidSeries      = readAndroidSeries
+	ExplainHostOSRelease   = explainHostOSRelease
+	LSBReleaseFile         = &lsbReleaseFile
+	RedHatReleaseFile      = &redhatReleaseFile
 )
 
 // HideUbuntuSeries hides the global state of the ubuntu series for tests. The
 // function returns a closure, that puts the global state back once called.
-// This is not concurrent safe.
 func HideUbuntuSeries() func() {
-	origSeries := ubuntuSeries
-	ubuntuSeries = make(map[string]seriesVersion)
-	return func() {
-		ubuntuSeries = origSeries
-	}
+	return alterSeriesState(func() {
+		ubuntuSeries = make(map[string]seriesVersion)
+	})
 }
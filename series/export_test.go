@@ -3,26 +3,91 @@
 
 package series
 
+import (
+	"fmt"
+	"time"
+)
+
 var (
 	KernelToMajor                 = kernelToMajor
 	MacOSXSeriesFromKernelVersion = macOSXSeriesFromKernelVersion
 	MacOSXSeriesFromMajorVersion  = macOSXSeriesFromMajorVersion
+	BSDSeriesFromUname            = bsdSeriesFromUname
+	AIXSeriesFromUname            = aixSeriesFromUname
+	BuiltinProviders              = &builtinProviders
+	DetectHostSeries              = detectHostSeries
+	LoadDefinitionsFrom           = loadDefinitions
+	MaxConcurrentProbes           = maxConcurrentProbes
 )
 
+// SetSeriesVersions replaces the series versions for tests. The function
+// returns a closure, that puts all the series state back as it was. Both
+// hold the lock guarding the series state, so other goroutines never see a
+// partial update.
 func SetSeriesVersions(value map[string]string) func() {
-	origVersions := seriesVersions
-	origUpdated := updatedseriesVersions
-	seriesVersions = value
-	updateVersionSeries()
-	updatedseriesVersions = len(value) != 0
-	return func() {
-		seriesVersions = origVersions
-		updateVersionSeries()
-		updatedseriesVersions = origUpdated
-	}
+	return alterSeriesState(func() {
+		seriesVersions = value
+		updatedseriesVersions = len(value) != 0
+	})
 }
 
 // UbuntuSupportedSeries exports the ubuntuSeries for testing.
 func UbuntuSupportedSeries() map[string]seriesVersion {
 	return ubuntuSeries
 }
+
+// HideGenericLinuxProfiles hides the registered generic linux profiles for
+// tests. The function returns a closure, that puts the global state back once
+// called.
+func HideGenericLinuxProfiles() func() {
+	return alterSeriesState(func() {
+		genericLinuxProfiles = make(map[genericLinuxProfile]string)
+		genericLinuxProfileVersions = make(map[string]string)
+	})
+}
+
+// ForgetLoadedSeriesData makes the series state look as if no series data
+// had been loaded yet, for tests. The function returns a closure, that puts
+// the global state back once called.
+func ForgetLoadedSeriesData() func() {
+	return alterSeriesState(func() {
+		seriesSources = make(map[string]Source)
+		updatedseriesVersions = false
+	})
+}
+
+// BackupSeriesState copies the global series state for tests. The function
+// returns a closure, that puts the global state back once called.
+func BackupSeriesState() func() {
+	return alterSeriesState(func() {})
+}
+
+// alterSeriesState calls alter to change the series state, and returns a
+// closure that restores the state as it was before. Both the change and the
+// restore are made while holding seriesVersionsMutex. The state is copied
+// on restore too, so the closure may be called more than once.
+func alterSeriesState(alter func()) func() {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+
+	backup := backupSeriesState()
+	alter()
+	updateVersionSeries()
+	return func() {
+		seriesVersionsMutex.Lock()
+		defer seriesVersionsMutex.Unlock()
+		backup.restore()
+	}
+}
+
+// RunHostProbes runs the detect functions as the probes of GetHostInfo,
+// and returns the host information they record.
+func RunHostProbes(timeout time.Duration, detects ...func() func(*HostInfo)) HostInfo {
+	probes := make([]hostProbe, len(detects))
+	for i, detect := range detects {
+		probes[i] = hostProbe{name: fmt.Sprintf("test %d", i), detect: detect}
+	}
+	var info HostInfo
+	runHostProbes(&info, probes, timeout)
+	return info
+}
@@ -0,0 +1,8 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !linux,!windows,!darwin,!openbsd,!netbsd,!aix
+
+package series
+
+var ReadSeries = readSeries
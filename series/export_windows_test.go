@@ -8,6 +8,8 @@ var (
 	CurrentVersionKey = &currentVersionKey
 	IsNanoKey         = &isNanoKey
 	ReadSeries        = readSeries
-	WindowsVersionMap = windowsVersions
-	WindowsNanoMap    = windowsNanoVersions
+	WindowsVersionMap = windowsVersionTable()
+	WindowsNanoMap    = windowsNanoVersionTable()
+	WineKey           = &wineKey
+	DetectWine        = detectWine
 )
@@ -0,0 +1,121 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/os"
+)
+
+// Predicate reports whether a series is wanted, for use with Filter.
+// Predicates are composed with And, Or and Not.
+type Predicate func(Series) bool
+
+// Filter returns the descriptions of the known series that satisfy the
+// predicate, sorted by name. A nil predicate selects every known series.
+// Series rejected as retired by the default registry are left out. The
+// predicate is called without any locks held, so it may use the other
+// functions of this package.
+func Filter(pred Predicate) []Series {
+	seriesVersionsMutex.Lock()
+	updateSeriesVersionsOnce()
+	known := knownSeries()
+	candidates := make([]Series, 0, len(known))
+	for name, record := range known {
+		if defaultRegistry.checkRetiredLocked(name) != nil {
+			continue
+		}
+		candidates = append(candidates, describeSeries(Name(name), record))
+	}
+	seriesVersionsMutex.Unlock()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+	var result []Series
+	for _, s := range candidates {
+		if pred == nil || pred(s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// And returns a predicate satisfied by series that satisfy all of the
+// predicates.
+func And(preds ...Predicate) Predicate {
+	return func(s Series) bool {
+		for _, pred := range preds {
+			if !pred(s) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate satisfied by series that satisfy any of the
+// predicates.
+func Or(preds ...Predicate) Predicate {
+	return func(s Series) bool {
+		for _, pred := range preds {
+			if pred(s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a predicate satisfied by series that don't satisfy pred.
+func Not(pred Predicate) Predicate {
+	return func(s Series) bool {
+		return !pred(s)
+	}
+}
+
+// ByOS returns a predicate satisfied by series of any of the operating
+// systems.
+func ByOS(osTypes ...os.OSType) Predicate {
+	return func(s Series) bool {
+		for _, osType := range osTypes {
+			if s.OS == osType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// LTSOnly is a predicate satisfied by LTS series.
+func LTSOnly(s Series) bool {
+	return s.LTS
+}
+
+// SupportedAt returns a predicate satisfied by series that are supported
+// at t: those released before t and reaching their end of life after it.
+// Series whose dates aren't known, or whose support is overridden with
+// Tx.SetSupported, are judged by their Supported field.
+func SupportedAt(t time.Time) Predicate {
+	return func(s Series) bool {
+		if s.supportOverridden || s.Released.IsZero() || s.EOL.IsZero() {
+			return s.Supported
+		}
+		return t.After(s.Released) && t.Before(s.EOL)
+	}
+}
+
+// HasTag returns a predicate satisfied by series with the tag attached.
+func HasTag(tag string) Predicate {
+	return func(s Series) bool {
+		for _, attached := range s.Tags {
+			if attached == tag {
+				return true
+			}
+		}
+		return false
+	}
+}
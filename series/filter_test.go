@@ -0,0 +1,87 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type filterSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&filterSuite{})
+
+func (s *filterSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func seriesNames(described []series.Series) []string {
+	names := make([]string, len(described))
+	for i, s := range described {
+		names[i] = string(s.Name)
+	}
+	return names
+}
+
+func (s *filterSuite) TestFilterByOSSupportedAt(c *gc.C) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := series.Filter(series.And(series.ByOS(os.Debian), series.SupportedAt(at)))
+	c.Assert(seriesNames(got), jc.DeepEquals, []string{"bookworm", "bullseye", "buster"})
+
+	got = series.Filter(series.And(series.ByOS(os.CentOS, os.Debian), series.SupportedAt(at.AddDate(3, 0, 0))))
+	c.Assert(seriesNames(got), jc.DeepEquals, []string{"bookworm", "centos9", "trixie"})
+}
+
+func (s *filterSuite) TestFilterLTSAndTags(c *gc.C) {
+	err := series.AddSeriesTags("focal", "fips-capable")
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.AddSeriesTags("eoan", "fips-capable")
+	c.Assert(err, jc.ErrorIsNil)
+
+	got := series.Filter(series.And(series.LTSOnly, series.HasTag("fips-capable")))
+	c.Assert(seriesNames(got), jc.DeepEquals, []string{"focal"})
+	got = series.Filter(series.Or(series.HasTag("fips-capable"), series.ByOS(os.Android)))
+	c.Assert(seriesNames(got), jc.DeepEquals, []string{
+		"android11", "android12", "android13", "android14", "eoan", "focal",
+	})
+}
+
+func (s *filterSuite) TestFilterNot(c *gc.C) {
+	got := series.Filter(series.And(series.ByOS(os.CentOS), series.Not(series.HasTag(series.ImageTag("s390x")))))
+	c.Assert(seriesNames(got), jc.DeepEquals, []string{"centos7", "centos8", "centos8-stream"})
+}
+
+func (s *filterSuite) TestFilterAll(c *gc.C) {
+	all := series.Filter(nil)
+	names := seriesNames(all)
+	c.Assert(sort.StringsAreSorted(names), jc.IsTrue)
+	c.Assert(names, jc.DeepEquals, seriesNames(series.Filter(series.And())))
+	c.Assert(series.Filter(series.Or()), gc.HasLen, 0)
+}
+
+func (s *filterSuite) TestFilterSkipsRejectedRetiredSeries(c *gc.C) {
+	series.DefaultRegistry().SetRejectRetiredSeries(true)
+	for _, name := range seriesNames(series.Filter(series.ByOS(os.Windows))) {
+		c.Check(name, gc.Not(gc.Equals), "win7")
+	}
+}
+
+func (s *filterSuite) TestFilterPredicateMayUsePackage(c *gc.C) {
+	got := series.Filter(func(s series.Series) bool {
+		version, err := series.SeriesVersion(string(s.Name))
+		return err == nil && version == "12"
+	})
+	c.Assert(seriesNames(got), jc.DeepEquals, []string{"bookworm"})
+}
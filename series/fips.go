@@ -0,0 +1,49 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// FIPSInfo describes whether the host runs in FIPS mode, restricting
+// cryptography to FIPS 140 validated algorithms, as compliance gated
+// workloads require.
+type FIPSInfo struct {
+	// Enabled is true if the kernel, or on Windows the system policy,
+	// enforces FIPS mode, as on hosts booted with fips=1 such as those
+	// with Ubuntu Pro FIPS enabled.
+	Enabled bool
+	// CryptoPolicy is the system-wide crypto policy that userspace
+	// libraries follow on hosts that have one, such as RHEL 8 and later,
+	// for example "FIPS" or "DEFAULT". It is empty on other hosts.
+	CryptoPolicy string
+}
+
+// Strict reports whether the host runs in FIPS mode throughout: FIPS mode
+// is enabled and, on hosts with a system-wide crypto policy, the policy
+// restricts userspace libraries to it too.
+func (f FIPSInfo) Strict() bool {
+	if !f.Enabled {
+		return false
+	}
+	return f.CryptoPolicy == "" || f.CryptoPolicy == "FIPS" || strings.HasPrefix(f.CryptoPolicy, "FIPS:")
+}
+
+// CheckFIPS returns an error satisfying errors.IsNotSupported unless the
+// host runs in strict FIPS mode, so that compliance gated workloads can
+// refuse to start on hosts that aren't. Unlike GetHostInfo it probes
+// nothing else about the host.
+func CheckFIPS() error {
+	fips := detectFIPS()
+	switch {
+	case !fips.Enabled:
+		return errors.NotSupportedf("host without FIPS mode")
+	case !fips.Strict():
+		return errors.NotSupportedf("host with crypto policy %q", fips.CryptoPolicy)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !linux,!windows
+
+package series
+
+// detectFIPS returns whether the host runs in FIPS mode, which is only
+// detected on linux and windows.
+func detectFIPS() FIPSInfo {
+	return FIPSInfo{}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// DefinitionsDir is the conventional directory holding series definition
+// fragments, for use with LoadDefinitionsDir.
+var DefinitionsDir = "/etc/juju/series.d"
+
+// LoadDefinitionsDir reads series definitions from the fragment files in
+// dir and adds them to the known series, as LoadDefinitions does. Files
+// ending in .yaml, .yml or .json are read in lexical order, and a series
+// defined in more than one fragment takes its definition from the last.
+// Other files are ignored, so packaging systems can leave backups behind.
+// A missing directory is not an error. Either all the fragments are
+// applied or none of them are.
+func LoadDefinitionsDir(dir string) error {
+	paths, err := definitionFragments(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	merged := definitions{Schema: DefinitionsSchemaVersion}
+	index := make(map[Name]int)
+	for _, path := range paths {
+		doc, err := readDefinitionFragment(path)
+		if err != nil {
+			return errors.Annotatef(err, "reading %s", path)
+		}
+		if doc.Version != "" {
+			merged.Version = doc.Version
+		}
+		if doc.Timestamp.After(merged.Timestamp) {
+			merged.Timestamp = doc.Timestamp
+		}
+		for _, def := range doc.Series {
+			if i, ok := index[def.Series]; ok {
+				logger.Debugf("series %q from %s overrides an earlier fragment", def.Series, path)
+				merged.Series[i] = def
+				continue
+			}
+			index[def.Series] = len(merged.Series)
+			merged.Series = append(merged.Series, def)
+		}
+	}
+	return errors.Trace(applyDefinitionsDocument(merged, dir))
+}
+
+// definitionFragments returns the paths of the fragment files in dir,
+// sorted lexically.
+func definitionFragments(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// readDefinitionFragment reads and validates a single fragment file.
+func readDefinitionFragment(path string) (definitions, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return definitions{}, errors.Trace(err)
+	}
+	if strings.HasSuffix(path, ".json") {
+		return decodeDefinitions(bytes.NewReader(data))
+	}
+	data, err = yamlToJSON(data)
+	if err != nil {
+		return definitions{}, errors.Trace(err)
+	}
+	return decodeDefinitions(bytes.NewReader(data))
+}
+
+// yamlToJSON converts a YAML document to JSON, so that YAML fragments are
+// checked by the same rules as JSON ones.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Annotate(err, "decoding series definitions")
+	}
+	doc, err := jsonValue(doc)
+	if err != nil {
+		return nil, errors.Annotate(err, "decoding series definitions")
+	}
+	return json.Marshal(doc)
+}
+
+// jsonValue converts the maps produced by the YAML decoder, which have
+// interface{} keys, to maps that can be encoded as JSON.
+func jsonValue(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			key, ok := k.(string)
+			if !ok {
+				return nil, errors.Errorf("key %v is not a string", k)
+			}
+			converted, err := jsonValue(v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			result[key] = converted
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, v := range value {
+			converted, err := jsonValue(v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			result[i] = converted
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type fragmentsSuite struct {
+	testing.CleanupSuite
+	dir string
+}
+
+var _ = gc.Suite(&fragmentsSuite{})
+
+func (s *fragmentsSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	cleanup := series.SetSeriesVersions(make(map[string]string))
+	s.AddCleanup(func(*gc.C) { cleanup() })
+	s.dir = c.MkDir()
+}
+
+func (s *fragmentsSuite) writeFragment(c *gc.C, name, content string) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, name), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *fragmentsSuite) TestLoadDefinitionsDir(c *gc.C) {
+	s.writeFragment(c, "10-spock.yaml", `
+schema: 1
+version: "2020.11"
+timestamp: 2020-11-01T00:00:00Z
+series:
+- series: spock
+  os: ubuntu
+  version: "99.04"
+  lts: true
+- series: centos9
+  os: centos
+  version: centos9
+`)
+	s.writeFragment(c, "20-override.json", `{
+		"schema": 1,
+		"version": "2020.12",
+		"series": [{"series": "spock", "os": "ubuntu", "version": "99.10"}]
+	}`)
+	s.writeFragment(c, "30-ignored.yaml.bak", `not: [valid`)
+
+	err := series.LoadDefinitionsDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	version, err := series.SeriesVersion("spock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "99.10")
+	osType, err := series.GetOSFromSeries("centos9")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.CentOS)
+	c.Assert(series.DataVersion(), jc.DeepEquals, series.DataVersionInfo{
+		Source:    s.dir,
+		Version:   "2020.12",
+		Timestamp: time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC),
+	})
+}
+
+func (s *fragmentsSuite) TestLoadDefinitionsDirInvalidFragment(c *gc.C) {
+	s.writeFragment(c, "10-spock.yaml", `
+schema: 1
+series:
+- series: spock
+  os: ubuntu
+  version: "99.04"
+`)
+	s.writeFragment(c, "20-bad.yaml", `
+schema: 1
+series:
+- series: riker
+  os: ubuntu
+  codename: Riker
+`)
+
+	err := series.LoadDefinitionsDir(s.dir)
+	c.Assert(err, gc.ErrorMatches, `reading .*20-bad.yaml: decoding series definitions: json: unknown field "codename"`)
+
+	// Nothing is applied when any fragment is invalid.
+	_, err = series.SeriesVersion("spock")
+	c.Assert(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+}
+
+func (s *fragmentsSuite) TestLoadDefinitionsDirMissing(c *gc.C) {
+	err := series.LoadDefinitionsDir(filepath.Join(s.dir, "missing"))
+	c.Assert(err, jc.ErrorIsNil)
+}
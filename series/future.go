@@ -0,0 +1,75 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"time"
+)
+
+// FuturePolicy determines how series with a release date in the future,
+// such as the development release of ubuntu, are treated by the supported
+// series lists.
+type FuturePolicy int
+
+const (
+	// FutureExclude leaves future series out of the supported series
+	// lists. This is the default.
+	FutureExclude FuturePolicy = iota
+	// FutureInclude adds future series to the supported series lists,
+	// until their end of life. They are reported in the Future tier by
+	// ByTier.
+	FutureInclude
+	// FutureWarn leaves future series out of the supported series lists
+	// as FutureExclude does, logging a warning for each one.
+	FutureWarn
+)
+
+func (p FuturePolicy) String() string {
+	switch p {
+	case FutureExclude:
+		return "exclude"
+	case FutureInclude:
+		return "include"
+	case FutureWarn:
+		return "warn"
+	}
+	return "unknown"
+}
+
+// SetFuturePolicy sets how series that aren't released yet are treated
+// by the supported series lists. The LTS lists, and so LatestLts, only
+// ever hold released series.
+func (r *Registry) SetFuturePolicy(policy FuturePolicy) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	r.futurePolicy = policy
+	updateVersionSeries()
+}
+
+// FuturePolicy returns how series that aren't released yet are treated.
+func (r *Registry) FuturePolicy() FuturePolicy {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	return r.futurePolicy
+}
+
+// futureAt reports whether the series is released after t.
+func (v seriesVersion) futureAt(t time.Time) bool {
+	return !v.Released.IsZero() && t.Before(v.Released)
+}
+
+// listedWithPolicy reports whether the series belongs in the supported
+// series lists at t, applying the future policy.
+func listedWithPolicy(name string, v seriesVersion, t time.Time, policy FuturePolicy) bool {
+	if v.SupportOverridden || !v.futureAt(t) {
+		return v.supportedAt(t)
+	}
+	switch policy {
+	case FutureInclude:
+		return v.EOL.IsZero() || t.Before(v.EOL)
+	case FutureWarn:
+		logger.Warningf("series %q isn't supported until its release on %s", name, formatDistroInfoDate(v.Released))
+	}
+	return v.supportedAt(t)
+}
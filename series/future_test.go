@@ -0,0 +1,70 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type futureSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&futureSuite{})
+
+func (s *futureSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	// centos9 was released on 2021-12-03.
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	})
+}
+
+func (s *futureSuite) workloadSeries() set.Strings {
+	return set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+}
+
+func (s *futureSuite) TestExcludedByDefault(c *gc.C) {
+	c.Assert(series.DefaultRegistry().FuturePolicy(), gc.Equals, series.FutureExclude)
+	c.Assert(s.workloadSeries().Contains("centos9"), jc.IsFalse)
+	c.Assert(s.workloadSeries().Contains("centos8"), jc.IsTrue)
+}
+
+func (s *futureSuite) TestInclude(c *gc.C) {
+	series.DefaultRegistry().SetFuturePolicy(series.FutureInclude)
+	c.Assert(s.workloadSeries().Contains("centos9"), jc.IsTrue)
+
+	tiers := series.ByTier()
+	c.Assert(set.NewStrings(tiers.Future...).Contains("centos9"), jc.IsTrue)
+	c.Assert(set.NewStrings(tiers.WorkloadOnly...).Contains("centos9"), jc.IsFalse)
+}
+
+func (s *futureSuite) TestWarn(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("future-test", &tw), jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { loggo.RemoveWriter("future-test") })
+
+	series.DefaultRegistry().SetFuturePolicy(series.FutureWarn)
+	c.Assert(s.workloadSeries().Contains("centos9"), jc.IsFalse)
+	c.Assert(tw.Log(), jc.LogMatches, jc.SimpleMessages{{
+		Level:   loggo.WARNING,
+		Message: `series "centos9" isn't supported until its release on 2021-12-03`,
+	}})
+}
+
+func (s *futureSuite) TestFutureTierWithoutInclude(c *gc.C) {
+	tiers := series.ByTier()
+	c.Assert(set.NewStrings(tiers.Future...).Contains("centos9"), jc.IsTrue)
+	c.Assert(set.NewStrings(tiers.Deprecated...).Contains("centos9"), jc.IsFalse)
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// genericLinuxProfile identifies an otherwise unknown linux distribution by
+// the ID and VERSION_ID values found in its os-release file.
+type genericLinuxProfile struct {
+	id        string
+	versionID string
+}
+
+var (
+	// genericLinuxProfiles maps registered profiles to their pseudo-series.
+	genericLinuxProfiles = map[genericLinuxProfile]string{}
+
+	// genericLinuxProfileVersions maps registered pseudo-series to their
+	// version, which follows the same "<id><version>" convention as centos.
+	genericLinuxProfileVersions = map[string]string{}
+)
+
+// RegisterGenericLinuxProfile registers a pseudo-series for hosts that
+// would otherwise be reported as genericlinux, keyed on the ID and
+// VERSION_ID values of their os-release file. An empty versionID matches
+// any version of the distribution that has no more specific profile.
+//
+// The pseudo-series reports an OS of GenericLinux, so that unknown
+// distributions can round-trip a stable identifier without gaining
+// support they don't have.
+func RegisterGenericLinuxProfile(id, versionID, series string) error {
+	id = strings.ToLower(strings.TrimSpace(id))
+	versionID = strings.TrimSpace(versionID)
+	series = FormatSeries(series)
+	if id == "" {
+		return errors.NotValidf("empty os-release ID")
+	}
+	if series == "" {
+		return errors.NotValidf("empty series")
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+
+	if osType, err := getOSFromSeries(series); err == nil && osType != os.GenericLinux {
+		return errors.AlreadyExistsf("%s series %q", osType, series)
+	}
+	if series == genericLinuxSeries {
+		return errors.AlreadyExistsf("series %q", series)
+	}
+
+	defer publishChanges(knownSeries())
+	profile := genericLinuxProfile{id: id, versionID: versionID}
+	if existing, ok := genericLinuxProfiles[profile]; ok && existing != series {
+		return errors.AlreadyExistsf("profile for %q version %q", id, versionID)
+	}
+	genericLinuxProfiles[profile] = series
+	genericLinuxProfileVersions[series] = id + versionID
+	updateVersionSeries()
+	return nil
+}
+
+// genericLinuxProfileSeries returns the pseudo-series registered for the
+// os-release ID and VERSION_ID, preferring an exact version match.
+func genericLinuxProfileSeries(id, versionID string) (string, bool) {
+	id = strings.ToLower(id)
+	if series, ok := genericLinuxProfiles[genericLinuxProfile{id: id, versionID: versionID}]; ok {
+		return series, true
+	}
+	series, ok := genericLinuxProfiles[genericLinuxProfile{id: id}]
+	return series, ok
+}
+
+// IsGenericLinux returns true if series is the genericlinux series, or a
+// pseudo-series registered with RegisterGenericLinuxProfile.
+func IsGenericLinux(series string) bool {
+	osType, err := GetOSFromSeries(series)
+	return err == nil && osType == os.GenericLinux
+}
+
+// ClassificationSignal describes one piece of host information considered
+// when classifying a linux host, and whether it was present.
+type ClassificationSignal struct {
+	// Name identifies the signal, for example the os-release key "ID".
+	Name string
+	// Value is the value of the signal, if present.
+	Value string
+	// Present is true if the host provided the signal.
+	Present bool
+	// Note describes what the signal meant for the classification.
+	Note string
+}
+
+// HostClassification explains why a linux host was given its series, and in
+// particular why it was reported as genericlinux.
+type HostClassification struct {
+	// Series is the series the host was classified as. It is empty if the
+	// distribution was recognized but its release wasn't.
+	Series string
+	// GenericLinux is true if the series is genericlinux or a registered
+	// generic linux profile.
+	GenericLinux bool
+	// Signals holds the signals considered, in the order they were.
+	Signals []ClassificationSignal
+}
+
+// osReleaseDistributions maps the os-release IDs of the linux
+// distributions that have defined series to their OS.
+var osReleaseDistributions = map[string]os.OSType{
+	"ubuntu":        os.Ubuntu,
+	"centos":        os.CentOS,
+	"opensuse":      os.OpenSUSE,
+	"opensuse-leap": os.OpenSUSE,
+	"debian":        os.Debian,
+	"fedora":        os.Fedora,
+	"rhel":          os.RHEL,
+	"rocky":         os.Rocky,
+	"almalinux":     os.AlmaLinux,
+}
+
+// explainOSRelease explains the classification of a host with the
+// os-release values as series, which is empty if it couldn't be
+// determined.
+func explainOSRelease(values map[string]string, series string) HostClassification {
+	result := HostClassification{
+		Series:       series,
+		GenericLinux: series == genericLinuxSeries,
+	}
+	id, versionID := values["ID"], values["VERSION_ID"]
+	osType, recognized := osReleaseDistributions[id]
+
+	idSignal := ClassificationSignal{Name: "ID", Value: id, Present: id != ""}
+	versionSignal := ClassificationSignal{Name: "VERSION_ID", Value: versionID, Present: versionID != ""}
+	switch {
+	case recognized:
+		idSignal.Note = fmt.Sprintf("%s has defined series", osType)
+		switch series {
+		case "":
+			versionSignal.Note = fmt.Sprintf("matches no %s series", osType)
+		case genericLinuxSeries:
+			versionSignal.Note = fmt.Sprintf("matches no %s series, so the host is generic linux", osType)
+		default:
+			versionSignal.Note = fmt.Sprintf("matches series %q", series)
+		}
+	case id == "":
+		idSignal.Note = "missing, so the distribution is unknown"
+	default:
+		idSignal.Note = "no series are defined for this distribution"
+	}
+	result.Signals = append(result.Signals, idSignal, versionSignal)
+
+	if !recognized {
+		profile := ClassificationSignal{Name: "generic linux profile"}
+		seriesVersionsMutex.Lock()
+		profileSeries, ok := genericLinuxProfileSeries(id, versionID)
+		seriesVersionsMutex.Unlock()
+		if ok {
+			profile.Present = true
+			profile.Value = profileSeries
+			profile.Note = fmt.Sprintf("registered for ID %q", id)
+			result.GenericLinux = true
+		} else {
+			profile.Note = fmt.Sprintf("none registered for ID %q version %q", id, versionID)
+		}
+		result.Signals = append(result.Signals, profile)
+	}
+
+	idLike := values["ID_LIKE"]
+	result.Signals = append(result.Signals, ClassificationSignal{
+		Name:    "ID_LIKE",
+		Value:   idLike,
+		Present: idLike != "",
+		Note:    "not used for classification, as derived distributions can differ from their parents",
+	})
+	return result
+}
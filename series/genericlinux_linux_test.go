@@ -0,0 +1,98 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type explainSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&explainSuite{})
+
+func (s *explainSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.HideGenericLinuxProfiles()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *explainSuite) TestExplainRecognized(c *gc.C) {
+	got := series.ExplainHostOSRelease(map[string]string{
+		"ID":         "ubuntu",
+		"VERSION_ID": "20.04",
+		"ID_LIKE":    "debian",
+	})
+	c.Assert(got.Series, gc.Equals, "focal")
+	c.Assert(got.GenericLinux, jc.IsFalse)
+	c.Assert(got.Signals, jc.DeepEquals, []series.ClassificationSignal{{
+		Name: "ID", Value: "ubuntu", Present: true, Note: "Ubuntu has defined series",
+	}, {
+		Name: "VERSION_ID", Value: "20.04", Present: true, Note: `matches series "focal"`,
+	}, {
+		Name: "ID_LIKE", Value: "debian", Present: true,
+		Note: "not used for classification, as derived distributions can differ from their parents",
+	}})
+}
+
+func (s *explainSuite) TestExplainUnknownRelease(c *gc.C) {
+	got := series.ExplainHostOSRelease(map[string]string{
+		"ID":         "centos",
+		"VERSION_ID": "6",
+	})
+	c.Assert(got.Series, gc.Equals, "")
+	c.Assert(got.GenericLinux, jc.IsFalse)
+	c.Assert(got.Signals[1].Note, gc.Equals, "matches no CentOS series")
+}
+
+func (s *explainSuite) TestExplainGenericLinux(c *gc.C) {
+	got := series.ExplainHostOSRelease(map[string]string{
+		"ID":         "gentoo",
+		"VERSION_ID": "2.15",
+	})
+	c.Assert(got.Series, gc.Equals, "genericlinux")
+	c.Assert(got.GenericLinux, jc.IsTrue)
+	c.Assert(got.Signals, jc.DeepEquals, []series.ClassificationSignal{{
+		Name: "ID", Value: "gentoo", Present: true, Note: "no series are defined for this distribution",
+	}, {
+		Name: "VERSION_ID", Value: "2.15", Present: true,
+	}, {
+		Name: "generic linux profile", Note: `none registered for ID "gentoo" version "2.15"`,
+	}, {
+		Name: "ID_LIKE", Note: "not used for classification, as derived distributions can differ from their parents",
+	}})
+}
+
+func (s *explainSuite) TestExplainGenericLinuxProfile(c *gc.C) {
+	err := series.RegisterGenericLinuxProfile("gentoo", "", "gentoo")
+	c.Assert(err, jc.ErrorIsNil)
+	got := series.ExplainHostOSRelease(map[string]string{
+		"ID":         "gentoo",
+		"VERSION_ID": "2.15",
+	})
+	c.Assert(got.Series, gc.Equals, "gentoo")
+	c.Assert(got.GenericLinux, jc.IsTrue)
+	c.Assert(got.Signals[2], jc.DeepEquals, series.ClassificationSignal{
+		Name: "generic linux profile", Value: "gentoo", Present: true, Note: `registered for ID "gentoo"`,
+	})
+}
+
+func (s *explainSuite) TestExplainRecognizedWithoutSeries(c *gc.C) {
+	got := series.ExplainHostOSRelease(map[string]string{
+		"ID":         "fedora",
+		"VERSION_ID": "42",
+	})
+	c.Assert(got.Series, gc.Equals, "genericlinux")
+	c.Assert(got.GenericLinux, jc.IsTrue)
+	c.Assert(got.Signals[:2], jc.DeepEquals, []series.ClassificationSignal{{
+		Name: "ID", Value: "fedora", Present: true, Note: "Fedora has defined series",
+	}, {
+		Name: "VERSION_ID", Value: "42", Present: true, Note: "matches no Fedora series, so the host is generic linux",
+	}})
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type genericLinuxSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&genericLinuxSuite{})
+
+func (s *genericLinuxSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.HideGenericLinuxProfiles()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *genericLinuxSuite) TestRegisterGenericLinuxProfile(c *gc.C) {
+	err := series.RegisterGenericLinuxProfile("Arch", "", " Arch")
+	c.Assert(err, jc.ErrorIsNil)
+
+	osType, err := series.GetOSFromSeries("arch")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.GenericLinux)
+
+	version, err := series.SeriesVersion("arch")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "arch")
+}
+
+func (s *genericLinuxSuite) TestRegisterGenericLinuxProfileVersioned(c *gc.C) {
+	err := series.RegisterGenericLinuxProfile("fedora", "24", "fedora24")
+	c.Assert(err, jc.ErrorIsNil)
+
+	version, err := series.SeriesVersion("fedora24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "fedora24")
+}
+
+func (s *genericLinuxSuite) TestRegisterGenericLinuxProfileInvalid(c *gc.C) {
+	err := series.RegisterGenericLinuxProfile("", "24", "fedora24")
+	c.Assert(err, gc.ErrorMatches, `empty os-release ID not valid`)
+
+	err = series.RegisterGenericLinuxProfile("fedora", "24", "")
+	c.Assert(err, gc.ErrorMatches, `empty series not valid`)
+}
+
+func (s *genericLinuxSuite) TestRegisterGenericLinuxProfileClash(c *gc.C) {
+	err := series.RegisterGenericLinuxProfile("fedora", "24", "bionic")
+	c.Assert(err, gc.ErrorMatches, `Ubuntu series "bionic" already exists`)
+
+	err = series.RegisterGenericLinuxProfile("fedora", "24", "genericlinux")
+	c.Assert(err, gc.ErrorMatches, `series "genericlinux" already exists`)
+
+	err = series.RegisterGenericLinuxProfile("fedora", "24", "fedora24")
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.RegisterGenericLinuxProfile("fedora", "24", "fedora-24")
+	c.Assert(err, gc.ErrorMatches, `profile for "fedora" version "24" already exists`)
+}
+
+func (s *genericLinuxSuite) TestIsGenericLinux(c *gc.C) {
+	err := series.RegisterGenericLinuxProfile("arch", "", "arch")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(series.IsGenericLinux("genericlinux"), jc.IsTrue)
+	c.Assert(series.IsGenericLinux("Arch"), jc.IsTrue)
+	c.Assert(series.IsGenericLinux("focal"), jc.IsFalse)
+	c.Assert(series.IsGenericLinux("sulu"), jc.IsFalse)
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Handler returns a read-only http.Handler serving the current series data
+// as a series definitions document, which UpdateFromURL and
+// LoadDefinitions accept, so that services can distribute series data from
+// a single place. The document is JSON unless another Encoding is chosen
+// with the "format" query parameter, or with an Accept header of
+// application/yaml or text/csv. Responses carry an ETag derived from the
+// DataVersion and the served data, and conditional requests are answered
+// with 304 Not Modified.
+func Handler() http.Handler {
+	return http.HandlerFunc(serveSeries)
+}
+
+func serveSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	encoding, err := requestEncoding(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+	snapshot := CurrentSnapshot()
+	var body bytes.Buffer
+	if err := snapshot.writeDefinitions(&body, encoding); err != nil {
+		logger.Errorf("cannot encode series data: %v", err)
+		http.Error(w, "cannot encode series data", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + snapshot.DataVersion().Version + "-" + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", encodingContentTypes[encoding])
+	for _, match := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if match = strings.TrimSpace(match); match == etag || match == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(body.Bytes())
+}
+
+// encodingContentTypes maps encodings to the content types they're served
+// with.
+var encodingContentTypes = map[Encoding]string{
+	EncodingJSON: "application/json",
+	EncodingYAML: "application/yaml",
+	EncodingCSV:  "text/csv",
+}
+
+// requestEncoding returns the encoding asked for by the request.
+func requestEncoding(r *http.Request) (Encoding, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		encoding := Encoding(strings.ToLower(format))
+		if _, ok := encodingContentTypes[encoding]; !ok {
+			return "", errors.NotSupportedf("format %q", format)
+		}
+		return encoding, nil
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(accept, ";", 2)[0])
+		for encoding, contentType := range encodingContentTypes {
+			if mediaType == contentType {
+				return encoding, nil
+			}
+		}
+	}
+	return EncodingJSON, nil
+}
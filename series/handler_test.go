@@ -0,0 +1,127 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type handlerSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&handlerSuite{})
+
+func (s *handlerSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *handlerSuite) serve(c *gc.C, method, etag string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/series", nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	rec := httptest.NewRecorder()
+	series.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *handlerSuite) TestServeSeries(c *gc.C) {
+	rec := s.serve(c, http.MethodGet, "")
+	c.Assert(rec.Code, gc.Equals, http.StatusOK)
+	c.Assert(rec.Header().Get("Content-Type"), gc.Equals, "application/json")
+	etag := rec.Header().Get("ETag")
+	c.Assert(etag, gc.Not(gc.Equals), "")
+
+	// The served document is accepted as series definitions.
+	err := series.LoadDefinitions(bytes.NewReader(rec.Body.Bytes()))
+	c.Assert(err, jc.ErrorIsNil)
+
+	rec = s.serve(c, http.MethodGet, etag)
+	c.Assert(rec.Code, gc.Equals, http.StatusNotModified)
+
+	err = series.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"})
+	c.Assert(err, jc.ErrorIsNil)
+	rec = s.serve(c, http.MethodGet, etag)
+	c.Assert(rec.Code, gc.Equals, http.StatusOK)
+	c.Assert(rec.Header().Get("ETag"), gc.Not(gc.Equals), etag)
+	c.Assert(rec.Body.String(), jc.Contains, `"series": "picard"`)
+}
+
+func (s *handlerSuite) TestServeSeriesHead(c *gc.C) {
+	rec := s.serve(c, http.MethodHead, "")
+	c.Assert(rec.Code, gc.Equals, http.StatusOK)
+	c.Assert(rec.Header().Get("ETag"), gc.Not(gc.Equals), "")
+	c.Assert(rec.Body.Len(), gc.Equals, 0)
+}
+
+func (s *handlerSuite) TestServeSeriesReadOnly(c *gc.C) {
+	rec := s.serve(c, http.MethodPost, "")
+	c.Assert(rec.Code, gc.Equals, http.StatusMethodNotAllowed)
+	c.Assert(rec.Header().Get("Allow"), gc.Equals, "GET, HEAD")
+}
+
+func (s *handlerSuite) TestWriteDefinitionsYAML(c *gc.C) {
+	err := series.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04", LTS: true})
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = series.WriteDefinitions(&buf, series.EncodingYAML)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), jc.HasPrefix, "schema: 1\n")
+	c.Assert(buf.String(), jc.Contains, "- series: picard\n  os: ubuntu\n  version: \"97.04\"\n  lts: true\n")
+
+	// The YAML is accepted as a definitions fragment.
+	dir := c.MkDir()
+	err = ioutil.WriteFile(filepath.Join(dir, "series.yaml"), buf.Bytes(), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.LoadDefinitionsDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *handlerSuite) TestWriteDefinitionsCSV(c *gc.C) {
+	err := series.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04", LTS: true})
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = series.WriteDefinitions(&buf, series.EncodingCSV)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), jc.HasPrefix, "series,os,version,lts,supported,esm-supported\n")
+	c.Assert(buf.String(), jc.Contains, "\npicard,ubuntu,97.04,true,false,false\n")
+
+	err = series.WriteDefinitions(&buf, "xml")
+	c.Assert(err, gc.ErrorMatches, `encoding "xml" not supported`)
+}
+
+func (s *handlerSuite) TestServeSeriesFormat(c *gc.C) {
+	req := httptest.NewRequest(http.MethodGet, "/series?format=csv", nil)
+	rec := httptest.NewRecorder()
+	series.Handler().ServeHTTP(rec, req)
+	c.Assert(rec.Code, gc.Equals, http.StatusOK)
+	c.Assert(rec.Header().Get("Content-Type"), gc.Equals, "text/csv")
+	c.Assert(rec.Body.String(), jc.HasPrefix, "series,os,version")
+
+	req = httptest.NewRequest(http.MethodGet, "/series", nil)
+	req.Header.Set("Accept", "text/html, application/yaml;q=0.9")
+	rec = httptest.NewRecorder()
+	series.Handler().ServeHTTP(rec, req)
+	c.Assert(rec.Code, gc.Equals, http.StatusOK)
+	c.Assert(rec.Header().Get("Content-Type"), gc.Equals, "application/yaml")
+	c.Assert(rec.Body.String(), jc.HasPrefix, "schema: 1\n")
+
+	req = httptest.NewRequest(http.MethodGet, "/series?format=xml", nil)
+	rec = httptest.NewRecorder()
+	series.Handler().ServeHTTP(rec, req)
+	c.Assert(rec.Code, gc.Equals, http.StatusNotAcceptable)
+}
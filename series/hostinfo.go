@@ -0,0 +1,314 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// MACSystem identifies a mandatory access control system.
+type MACSystem string
+
+const (
+	// MACNone is reported when no mandatory access control system is
+	// active.
+	MACNone MACSystem = "none"
+	// MACAppArmor is reported when AppArmor is active.
+	MACAppArmor MACSystem = "apparmor"
+	// MACSELinux is reported when SELinux is active.
+	MACSELinux MACSystem = "selinux"
+)
+
+// MACInfo describes the mandatory access control system active on a host.
+type MACInfo struct {
+	// System is the active system.
+	System MACSystem
+	// Mode is the mode SELinux is running in, either "enforcing" or
+	// "permissive". It is empty for other systems.
+	Mode string
+}
+
+// CgroupVersion identifies the cgroup hierarchy layout of a host.
+type CgroupVersion string
+
+const (
+	// CgroupNone is reported when no cgroup hierarchy is mounted.
+	CgroupNone CgroupVersion = "none"
+	// CgroupV1 is reported for the legacy layout, with a hierarchy per
+	// controller.
+	CgroupV1 CgroupVersion = "v1"
+	// CgroupV2 is reported for the unified hierarchy.
+	CgroupV2 CgroupVersion = "v2"
+	// CgroupHybrid is reported when the v1 hierarchies are mounted along
+	// with a unified hierarchy that has no controllers, as systemd does by
+	// default on many distributions.
+	CgroupHybrid CgroupVersion = "hybrid"
+)
+
+// ImmutableSystem identifies how an immutable host, whose root filesystem
+// can't be modified in place, is updated.
+type ImmutableSystem string
+
+const (
+	// ImmutableNone is reported for hosts whose packages are installed in
+	// place by the package manager.
+	ImmutableNone ImmutableSystem = "none"
+	// ImmutableOSTree is reported for ostree based hosts, such as Fedora
+	// Silverblue and Kinoite, which install packages by layering them
+	// onto a new deployment with rpm-ostree.
+	ImmutableOSTree ImmutableSystem = "ostree"
+	// ImmutableTransactional is reported for hosts such as openSUSE
+	// MicroOS, which install packages into a new snapshot with
+	// transactional-update that is used from the next boot.
+	ImmutableTransactional ImmutableSystem = "transactional-update"
+	// ImmutableSnap is reported for Ubuntu Core hosts, which only install
+	// software as snaps.
+	ImmutableSnap ImmutableSystem = "snap"
+)
+
+// ImmutableInfo describes whether a host is immutable, in which case
+// software has to be installed differently.
+type ImmutableInfo struct {
+	// System is how the host is updated.
+	System ImmutableSystem
+	// Variant identifies the immutable distribution, for example
+	// "silverblue", "opensuse-microos" or "ubuntu-core", from its
+	// os-release file. It is empty for mutable hosts.
+	Variant string
+}
+
+// SystemdInfo describes the systemd instance managing a host.
+type SystemdInfo struct {
+	// Running is true if systemd is PID 1. Containers of systemd based
+	// series often run without it.
+	Running bool
+	// Version is the version of the running systemd, or zero if it isn't
+	// running or the version can't be determined.
+	Version int
+}
+
+// LinuxCapabilities describes what a linux host provides, for hosts whose
+// distribution isn't recognized, so that callers can decide what they can
+// do on it.
+type LinuxCapabilities struct {
+	// Systemd is true if systemd is running as PID 1.
+	Systemd bool
+	// PackageManagers holds the package managers found on the host, such
+	// as "apt" or "dnf", in order of preference.
+	PackageManagers []string
+	// GlibcVersion is the version of the GNU C library, for example
+	// "2.31". It is empty if the host uses another C library.
+	GlibcVersion string
+	// KernelVersion is the release of the running kernel, for example
+	// "5.4.0-42-generic".
+	KernelVersion string
+}
+
+// HostInfo describes the machine the current process is running on.
+type HostInfo struct {
+	// OS is the operating system of the host.
+	OS os.OSType
+	// Series is the series of the host.
+	Series string
+	// PrettyName is the vendor's name for the release of the host, as
+	// given by the host, for example "Ubuntu 20.04.1 LTS" from the
+	// PRETTY_NAME of /etc/os-release, or the ProductName in the Windows
+	// registry. It is empty if the host doesn't provide one.
+	PrettyName string
+	// MAC is the mandatory access control system of the host, which
+	// workload confinement has to be configured for.
+	MAC MACInfo
+	// Cgroup is the cgroup layout of the host, which container workloads
+	// need to be compatible with.
+	Cgroup CgroupVersion
+	// Systemd describes whether systemd manages services on the host.
+	Systemd SystemdInfo
+	// Capabilities is probed on GenericLinux hosts only, and is nil for
+	// hosts with a recognized operating system.
+	Capabilities *LinuxCapabilities
+	// Snapd is true if snapd is running on the host, so that snaps can be
+	// installed.
+	Snapd bool
+	// Wine is true if the process is running under Wine or Proton, whose
+	// registry describes the emulated Windows release rather than a real
+	// Windows host.
+	Wine bool
+	// Immutable describes whether the host is immutable, in which case
+	// packages can't be installed in the usual way.
+	Immutable ImmutableInfo
+	// FIPS describes whether the host runs in FIPS mode, which compliance
+	// gated workloads need to verify alongside the series.
+	FIPS FIPSInfo
+	// Uptime is how long the host had been running since it booted when
+	// it was probed. It is zero if it can't be determined.
+	Uptime time.Duration
+	// BootID identifies the current boot of the host, and changes when
+	// it reboots, for example the kernel's random boot ID on linux. It is
+	// empty if the host doesn't provide one.
+	BootID string
+	// MachineID is the durable identity of the host, as returned by
+	// MachineID. It is empty if it can't be determined.
+	MachineID string
+}
+
+const (
+	// maxConcurrentProbes bounds how many host probes GetHostInfo runs at
+	// once.
+	maxConcurrentProbes = 4
+	// defaultProbeTimeout bounds how long GetHostInfo waits for each
+	// probe, so that a hung command or filesystem doesn't stall it.
+	defaultProbeTimeout = 5 * time.Second
+)
+
+// probeTimeout is how long GetHostInfo waits for each probe.
+var probeTimeout = defaultProbeTimeout
+
+// hostProbe detects an independent fact about the host, returning a
+// function that records it.
+type hostProbe struct {
+	name   string
+	detect func() func(*HostInfo)
+}
+
+// GetHostInfo returns information about the machine the current process is
+// running on. Host features that can't be detected are reported as absent.
+// The host is probed concurrently, and probes that take too long are
+// abandoned, their features being reported as absent too.
+func GetHostInfo() (HostInfo, error) {
+	// seriesErr is replaced by the result of the series probe, unless it
+	// times out.
+	seriesErr := errors.Errorf("timed out after %v", probeTimeout)
+	probes := []hostProbe{{
+		name: "series",
+		detect: func() func(*HostInfo) {
+			series, err := HostSeries()
+			return func(info *HostInfo) {
+				info.Series, seriesErr = series, err
+			}
+		},
+	}, {
+		name: "pretty name",
+		detect: func() func(*HostInfo) {
+			name := detectPrettyName()
+			return func(info *HostInfo) { info.PrettyName = name }
+		},
+	}, {
+		name: "mandatory access control",
+		detect: func() func(*HostInfo) {
+			mac := detectMAC()
+			return func(info *HostInfo) { info.MAC = mac }
+		},
+	}, {
+		name: "cgroup version",
+		detect: func() func(*HostInfo) {
+			cgroup := detectCgroupVersion()
+			return func(info *HostInfo) { info.Cgroup = cgroup }
+		},
+	}, {
+		name: "systemd",
+		detect: func() func(*HostInfo) {
+			systemd := detectSystemd()
+			return func(info *HostInfo) { info.Systemd = systemd }
+		},
+	}, {
+		name: "snapd",
+		detect: func() func(*HostInfo) {
+			snapd := detectSnapd()
+			return func(info *HostInfo) { info.Snapd = snapd }
+		},
+	}, {
+		name: "wine",
+		detect: func() func(*HostInfo) {
+			wine := detectWine()
+			return func(info *HostInfo) { info.Wine = wine }
+		},
+	}, {
+		name: "immutable",
+		detect: func() func(*HostInfo) {
+			immutable := detectImmutable()
+			return func(info *HostInfo) { info.Immutable = immutable }
+		},
+	}, {
+		name: "fips",
+		detect: func() func(*HostInfo) {
+			fips := detectFIPS()
+			return func(info *HostInfo) { info.FIPS = fips }
+		},
+	}, {
+		name: "uptime",
+		detect: func() func(*HostInfo) {
+			uptime := detectUptime()
+			return func(info *HostInfo) { info.Uptime = uptime }
+		},
+	}, {
+		name: "boot ID",
+		detect: func() func(*HostInfo) {
+			bootID := detectBootID()
+			return func(info *HostInfo) { info.BootID = bootID }
+		},
+	}, {
+		name: "machine ID",
+		detect: func() func(*HostInfo) {
+			machineID := detectMachineID()
+			return func(info *HostInfo) { info.MachineID = machineID }
+		},
+	}}
+
+	info := HostInfo{OS: os.HostOS()}
+	runHostProbes(&info, probes, probeTimeout)
+	if seriesErr != nil {
+		return HostInfo{}, errors.Annotate(seriesErr, "cannot determine host series")
+	}
+	if info.OS == os.GenericLinux {
+		info.Capabilities = probeLinuxCapabilities(info.Systemd)
+	}
+	return info, nil
+}
+
+// runHostProbes runs the probes concurrently, at most maxConcurrentProbes
+// at a time, and records the facts they detect in info, in the order of
+// the probes. Probes that don't finish within timeout are left to finish
+// in the background, and what they detect is discarded.
+func runHostProbes(info *HostInfo, probes []hostProbe, timeout time.Duration) {
+	results := make([]func(*HostInfo), len(probes))
+	slots := make(chan struct{}, maxConcurrentProbes)
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, probe hostProbe) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = runHostProbe(probe, timeout)
+		}(i, probe)
+	}
+	wg.Wait()
+	for _, record := range results {
+		if record != nil {
+			record(info)
+		}
+	}
+}
+
+// runHostProbe runs the probe, returning nil if it doesn't finish within
+// timeout.
+func runHostProbe(probe hostProbe, timeout time.Duration) func(*HostInfo) {
+	done := make(chan func(*HostInfo), 1)
+	go func() {
+		done <- probe.detect()
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case record := <-done:
+		return record
+	case <-timer.C:
+		logger.Warningf("abandoning host %s probe after %v", probe.name, timeout)
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// swVers returns the output of "sw_vers" with the flag, such as
+// "-productName".
+var swVers = func(flag string) ([]byte, error) {
+	return exec.Command("sw_vers", flag).Output()
+}
+
+// sysctl returns the output of "sysctl -n" for the variable, such as
+// "kern.boottime".
+var sysctl = func(name string) ([]byte, error) {
+	return exec.Command("sysctl", "-n", name).Output()
+}
+
+// bootTimePattern matches the seconds of kern.boottime, which sysctl
+// shows as "{ sec = 1718000000, usec = 0 } Mon Jun 10 06:13:20 2024".
+var bootTimePattern = regexp.MustCompile(`sec = (\d+)`)
+
+// detectUptime returns the time since the boot time reported by sysctl.
+func detectUptime() time.Duration {
+	output, err := sysctl("kern.boottime")
+	if err != nil {
+		return 0
+	}
+	match := bootTimePattern.FindSubmatch(output)
+	if match == nil {
+		return 0
+	}
+	seconds, err := strconv.ParseInt(string(match[1]), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Since(time.Unix(seconds, 0))
+}
+
+// detectBootID returns the UUID of the boot session reported by sysctl.
+func detectBootID() string {
+	output, err := sysctl("kern.bootsessionuuid")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// detectPrettyName returns the product name and version reported by
+// sw_vers, for example "macOS 14.5".
+func detectPrettyName() string {
+	var parts []string
+	for _, flag := range []string{"-productName", "-productVersion"} {
+		output, err := swVers(flag)
+		if err != nil {
+			return ""
+		}
+		parts = append(parts, strings.TrimSpace(string(output)))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,267 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	jujuos "github.com/juju/os"
+)
+
+var (
+	// lsmFile lists the active linux security modules.
+	lsmFile = "/sys/kernel/security/lsm"
+	// selinuxEnforceFile holds "1" when SELinux is enforcing, and "0" when
+	// it is permissive. It only exists when SELinux is enabled.
+	selinuxEnforceFile = "/sys/fs/selinux/enforce"
+	// appArmorEnabledFile holds "Y" when AppArmor is enabled.
+	appArmorEnabledFile = "/sys/module/apparmor/parameters/enabled"
+	// mountsFile lists the filesystems mounted in the mount namespace of
+	// the current process.
+	mountsFile = "/proc/self/mounts"
+	// systemdRunDir exists only when systemd is PID 1, see sd_booted(3).
+	systemdRunDir = "/run/systemd/system"
+	// systemctlVersion returns the output of "systemctl --version".
+	systemctlVersion = func() ([]byte, error) {
+		return exec.Command("systemctl", "--version").Output()
+	}
+	// kernelReleaseFile holds the release of the running kernel.
+	kernelReleaseFile = "/proc/sys/kernel/osrelease"
+	// libcVersion returns the output of "getconf GNU_LIBC_VERSION", which
+	// fails on hosts without glibc.
+	libcVersion = func() ([]byte, error) {
+		return exec.Command("getconf", "GNU_LIBC_VERSION").Output()
+	}
+	// snapdSocketFile is the socket snapd listens on while it's running.
+	snapdSocketFile = "/run/snapd.socket"
+	// ostreeBootedFile exists only on hosts booted from an ostree
+	// deployment.
+	ostreeBootedFile = "/run/ostree-booted"
+	// fipsEnabledFile holds "1" when the kernel runs in FIPS mode.
+	fipsEnabledFile = "/proc/sys/crypto/fips_enabled"
+	// cryptoPolicyFile holds the system-wide crypto policy on hosts using
+	// crypto-policies, such as RHEL 8 and later.
+	cryptoPolicyFile = "/etc/crypto-policies/state/current"
+	// uptimeFile holds the seconds since boot, followed by the idle time.
+	uptimeFile = "/proc/uptime"
+	// bootIDFile holds a random UUID generated by the kernel at boot.
+	bootIDFile = "/proc/sys/kernel/random/boot_id"
+	// lookPath finds executables in the PATH.
+	lookPath = exec.LookPath
+)
+
+// packageManagers holds the package managers probed for, in order of
+// preference.
+var packageManagers = []string{"apt", "dnf", "yum", "zypper", "pacman", "apk"}
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// detectMAC returns the mandatory access control system of the host.
+func detectMAC() MACInfo {
+	modules := make(map[string]bool)
+	if contents, err := ioutil.ReadFile(lsmFile); err == nil {
+		for _, module := range strings.Split(strings.TrimSpace(string(contents)), ",") {
+			modules[module] = true
+		}
+	} else {
+		logger.Tracef("cannot read %s: %v", lsmFile, err)
+	}
+
+	// The lsm file needs securityfs to be mounted, so it may not be
+	// available in containers; fall back to the per module files.
+	knownModules := len(modules) > 0
+	if !knownModules || modules["selinux"] {
+		if contents, err := ioutil.ReadFile(selinuxEnforceFile); err == nil {
+			mode := "permissive"
+			if strings.TrimSpace(string(contents)) == "1" {
+				mode = "enforcing"
+			}
+			return MACInfo{System: MACSELinux, Mode: mode}
+		}
+	}
+	if !knownModules || modules["apparmor"] {
+		if contents, err := ioutil.ReadFile(appArmorEnabledFile); err == nil && strings.TrimSpace(string(contents)) == "Y" {
+			return MACInfo{System: MACAppArmor}
+		}
+	}
+	return MACInfo{System: MACNone}
+}
+
+// detectCgroupVersion returns the cgroup layout of the host, from the
+// filesystems mounted at and below /sys/fs/cgroup.
+func detectCgroupVersion() CgroupVersion {
+	contents, err := ioutil.ReadFile(mountsFile)
+	if err != nil {
+		logger.Debugf("cannot read %s: %v", mountsFile, err)
+		return CgroupNone
+	}
+	var v1, unified bool
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		mountPoint, fsType := fields[1], fields[2]
+		switch {
+		case fsType == "cgroup2" && mountPoint == cgroupRoot:
+			return CgroupV2
+		case fsType == "cgroup2" && strings.HasPrefix(mountPoint, cgroupRoot+"/"):
+			unified = true
+		case fsType == "cgroup":
+			v1 = true
+		}
+	}
+	switch {
+	case v1 && unified:
+		return CgroupHybrid
+	case v1:
+		return CgroupV1
+	case unified:
+		return CgroupV2
+	}
+	return CgroupNone
+}
+
+// detectSystemd returns whether systemd is running as PID 1, and if so its
+// version.
+func detectSystemd() SystemdInfo {
+	if fi, err := os.Stat(systemdRunDir); err != nil || !fi.IsDir() {
+		return SystemdInfo{}
+	}
+	info := SystemdInfo{Running: true}
+	output, err := systemctlVersion()
+	if err != nil {
+		logger.Debugf("cannot determine systemd version: %v", err)
+		return info
+	}
+	// The first line is like "systemd 245 (245.4-4ubuntu3)".
+	fields := strings.Fields(string(output))
+	if len(fields) >= 2 && fields[0] == "systemd" {
+		if version, err := strconv.Atoi(fields[1]); err == nil {
+			info.Version = version
+		}
+	}
+	return info
+}
+
+// probeLinuxCapabilities returns what the host provides, given whether
+// systemd is running.
+func probeLinuxCapabilities(systemd SystemdInfo) *LinuxCapabilities {
+	caps := &LinuxCapabilities{Systemd: systemd.Running}
+	for _, name := range packageManagers {
+		if _, err := lookPath(name); err == nil {
+			caps.PackageManagers = append(caps.PackageManagers, name)
+		}
+	}
+	// The output is like "glibc 2.31".
+	if output, err := libcVersion(); err == nil {
+		fields := strings.Fields(string(output))
+		if len(fields) == 2 && fields[0] == "glibc" {
+			caps.GlibcVersion = fields[1]
+		}
+	}
+	if release, err := ioutil.ReadFile(kernelReleaseFile); err == nil {
+		caps.KernelVersion = strings.TrimSpace(string(release))
+	}
+	return caps
+}
+
+// detectSnapd returns whether snapd is running, by looking for its socket.
+func detectSnapd() bool {
+	_, err := os.Stat(snapdSocketFile)
+	return err == nil
+}
+
+// transactionalIDs holds the os-release IDs of the distributions updated
+// with transactional-update.
+var transactionalIDs = map[string]bool{
+	"opensuse-microos":    true,
+	"opensuse-leap-micro": true,
+	"sle-micro":           true,
+}
+
+// ubuntuCoreID is the os-release ID of Ubuntu Core.
+const ubuntuCoreID = "ubuntu-core"
+
+// detectImmutable returns whether the host is immutable, from the marker
+// ostree leaves on booted deployments and the os-release ID.
+func detectImmutable() ImmutableInfo {
+	values, err := jujuos.ReadOSRelease(osReleaseFile)
+	if err != nil {
+		logger.Tracef("cannot read %s: %v", osReleaseFile, err)
+		values = map[string]string{}
+	}
+	id := strings.ToLower(values["ID"])
+	if _, err := os.Stat(ostreeBootedFile); err == nil {
+		variant := strings.ToLower(values["VARIANT_ID"])
+		if variant == "" {
+			variant = id
+		}
+		return ImmutableInfo{System: ImmutableOSTree, Variant: variant}
+	}
+	switch {
+	case id == ubuntuCoreID:
+		return ImmutableInfo{System: ImmutableSnap, Variant: id}
+	case transactionalIDs[id]:
+		return ImmutableInfo{System: ImmutableTransactional, Variant: id}
+	}
+	return ImmutableInfo{System: ImmutableNone}
+}
+
+// detectFIPS returns whether the kernel runs in FIPS mode, and the
+// system-wide crypto policy if the host has one.
+func detectFIPS() FIPSInfo {
+	var info FIPSInfo
+	if contents, err := ioutil.ReadFile(fipsEnabledFile); err == nil {
+		info.Enabled = strings.TrimSpace(string(contents)) == "1"
+	} else {
+		logger.Tracef("cannot read %s: %v", fipsEnabledFile, err)
+	}
+	if contents, err := ioutil.ReadFile(cryptoPolicyFile); err == nil {
+		info.CryptoPolicy = strings.TrimSpace(string(contents))
+	}
+	return info
+}
+
+// detectUptime returns the time since boot from /proc/uptime.
+func detectUptime() time.Duration {
+	contents, err := ioutil.ReadFile(uptimeFile)
+	if err != nil {
+		logger.Tracef("cannot read %s: %v", uptimeFile, err)
+		return 0
+	}
+	fields := strings.Fields(string(contents))
+	if len(fields) == 0 {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// detectBootID returns the boot ID generated by the kernel.
+func detectBootID() string {
+	contents, err := ioutil.ReadFile(bootIDFile)
+	if err != nil {
+		logger.Tracef("cannot read %s: %v", bootIDFile, err)
+		return ""
+	}
+	return strings.TrimSpace(string(contents))
+}
+
+// detectPrettyName returns the PRETTY_NAME from /etc/os-release.
+func detectPrettyName() string {
+	values, err := jujuos.ReadOSRelease(osReleaseFile)
+	if err != nil {
+		return ""
+	}
+	return values["PRETTY_NAME"]
+}
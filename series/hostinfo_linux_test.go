@@ -0,0 +1,258 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type hostInfoSuite struct {
+	testing.CleanupSuite
+	dir string
+}
+
+var _ = gc.Suite(&hostInfoSuite{})
+
+func (s *hostInfoSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.PatchValue(series.LSMFile, filepath.Join(s.dir, "lsm"))
+	s.PatchValue(series.SELinuxEnforceFile, filepath.Join(s.dir, "enforce"))
+	s.PatchValue(series.AppArmorEnabledFile, filepath.Join(s.dir, "enabled"))
+	s.PatchValue(series.MountsFile, filepath.Join(s.dir, "mounts"))
+	s.PatchValue(series.SystemdRunDir, filepath.Join(s.dir, "systemd"))
+	s.PatchValue(series.KernelReleaseFile, filepath.Join(s.dir, "osrelease"))
+	s.PatchValue(series.SnapdSocketFile, filepath.Join(s.dir, "snapd.socket"))
+	s.PatchValue(series.OSTreeBootedFile, filepath.Join(s.dir, "ostree-booted"))
+	s.PatchValue(series.OSReleaseFile, filepath.Join(s.dir, "os-release"))
+	s.PatchValue(series.FIPSEnabledFile, filepath.Join(s.dir, "fips_enabled"))
+	s.PatchValue(series.CryptoPolicyFile, filepath.Join(s.dir, "current"))
+	s.PatchValue(series.UptimeFile, filepath.Join(s.dir, "uptime"))
+	s.PatchValue(series.BootIDFile, filepath.Join(s.dir, "boot_id"))
+}
+
+func (s *hostInfoSuite) writeFile(c *gc.C, name, content string) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, name), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *hostInfoSuite) TestDetectMACNone(c *gc.C) {
+	c.Assert(series.DetectMAC(), jc.DeepEquals, series.MACInfo{System: series.MACNone})
+}
+
+func (s *hostInfoSuite) TestDetectMACAppArmor(c *gc.C) {
+	s.writeFile(c, "lsm", "lockdown,capability,yama,apparmor\n")
+	s.writeFile(c, "enabled", "Y\n")
+	c.Assert(series.DetectMAC(), jc.DeepEquals, series.MACInfo{System: series.MACAppArmor})
+}
+
+func (s *hostInfoSuite) TestDetectMACSELinux(c *gc.C) {
+	s.writeFile(c, "lsm", "capability,selinux\n")
+	s.writeFile(c, "enforce", "1")
+	c.Assert(series.DetectMAC(), jc.DeepEquals, series.MACInfo{System: series.MACSELinux, Mode: "enforcing"})
+
+	s.writeFile(c, "enforce", "0")
+	c.Assert(series.DetectMAC(), jc.DeepEquals, series.MACInfo{System: series.MACSELinux, Mode: "permissive"})
+}
+
+func (s *hostInfoSuite) TestDetectMACInactiveModule(c *gc.C) {
+	// AppArmor may be built in but not in the active module list.
+	s.writeFile(c, "lsm", "capability,yama\n")
+	s.writeFile(c, "enabled", "Y\n")
+	c.Assert(series.DetectMAC(), jc.DeepEquals, series.MACInfo{System: series.MACNone})
+}
+
+func (s *hostInfoSuite) TestDetectMACWithoutSecurityFS(c *gc.C) {
+	s.writeFile(c, "enabled", "Y\n")
+	c.Assert(series.DetectMAC(), jc.DeepEquals, series.MACInfo{System: series.MACAppArmor})
+}
+
+func (s *hostInfoSuite) TestDetectCgroupVersion(c *gc.C) {
+	for i, test := range []struct {
+		mounts string
+		want   series.CgroupVersion
+	}{{
+		mounts: "",
+		want:   series.CgroupNone,
+	}, {
+		mounts: `sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime,nsdelegate 0 0
+`,
+		want: series.CgroupV2,
+	}, {
+		mounts: `tmpfs /sys/fs/cgroup tmpfs ro,nosuid,nodev,noexec,mode=755 0 0
+cgroup2 /sys/fs/cgroup/unified cgroup2 rw,nosuid,nodev,noexec,relatime,nsdelegate 0 0
+cgroup /sys/fs/cgroup/systemd cgroup rw,nosuid,nodev,noexec,relatime,xattr,name=systemd 0 0
+cgroup /sys/fs/cgroup/memory cgroup rw,nosuid,nodev,noexec,relatime,memory 0 0
+`,
+		want: series.CgroupHybrid,
+	}, {
+		mounts: `tmpfs /sys/fs/cgroup tmpfs ro,nosuid,nodev,noexec,mode=755 0 0
+cgroup /sys/fs/cgroup/systemd cgroup rw,nosuid,nodev,noexec,relatime,xattr,name=systemd 0 0
+cgroup /sys/fs/cgroup/cpu,cpuacct cgroup rw,nosuid,nodev,noexec,relatime,cpu,cpuacct 0 0
+`,
+		want: series.CgroupV1,
+	}} {
+		c.Logf("test %d", i)
+		s.writeFile(c, "mounts", test.mounts)
+		c.Check(series.DetectCgroupVersion(), gc.Equals, test.want)
+	}
+}
+
+func (s *hostInfoSuite) TestDetectSystemdNotRunning(c *gc.C) {
+	s.PatchValue(series.SystemctlVersion, func() ([]byte, error) {
+		c.Fatalf("unexpected call")
+		return nil, nil
+	})
+	c.Assert(series.DetectSystemd(), jc.DeepEquals, series.SystemdInfo{})
+}
+
+func (s *hostInfoSuite) TestDetectSystemd(c *gc.C) {
+	err := os.Mkdir(filepath.Join(s.dir, "systemd"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(series.SystemctlVersion, func() ([]byte, error) {
+		return []byte("systemd 245 (245.4-4ubuntu3)\n+PAM +AUDIT +SELINUX\n"), nil
+	})
+	c.Assert(series.DetectSystemd(), jc.DeepEquals, series.SystemdInfo{Running: true, Version: 245})
+}
+
+func (s *hostInfoSuite) TestDetectSystemdUnknownVersion(c *gc.C) {
+	err := os.Mkdir(filepath.Join(s.dir, "systemd"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(series.SystemctlVersion, func() ([]byte, error) {
+		return nil, errors.New("systemctl not found")
+	})
+	c.Assert(series.DetectSystemd(), jc.DeepEquals, series.SystemdInfo{Running: true})
+}
+
+func (s *hostInfoSuite) TestProbeLinuxCapabilities(c *gc.C) {
+	s.writeFile(c, "osrelease", "5.4.0-42-generic\n")
+	s.PatchValue(series.LibcVersion, func() ([]byte, error) {
+		return []byte("glibc 2.31\n"), nil
+	})
+	s.PatchValue(series.LookPath, func(name string) (string, error) {
+		if name == "apt" || name == "dnf" {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	})
+	caps := series.ProbeLinuxCapabilities(series.SystemdInfo{Running: true, Version: 245})
+	c.Assert(caps, jc.DeepEquals, &series.LinuxCapabilities{
+		Systemd:         true,
+		PackageManagers: []string{"apt", "dnf"},
+		GlibcVersion:    "2.31",
+		KernelVersion:   "5.4.0-42-generic",
+	})
+}
+
+func (s *hostInfoSuite) TestProbeLinuxCapabilitiesMinimal(c *gc.C) {
+	s.PatchValue(series.LibcVersion, func() ([]byte, error) {
+		return nil, errors.New("getconf: GNU_LIBC_VERSION: unknown variable")
+	})
+	s.PatchValue(series.LookPath, func(name string) (string, error) {
+		return "", errors.New("not found")
+	})
+	caps := series.ProbeLinuxCapabilities(series.SystemdInfo{})
+	c.Assert(caps, jc.DeepEquals, &series.LinuxCapabilities{})
+}
+
+func (s *hostInfoSuite) TestDetectSnapd(c *gc.C) {
+	c.Assert(series.DetectSnapd(), jc.IsFalse)
+	s.writeFile(c, "snapd.socket", "")
+	c.Assert(series.DetectSnapd(), jc.IsTrue)
+}
+
+func (s *hostInfoSuite) TestDetectImmutable(c *gc.C) {
+	mutable := series.ImmutableInfo{System: series.ImmutableNone}
+	c.Assert(series.DetectImmutable(), jc.DeepEquals, mutable)
+
+	for _, test := range []struct {
+		osRelease string
+		ostree    bool
+		expected  series.ImmutableInfo
+	}{{
+		osRelease: "ID=ubuntu\nVERSION_ID=\"20.04\"\n",
+		expected:  mutable,
+	}, {
+		osRelease: "ID=fedora\nVARIANT_ID=silverblue\n",
+		ostree:    true,
+		expected:  series.ImmutableInfo{System: series.ImmutableOSTree, Variant: "silverblue"},
+	}, {
+		osRelease: "ID=fedora\nVARIANT_ID=kinoite\n",
+		ostree:    true,
+		expected:  series.ImmutableInfo{System: series.ImmutableOSTree, Variant: "kinoite"},
+	}, {
+		osRelease: "ID=fedora\nVARIANT_ID=kinoite\n",
+		expected:  mutable,
+	}, {
+		osRelease: "ID=\"opensuse-microos\"\nID_LIKE=\"suse opensuse opensuse-tumbleweed\"\n",
+		expected:  series.ImmutableInfo{System: series.ImmutableTransactional, Variant: "opensuse-microos"},
+	}, {
+		osRelease: "ID=ubuntu-core\nID_LIKE=ubuntu\nVERSION_ID=\"22\"\n",
+		expected:  series.ImmutableInfo{System: series.ImmutableSnap, Variant: "ubuntu-core"},
+	}} {
+		c.Logf("os-release %q, ostree %v", test.osRelease, test.ostree)
+		s.writeFile(c, "os-release", test.osRelease)
+		_ = os.Remove(filepath.Join(s.dir, "ostree-booted"))
+		if test.ostree {
+			s.writeFile(c, "ostree-booted", "")
+		}
+		c.Check(series.DetectImmutable(), jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *hostInfoSuite) TestDetectFIPS(c *gc.C) {
+	c.Assert(series.DetectFIPS(), jc.DeepEquals, series.FIPSInfo{})
+	c.Assert(series.CheckFIPS(), gc.ErrorMatches, "host without FIPS mode not supported")
+
+	s.writeFile(c, "fips_enabled", "0\n")
+	c.Assert(series.DetectFIPS(), jc.DeepEquals, series.FIPSInfo{})
+
+	s.writeFile(c, "fips_enabled", "1\n")
+	c.Assert(series.DetectFIPS(), jc.DeepEquals, series.FIPSInfo{Enabled: true})
+	c.Assert(series.CheckFIPS(), jc.ErrorIsNil)
+}
+
+func (s *hostInfoSuite) TestDetectFIPSCryptoPolicy(c *gc.C) {
+	s.writeFile(c, "fips_enabled", "1\n")
+	s.writeFile(c, "current", "DEFAULT\n")
+	fips := series.DetectFIPS()
+	c.Assert(fips, jc.DeepEquals, series.FIPSInfo{Enabled: true, CryptoPolicy: "DEFAULT"})
+	c.Assert(fips.Strict(), jc.IsFalse)
+	c.Assert(series.CheckFIPS(), gc.ErrorMatches, `host with crypto policy "DEFAULT" not supported`)
+
+	s.writeFile(c, "current", "FIPS:OSPP\n")
+	c.Assert(series.DetectFIPS().Strict(), jc.IsTrue)
+	c.Assert(series.CheckFIPS(), jc.ErrorIsNil)
+}
+
+func (s *hostInfoSuite) TestDetectUptime(c *gc.C) {
+	c.Assert(series.DetectUptime(), gc.Equals, time.Duration(0))
+	s.writeFile(c, "uptime", "350735.47 234388.90\n")
+	c.Assert(series.DetectUptime(), gc.Equals, 350735*time.Second+470*time.Millisecond)
+	s.writeFile(c, "uptime", "garbage\n")
+	c.Assert(series.DetectUptime(), gc.Equals, time.Duration(0))
+}
+
+func (s *hostInfoSuite) TestDetectBootID(c *gc.C) {
+	c.Assert(series.DetectBootID(), gc.Equals, "")
+	s.writeFile(c, "boot_id", "0a1b2c3d-4e5f-6789-abcd-ef0123456789\n")
+	c.Assert(series.DetectBootID(), gc.Equals, "0a1b2c3d-4e5f-6789-abcd-ef0123456789")
+}
+
+func (s *hostInfoSuite) TestDetectPrettyName(c *gc.C) {
+	c.Assert(series.DetectPrettyName(), gc.Equals, "")
+	s.writeFile(c, "os-release", "ID=ubuntu\nPRETTY_NAME=\"Ubuntu 20.04.1 LTS\"\n")
+	c.Assert(series.DetectPrettyName(), gc.Equals, "Ubuntu 20.04.1 LTS")
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !linux
+
+package series
+
+// detectMAC returns the mandatory access control system of the host, which
+// is only detected on linux.
+func detectMAC() MACInfo {
+	return MACInfo{System: MACNone}
+}
+
+// detectCgroupVersion returns the cgroup layout of the host; cgroups only
+// exist on linux.
+func detectCgroupVersion() CgroupVersion {
+	return CgroupNone
+}
+
+// detectSystemd returns whether systemd is running; it only runs on linux.
+func detectSystemd() SystemdInfo {
+	return SystemdInfo{}
+}
+
+// detectSnapd returns whether snapd is running; it only runs on linux.
+func detectSnapd() bool {
+	return false
+}
+
+// detectImmutable returns whether the host is immutable; only linux
+// distributions are detected as such.
+func detectImmutable() ImmutableInfo {
+	return ImmutableInfo{System: ImmutableNone}
+}
+
+// probeLinuxCapabilities is only meaningful on linux.
+func probeLinuxCapabilities(SystemdInfo) *LinuxCapabilities {
+	return nil
+}
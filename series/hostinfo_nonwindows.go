@@ -0,0 +1,12 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package series
+
+// detectWine returns whether the process is running under Wine, which only
+// emulates windows.
+func detectWine() bool {
+	return false
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !linux,!windows,!darwin
+
+package series
+
+import "time"
+
+// detectPrettyName returns the name of the release of the host, which is
+// only known on linux, windows and darwin.
+func detectPrettyName() string {
+	return ""
+}
+
+// detectUptime returns the time since the host booted, which is only
+// known on linux, windows and darwin.
+func detectUptime() time.Duration {
+	return 0
+}
+
+// detectBootID returns the ID of the boot of the host, which is only known
+// on linux, windows and darwin.
+func detectBootID() string {
+	return ""
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type hostProbeSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&hostProbeSuite{})
+
+func recordSeries(name string) func() func(*series.HostInfo) {
+	return func() func(*series.HostInfo) {
+		return func(info *series.HostInfo) { info.Series = name }
+	}
+}
+
+func (s *hostProbeSuite) TestRecordedInProbeOrder(c *gc.C) {
+	slow := func() func(*series.HostInfo) {
+		time.Sleep(10 * time.Millisecond)
+		return recordSeries("focal")()
+	}
+	info := series.RunHostProbes(time.Minute, slow, recordSeries("jammy"), func() func(*series.HostInfo) {
+		return func(info *series.HostInfo) { info.Snapd = true }
+	})
+	c.Check(info.Series, gc.Equals, "jammy")
+	c.Check(info.Snapd, jc.IsTrue)
+}
+
+func (s *hostProbeSuite) TestSlowProbesAbandoned(c *gc.C) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+	blocked := func() func(*series.HostInfo) {
+		<-unblock
+		return func(info *series.HostInfo) { info.Wine = true }
+	}
+
+	start := time.Now()
+	info := series.RunHostProbes(50*time.Millisecond, blocked, recordSeries("focal"))
+	c.Check(time.Since(start) < 10*time.Second, jc.IsTrue)
+	c.Check(info.Series, gc.Equals, "focal")
+	c.Check(info.Wine, jc.IsFalse)
+}
+
+func (s *hostProbeSuite) TestConcurrencyBounded(c *gc.C) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	probe := func() func(*series.HostInfo) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+	probes := make([]func() func(*series.HostInfo), 3*series.MaxConcurrentProbes)
+	for i := range probes {
+		probes[i] = probe
+	}
+	series.RunHostProbes(time.Minute, probes...)
+	c.Check(maxRunning > 1, jc.IsTrue)
+	c.Check(maxRunning <= series.MaxConcurrentProbes, jc.IsTrue)
+}
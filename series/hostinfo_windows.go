@@ -0,0 +1,93 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+var (
+	// wineKey is created in the registry of every Wine prefix.
+	wineKey = "Software\\Wine"
+
+	// fipsPolicyKey holds the Enabled value, which is 1 when the "Use
+	// FIPS compliant algorithms" security policy is set.
+	fipsPolicyKey = "System\\CurrentControlSet\\Control\\Lsa\\FipsAlgorithmPolicy"
+
+	// prefetchParametersKey holds the BootId value, which counts the
+	// boots of the host.
+	prefetchParametersKey = "System\\CurrentControlSet\\Control\\Session Manager\\Memory Management\\PrefetchParameters"
+
+	// wineGetVersion is exported by the ntdll of Wine, but not Windows.
+	wineGetVersion = windows.NewLazySystemDLL("ntdll.dll").NewProc("wine_get_version")
+
+	// getTickCount64 returns the milliseconds since the host booted.
+	getTickCount64 = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetTickCount64")
+)
+
+// detectWine returns true if the process is running under Wine, or a
+// derivative such as Proton, rather than on Windows.
+func detectWine() bool {
+	if err := wineGetVersion.Find(); err == nil {
+		return true
+	}
+	for _, root := range []registry.Key{registry.LOCAL_MACHINE, registry.CURRENT_USER} {
+		k, err := registry.OpenKey(root, wineKey, registry.QUERY_VALUE)
+		if err == nil {
+			k.Close()
+			return true
+		}
+	}
+	return false
+}
+
+// detectFIPS returns whether the FIPS security policy is enabled. Windows
+// has no separate crypto policy.
+func detectFIPS() FIPSInfo {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, fipsPolicyKey, registry.QUERY_VALUE)
+	if err != nil {
+		return FIPSInfo{}
+	}
+	defer k.Close()
+	enabled, _, err := k.GetIntegerValue("Enabled")
+	return FIPSInfo{Enabled: err == nil && enabled == 1}
+}
+
+// detectUptime returns the time since boot, from GetTickCount64.
+func detectUptime() time.Duration {
+	if err := getTickCount64.Find(); err != nil {
+		return 0
+	}
+	ticks, _, _ := getTickCount64.Call()
+	return time.Duration(ticks) * time.Millisecond
+}
+
+// detectBootID returns the boot count kept in the registry, which
+// identifies the boot as it's incremented whenever the host boots.
+func detectBootID() string {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, prefetchParametersKey, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	defer k.Close()
+	bootID, _, err := k.GetIntegerValue("BootId")
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatUint(bootID, 10)
+}
+
+// detectPrettyName returns the product name from the registry, for example
+// "Windows Server 2019 Datacenter".
+func detectPrettyName() string {
+	name, err := getVersionFromRegistry()
+	if err != nil {
+		return ""
+	}
+	return name
+}
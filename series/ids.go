@@ -0,0 +1,227 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+)
+
+// SeriesID is a stable numeric identifier of a series, for storing series
+// compactly, for example in telemetry. The ID of a series never changes,
+// and IDs are never reused, so stored IDs can be decoded by later
+// releases of this package. The zero SeriesID identifies no series.
+type SeriesID uint16
+
+// FirstRegisteredSeriesID is the lowest ID that can be registered with
+// RegisterSeriesID. Lower IDs are reserved for the series built into this
+// package, including those added in future.
+const FirstRegisteredSeriesID SeriesID = 32768
+
+// builtinSeriesIDs holds the IDs of the series built into this package,
+// in blocks by operating system. Entries must never be changed or
+// removed; new series are given the next ID of their block.
+var builtinSeriesIDs = map[string]SeriesID{
+	"precise":  1000,
+	"quantal":  1001,
+	"raring":   1002,
+	"saucy":    1003,
+	"trusty":   1004,
+	"utopic":   1005,
+	"vivid":    1006,
+	"wily":     1007,
+	"xenial":   1008,
+	"yakkety":  1009,
+	"zesty":    1010,
+	"artful":   1011,
+	"bionic":   1012,
+	"cosmic":   1013,
+	"disco":    1014,
+	"eoan":     1015,
+	"focal":    1016,
+	"groovy":   1017,
+	"hirsute":  1018,
+	"impish":   1019,
+	"jammy":    1020,
+	"kinetic":  1021,
+	"lunar":    1022,
+	"mantic":   1023,
+	"noble":    1024,
+	"oracular": 1025,
+	"plucky":   1026,
+	"questing": 1027,
+
+	"win7":        2000,
+	"win8":        2001,
+	"win81":       2002,
+	"win10":       2003,
+	"win2008r2":   2004,
+	"win2012":     2005,
+	"win2012hv":   2006,
+	"win2012hvr2": 2007,
+	"win2012r2":   2008,
+	"win2016":     2009,
+	"win2016hv":   2010,
+	"win2016nano": 2011,
+	"win2019":     2012,
+	"win23h2":     2013,
+	"win2022":     2014,
+	"win11":       2015,
+
+	"centos7":        3000,
+	"centos8":        3001,
+	"centos8-stream": 3002,
+	"centos9":        3003,
+
+	"opensuseleap": 4000,
+	"opensuse15.4": 4001,
+	"opensuse15.5": 4002,
+	"opensuse15.6": 4003,
+
+	"genericlinux": 5000,
+	"kubernetes":   5100,
+
+	"openbsd7.3": 6000,
+	"openbsd7.4": 6001,
+	"openbsd7.5": 6002,
+	"netbsd9":    6100,
+	"netbsd10":   6101,
+
+	"aix7.2":  7000,
+	"aix7.3":  7001,
+	"ibmi7.4": 7100,
+	"ibmi7.5": 7101,
+
+	"android11": 8000,
+	"android12": 8001,
+	"android13": 8002,
+	"android14": 8003,
+
+	"puma":         9000,
+	"jaguar":       9001,
+	"panther":      9002,
+	"tiger":        9003,
+	"leopard":      9004,
+	"snowleopard":  9005,
+	"lion":         9006,
+	"mountainlion": 9007,
+	"mavericks":    9008,
+	"yosemite":     9009,
+	"elcapitan":    9010,
+	"sierra":       9011,
+	"highsierra":   9012,
+	"mojave":       9013,
+	"catalina":     9014,
+
+	"stretch":  10000,
+	"buster":   10001,
+	"bullseye": 10002,
+	"bookworm": 10003,
+	"trixie":   10004,
+
+	"fedora38": 11000,
+	"fedora39": 11001,
+	"fedora40": 11002,
+	"fedora41": 11003,
+
+	"rhel8": 12000,
+	"rhel9": 12001,
+
+	"rocky8": 13000,
+	"rocky9": 13001,
+
+	"alma8": 14000,
+	"alma9": 14001,
+}
+
+// builtinSeriesNames maps the IDs of builtinSeriesIDs to their series.
+var builtinSeriesNames = reverseSeriesIDs(builtinSeriesIDs)
+
+var (
+	// registeredSeriesIDs holds the IDs registered with RegisterSeriesID,
+	// and registeredSeriesNames the reverse. They are guarded by
+	// seriesVersionsMutex.
+	registeredSeriesIDs   = map[string]SeriesID{}
+	registeredSeriesNames = map[SeriesID]string{}
+)
+
+func reverseSeriesIDs(ids map[string]SeriesID) map[SeriesID]string {
+	names := make(map[SeriesID]string, len(ids))
+	for name, id := range ids {
+		names[id] = name
+	}
+	return names
+}
+
+// RegisterSeriesID gives a series that isn't built into this package, such
+// as one registered at runtime, a stable ID. The ID must be at least
+// FirstRegisteredSeriesID, and it's up to the application to keep using
+// the same ID for the series. An error satisfying errors.IsAlreadyExists
+// is returned if the series or the ID is already in use.
+func RegisterSeriesID(series string, id SeriesID) error {
+	name := FormatSeries(series)
+	if name == "" {
+		return errors.Trace(EmptyInputError{Input: "series"})
+	}
+	if id < FirstRegisteredSeriesID {
+		return errors.NotValidf("series ID %d below %d", id, FirstRegisteredSeriesID)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	if existing, ok := seriesIDLocked(name); ok {
+		return errors.AlreadyExistsf("ID %d for series %q", existing, name)
+	}
+	if existing, ok := seriesFromIDLocked(id); ok {
+		return errors.AlreadyExistsf("series ID %d for series %q", id, existing)
+	}
+	registeredSeriesIDs[name] = id
+	registeredSeriesNames[id] = name
+	return nil
+}
+
+// EncodeSeries returns the ID of the series. An error satisfying
+// errors.IsNotFound is returned if the series has no ID.
+func EncodeSeries(series string) (SeriesID, error) {
+	name := FormatSeries(series)
+	if name == "" {
+		return 0, errors.Trace(EmptyInputError{Input: "series"})
+	}
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	if id, ok := seriesIDLocked(name); ok {
+		return id, nil
+	}
+	return 0, errors.NotFoundf("ID for series %q", series)
+}
+
+// DecodeSeries returns the series with the ID. An error satisfying
+// errors.IsNotFound is returned if no series has the ID.
+func DecodeSeries(id SeriesID) (string, error) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	if name, ok := seriesFromIDLocked(id); ok {
+		return name, nil
+	}
+	return "", errors.NotFoundf("series with ID %d", id)
+}
+
+// seriesIDLocked returns the ID of the normalized series. The caller must
+// hold seriesVersionsMutex.
+func seriesIDLocked(name string) (SeriesID, bool) {
+	if id, ok := builtinSeriesIDs[name]; ok {
+		return id, true
+	}
+	id, ok := registeredSeriesIDs[name]
+	return id, ok
+}
+
+// seriesFromIDLocked returns the series with the ID. The caller must hold
+// seriesVersionsMutex.
+func seriesFromIDLocked(id SeriesID) (string, bool) {
+	if name, ok := builtinSeriesNames[id]; ok {
+		return name, true
+	}
+	name, ok := registeredSeriesNames[id]
+	return name, ok
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type idsSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&idsSuite{})
+
+func (s *idsSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *idsSuite) TestStableIDs(c *gc.C) {
+	// These IDs may be stored, so they must never change.
+	for name, expected := range map[string]series.SeriesID{
+		"precise":  1000,
+		"focal":    1016,
+		"win2019":  2012,
+		"centos7":  3000,
+		"catalina": 9014,
+	} {
+		id, err := series.EncodeSeries(name)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(id, gc.Equals, expected, gc.Commentf("series %q", name))
+	}
+}
+
+func (s *idsSuite) TestEmbeddedSeriesHaveIDs(c *gc.C) {
+	for _, name := range series.CurrentSnapshot().Series() {
+		if source, err := series.SeriesSource(string(name)); err != nil || source != series.SourceEmbedded {
+			continue
+		}
+		id, err := series.EncodeSeries(string(name))
+		c.Assert(err, jc.ErrorIsNil, gc.Commentf("series %q", name))
+		decoded, err := series.DecodeSeries(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(decoded, gc.Equals, string(name))
+	}
+}
+
+func (s *idsSuite) TestRegisterSeriesID(c *gc.C) {
+	_, err := series.EncodeSeries("picard")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = series.RegisterSeriesID("picard", series.FirstRegisteredSeriesID)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := series.EncodeSeries("Picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, series.FirstRegisteredSeriesID)
+	name, err := series.DecodeSeries(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "picard")
+
+	err = series.RegisterSeriesID("picard", series.FirstRegisteredSeriesID+1)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	err = series.RegisterSeriesID("riker", series.FirstRegisteredSeriesID)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	err = series.RegisterSeriesID("focal", series.FirstRegisteredSeriesID+2)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	err = series.RegisterSeriesID("riker", 1)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *idsSuite) TestDecodeUnknownID(c *gc.C) {
+	_, err := series.DecodeSeries(0)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = series.DecodeSeries(65535)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
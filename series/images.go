@@ -0,0 +1,163 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+)
+
+// imageTagPrefix starts the tags marking the architectures that a series
+// has official images for.
+const imageTagPrefix = "images-"
+
+// ImageTag returns the series tag marking series that have official cloud
+// images for the architecture, for example "images-arm64". Series are
+// tagged for amd64, arm64, ppc64el and s390x images.
+func ImageTag(arch string) string {
+	return imageTagPrefix + arch
+}
+
+// imageArchitectures holds the architectures with official cloud images
+// of the series other than ubuntu, whose images are determined by
+// ubuntuImageArchitectures.
+var imageArchitectures = map[string][]string{
+	"win7":           {"amd64"},
+	"win8":           {"amd64"},
+	"win81":          {"amd64"},
+	"win10":          {"amd64"},
+	"win11":          {"amd64"},
+	"win2008r2":      {"amd64"},
+	"win2012":        {"amd64"},
+	"win2012hv":      {"amd64"},
+	"win2012hvr2":    {"amd64"},
+	"win2012r2":      {"amd64"},
+	"win2016":        {"amd64"},
+	"win2016hv":      {"amd64"},
+	"win2016nano":    {"amd64"},
+	"win2019":        {"amd64"},
+	"win2022":        {"amd64"},
+	"win23h2":        {"amd64"},
+	"centos7":        {"amd64", "arm64", "ppc64el"},
+	"centos8":        {"amd64", "arm64", "ppc64el"},
+	"centos8-stream": {"amd64", "arm64", "ppc64el"},
+	"centos9":        {"amd64", "arm64", "ppc64el", "s390x"},
+	"rhel8":          {"amd64", "arm64", "ppc64el", "s390x"},
+	"rhel9":          {"amd64", "arm64", "ppc64el", "s390x"},
+	"rocky8":         {"amd64", "arm64"},
+	"rocky9":         {"amd64", "arm64", "ppc64el", "s390x"},
+	"alma8":          {"amd64", "arm64"},
+	"alma9":          {"amd64", "arm64", "ppc64el", "s390x"},
+	"opensuseleap":   {"amd64"},
+	"opensuse15.4":   {"amd64", "arm64"},
+	"opensuse15.5":   {"amd64", "arm64"},
+	"opensuse15.6":   {"amd64", "arm64"},
+	"stretch":        {"amd64"},
+	"buster":         {"amd64", "arm64"},
+	"bullseye":       {"amd64", "arm64"},
+	"bookworm":       {"amd64", "arm64", "ppc64el"},
+	"trixie":         {"amd64", "arm64", "ppc64el"},
+	"fedora38":       {"amd64", "arm64"},
+	"fedora39":       {"amd64", "arm64"},
+	"fedora40":       {"amd64", "arm64"},
+	"fedora41":       {"amd64", "arm64"},
+}
+
+var (
+	// firstPortsUbuntuVersion is the first ubuntu release with arm64
+	// and ppc64el images.
+	firstPortsUbuntuVersion = []int{14, 4}
+	// firstS390XUbuntuVersion is the first ubuntu release with s390x
+	// images.
+	firstS390XUbuntuVersion = []int{16, 4}
+)
+
+// ubuntuImageArchitectures returns the architectures with official cloud
+// images of the ubuntu release with the version.
+func ubuntuImageArchitectures(version string) []string {
+	parsed, ok := parseConstraintVersion(strings.TrimSuffix(version, " LTS"))
+	if !ok {
+		return nil
+	}
+	arches := []string{"amd64"}
+	if compareVersions(parsed, firstPortsUbuntuVersion) >= 0 {
+		arches = append(arches, "arm64", "ppc64el")
+	}
+	if compareVersions(parsed, firstS390XUbuntuVersion) >= 0 {
+		arches = append(arches, "s390x")
+	}
+	return arches
+}
+
+// defaultSeriesTags returns the tags that the series built into this
+// package start with.
+func defaultSeriesTags() map[string]set.Strings {
+	tags := make(map[string]set.Strings)
+	for name, arches := range imageArchitectures {
+		tags[name] = imageTags(arches)
+	}
+	for name, version := range ubuntuSeries {
+		if arches := ubuntuImageArchitectures(version.Version); len(arches) > 0 {
+			tags[name] = imageTags(arches)
+		}
+	}
+	return tags
+}
+
+// tagUbuntuImages tags a newly found ubuntu series with the architectures
+// of its images, unless it's already tagged. The caller must hold
+// seriesVersionsMutex.
+func tagUbuntuImages(series, version string) {
+	if _, ok := seriesTags[series]; ok {
+		return
+	}
+	if arches := ubuntuImageArchitectures(version); len(arches) > 0 {
+		seriesTags[series] = imageTags(arches)
+	}
+}
+
+func imageTags(arches []string) set.Strings {
+	tags := set.NewStrings()
+	for _, arch := range arches {
+		tags.Add(ImageTag(arch))
+	}
+	return tags
+}
+
+// ImageArchitectures returns the architectures that a known series has
+// official images for, sorted, according to its image tags.
+func ImageArchitectures(series string) ([]string, error) {
+	tags, err := SeriesTags(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var arches []string
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, imageTagPrefix) {
+			arches = append(arches, strings.TrimPrefix(tag, imageTagPrefix))
+		}
+	}
+	sort.Strings(arches)
+	return arches, nil
+}
+
+// CheckImageArchitecture returns an error satisfying errors.IsNotSupported
+// if a known series has no official images for the architecture, so that
+// series and architecture combinations that can't be provisioned are
+// rejected early.
+func CheckImageArchitecture(series, arch string) error {
+	arches, err := ImageArchitectures(series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, candidate := range arches {
+		if candidate == arch {
+			return nil
+		}
+	}
+	return errors.NotSupportedf("%s images of series %q", arch, series)
+}
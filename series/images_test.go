@@ -0,0 +1,81 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type imagesSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&imagesSuite{})
+
+func (s *imagesSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *imagesSuite) TestImageArchitectures(c *gc.C) {
+	for _, test := range []struct {
+		series string
+		arches []string
+	}{
+		{"precise", []string{"amd64"}},
+		{"trusty", []string{"amd64", "arm64", "ppc64el"}},
+		{"focal", []string{"amd64", "arm64", "ppc64el", "s390x"}},
+		{"centos7", []string{"amd64", "arm64", "ppc64el"}},
+		{"centos9", []string{"amd64", "arm64", "ppc64el", "s390x"}},
+		{"opensuse15.5", []string{"amd64", "arm64"}},
+		{"win2019", []string{"amd64"}},
+		{"kubernetes", nil},
+	} {
+		c.Logf("series %q", test.series)
+		arches, err := series.ImageArchitectures(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(arches, jc.DeepEquals, test.arches)
+	}
+	_, err := series.ImageArchitectures("sulu")
+	c.Check(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *imagesSuite) TestSeriesWithImageTag(c *gc.C) {
+	withS390X := set.NewStrings(series.SeriesWithTags(series.ImageTag("s390x"))...)
+	c.Check(withS390X.Contains("jammy"), jc.IsTrue)
+	c.Check(withS390X.Contains("centos9"), jc.IsTrue)
+	c.Check(withS390X.Contains("centos7"), jc.IsFalse)
+	c.Check(withS390X.Contains("win2019"), jc.IsFalse)
+}
+
+func (s *imagesSuite) TestCheckImageArchitecture(c *gc.C) {
+	c.Check(series.CheckImageArchitecture("jammy", "arm64"), jc.ErrorIsNil)
+	err := series.CheckImageArchitecture("win2019", "arm64")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `arm64 images of series "win2019" not supported`)
+
+	err = series.RemoveSeriesTags("jammy", series.ImageTag("arm64"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.CheckImageArchitecture("jammy", "arm64")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *imagesSuite) TestRegisteredSeriesImages(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04", "tags": ["images-arm64"]}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	arches, err := series.ImageArchitectures("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(arches, jc.DeepEquals, []string{"arm64"})
+}
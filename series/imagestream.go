@@ -0,0 +1,62 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// ImageStream is a stream of cloud images that series are published in.
+type ImageStream string
+
+const (
+	// ReleasedStream holds the images of released series.
+	ReleasedStream ImageStream = "released"
+	// DailyStream holds the daily builds of ubuntu series, including the
+	// beta images of series that aren't released yet.
+	DailyStream ImageStream = "daily"
+)
+
+// ImageStreams returns the image streams that a known series has images
+// in. A series that isn't released yet is a beta series, with images in
+// the daily stream only; released ubuntu series are in both streams, and
+// other released series in the released stream.
+func ImageStreams(series string) ([]ImageStream, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	record, ok := knownSeries()[string(name)]
+	if !ok {
+		return nil, errors.Trace(unknownSeries(series, knownSeries()))
+	}
+	switch {
+	case record.futureAt(defaultRegistry.today()):
+		return []ImageStream{DailyStream}, nil
+	case record.OS == os.Ubuntu:
+		return []ImageStream{ReleasedStream, DailyStream}, nil
+	}
+	return []ImageStream{ReleasedStream}, nil
+}
+
+// IsBetaSeries reports whether a known series is only available in the
+// daily image stream, because it isn't released yet.
+func IsBetaSeries(series string) (bool, error) {
+	streams, err := ImageStreams(series)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return len(streams) == 1 && streams[0] == DailyStream, nil
+}
+
+// BetaSeries returns the known series that are only available in the
+// daily image stream, as IsBetaSeries reports, ubuntu series first.
+func BetaSeries() []string {
+	return copyStrings(getSupportedSeriesLists().future)
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type imageStreamSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&imageStreamSuite{})
+
+func (s *imageStreamSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	// centos9 was released on 2021-12-03.
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	})
+}
+
+func (s *imageStreamSuite) TestImageStreams(c *gc.C) {
+	for _, test := range []struct {
+		series  string
+		streams []series.ImageStream
+		beta    bool
+	}{
+		{"focal", []series.ImageStream{series.ReleasedStream, series.DailyStream}, false},
+		{"centos8", []series.ImageStream{series.ReleasedStream}, false},
+		{"centos9", []series.ImageStream{series.DailyStream}, true},
+	} {
+		c.Logf("series %s", test.series)
+		streams, err := series.ImageStreams(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(streams, jc.DeepEquals, test.streams)
+		beta, err := series.IsBetaSeries(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(beta, gc.Equals, test.beta)
+	}
+	c.Check(set.NewStrings(series.BetaSeries()...).Contains("centos9"), jc.IsTrue)
+}
+
+func (s *imageStreamSuite) TestImageStreamsUnknownSeries(c *gc.C) {
+	_, err := series.ImageStreams("firewolf")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *imageStreamSuite) TestIncludeBeta(c *gc.C) {
+	workload := set.NewStrings(series.SupportedJujuWorkloadSeriesWithOptions(series.SupportedSeriesOptions{})...)
+	c.Assert(workload.Contains("centos9"), jc.IsFalse)
+	c.Assert(workload.Contains("centos8"), jc.IsTrue)
+
+	workload = set.NewStrings(series.SupportedJujuWorkloadSeriesWithOptions(series.SupportedSeriesOptions{
+		IncludeBeta: true,
+	})...)
+	c.Assert(workload.Contains("centos9"), jc.IsTrue)
+	c.Assert(workload.Contains("centos8"), jc.IsTrue)
+
+	// The option doesn't change the future policy.
+	c.Assert(series.DefaultRegistry().FuturePolicy(), gc.Equals, series.FutureExclude)
+	c.Assert(set.NewStrings(series.SupportedJujuWorkloadSeries()...).Contains("centos9"), jc.IsFalse)
+}
+
+func (s *imageStreamSuite) TestIncludeBetaController(c *gc.C) {
+	c.Assert(
+		series.SupportedJujuControllerSeriesWithOptions(series.SupportedSeriesOptions{}),
+		jc.DeepEquals, series.SupportedJujuControllerSeries())
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+)
+
+// The lookups whose results are passed to interceptors.
+const (
+	LookupCanonicalSeries      = "CanonicalSeries"
+	LookupHostSeries           = "HostSeries"
+	LookupVersionSeries        = "VersionSeries"
+	LookupWindowsVersionSeries = "WindowsVersionSeries"
+	LookupCentOSVersionSeries  = "CentOSVersionSeries"
+
+	// The supported series lists, including their variants such as
+	// SupportedJujuWorkloadSeriesAt, pass each series they list to the
+	// interceptors, with an empty Input.
+	LookupSupportedJujuControllerSeries = "SupportedJujuControllerSeries"
+	LookupSupportedJujuWorkloadSeries   = "SupportedJujuWorkloadSeries"
+	LookupESMSupportedJujuSeries        = "ESMSupportedJujuSeries"
+)
+
+// Resolution is a series resolved by one of the lookup functions.
+type Resolution struct {
+	// Lookup is the function that resolved the series, such as
+	// LookupVersionSeries.
+	Lookup string
+	// Input is what the series was resolved from, as given to the lookup
+	// function. It is empty for LookupHostSeries.
+	Input string
+	// Series is the resolved series, as rewritten by any earlier
+	// interceptors.
+	Series string
+}
+
+// Interceptor vetoes or rewrites the series resolved by the lookup
+// functions, for organizations with their own naming policies, for
+// example to give "rhel7" wherever "centos7" would be resolved.
+type Interceptor interface {
+	// Intercept returns the series that the lookup should give for the
+	// resolution, or an error that the lookup should fail with.
+	Intercept(resolution Resolution) (string, error)
+}
+
+// InterceptorFunc adapts a function to the Interceptor interface.
+type InterceptorFunc func(resolution Resolution) (string, error)
+
+// Intercept is part of the Interceptor interface.
+func (f InterceptorFunc) Intercept(resolution Resolution) (string, error) {
+	return f(resolution)
+}
+
+// registeredInterceptor is an interceptor added with AddInterceptor.
+type registeredInterceptor struct {
+	id          int
+	interceptor Interceptor
+}
+
+// AddInterceptor adds an interceptor that is applied to the series given
+// by CanonicalSeries, HostSeries, VersionSeries, VersionSeriesAll,
+// WindowsVersionSeries and CentOSVersionSeries, and to the Juju supported
+// series lists: SupportedJujuControllerSeries, SupportedJujuWorkloadSeries,
+// ESMSupportedJujuSeries, their variants and SupportedJujuSeriesInfo, from
+// which vetoed series are left out. Interceptors are applied in the order they
+// were added, each seeing the series given by the one before. They are
+// called without the series data locked, so they may call back into this
+// package.
+//
+// Other lookups aren't intercepted. In particular lookups of the data of a
+// series named by the caller, such as GetOSFromSeries, SeriesVersion and
+// UbuntuSeriesVersion, aren't: interceptors rewrite the names given out,
+// not the series data, and they commonly call these lookups themselves.
+// Callers that validate series they are given should resolve them with
+// CanonicalSeries first. The returned function removes the interceptor.
+func (r *Registry) AddInterceptor(interceptor Interceptor) func() {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	id := r.nextInterceptorID
+	r.nextInterceptorID++
+	r.interceptors = append(r.interceptors, registeredInterceptor{id: id, interceptor: interceptor})
+	return func() {
+		seriesVersionsMutex.Lock()
+		defer seriesVersionsMutex.Unlock()
+		for i, registered := range r.interceptors {
+			if registered.id == id {
+				r.interceptors = append(r.interceptors[:i:i], r.interceptors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// interceptList passes each series of a list through the interceptors,
+// leaving out those vetoed and any duplicates that rewriting makes. The
+// caller must not hold seriesVersionsMutex.
+func (r *Registry) interceptList(lookup string, series []string) []string {
+	seriesVersionsMutex.Lock()
+	intercepted := len(r.interceptors) > 0
+	seriesVersionsMutex.Unlock()
+	if !intercepted {
+		return series
+	}
+
+	result := make([]string, 0, len(series))
+	seen := make(map[string]bool, len(series))
+	for _, name := range series {
+		rewritten, err := r.intercept(lookup, "", name)
+		if err != nil {
+			logger.Debugf("leaving %q out of %s: %v", name, lookup, err)
+			continue
+		}
+		if !seen[rewritten] {
+			seen[rewritten] = true
+			result = append(result, rewritten)
+		}
+	}
+	return result
+}
+
+// intercept passes a resolved series through the interceptors, returning
+// the series to give. The caller must not hold seriesVersionsMutex.
+func (r *Registry) intercept(lookup, input, series string) (string, error) {
+	seriesVersionsMutex.Lock()
+	interceptors := r.interceptors
+	seriesVersionsMutex.Unlock()
+
+	for _, registered := range interceptors {
+		rewritten, err := registered.interceptor.Intercept(Resolution{
+			Lookup: lookup,
+			Input:  input,
+			Series: series,
+		})
+		if err != nil {
+			return "", errors.Annotatef(err, "%s %q", lookup, input)
+		}
+		if rewritten == "" {
+			return "", errors.Errorf("%s %q: interceptor gave no series", lookup, input)
+		}
+		series = rewritten
+	}
+	return series, nil
+}
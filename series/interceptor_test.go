@@ -0,0 +1,156 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type interceptorSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&interceptorSuite{})
+
+func (s *interceptorSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+// rhel rewrites the centos series to the names of the matching RHEL
+// releases.
+var rhel = series.InterceptorFunc(func(r series.Resolution) (string, error) {
+	if r.Series == "centos7" {
+		return "rhel7", nil
+	}
+	return r.Series, nil
+})
+
+func (s *interceptorSuite) TestRewrite(c *gc.C) {
+	var seen []series.Resolution
+	series.DefaultRegistry().AddInterceptor(series.InterceptorFunc(func(r series.Resolution) (string, error) {
+		seen = append(seen, r)
+		return r.Series, nil
+	}))
+	series.DefaultRegistry().AddInterceptor(rhel)
+
+	name, err := series.CentOSVersionSeries("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "rhel7")
+	canonical, err := series.CanonicalSeries("CentOS7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(canonical, gc.Equals, series.Name("rhel7"))
+	name, err = series.VersionSeries("20.04")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "focal")
+
+	c.Check(seen, jc.DeepEquals, []series.Resolution{
+		{Lookup: series.LookupCentOSVersionSeries, Input: "centos7", Series: "centos7"},
+		{Lookup: series.LookupCanonicalSeries, Input: "CentOS7", Series: "centos7"},
+		{Lookup: series.LookupVersionSeries, Input: "20.04", Series: "focal"},
+	})
+}
+
+func (s *interceptorSuite) TestRewriteDoesNotAffectSeriesData(c *gc.C) {
+	series.DefaultRegistry().AddInterceptor(rhel)
+
+	arches, err := series.ImageArchitectures("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(arches, gc.Not(gc.HasLen), 0)
+	classification, err := series.ClassifySeries("CentOS7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(classification.Series, gc.Equals, series.Name("centos7"))
+}
+
+func (s *interceptorSuite) TestVeto(c *gc.C) {
+	series.DefaultRegistry().AddInterceptor(series.InterceptorFunc(func(r series.Resolution) (string, error) {
+		if r.Series == "trusty" {
+			return "", errors.NotSupportedf("series %q at this site", r.Series)
+		}
+		return r.Series, nil
+	}))
+
+	_, err := series.VersionSeries("14.04")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `VersionSeries "14.04": series "trusty" at this site not supported`)
+	_, err = series.CanonicalSeries("trusty")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = series.CanonicalSeries("xenial")
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *interceptorSuite) TestEmptyRewrite(c *gc.C) {
+	series.DefaultRegistry().AddInterceptor(series.InterceptorFunc(func(series.Resolution) (string, error) {
+		return "", nil
+	}))
+	_, err := series.WindowsVersionSeries("Windows Server 2019")
+	c.Check(err, gc.ErrorMatches, `WindowsVersionSeries "Windows Server 2019": interceptor gave no series`)
+}
+
+func (s *interceptorSuite) TestRemove(c *gc.C) {
+	remove := series.DefaultRegistry().AddInterceptor(rhel)
+	remove()
+	name, err := series.CentOSVersionSeries("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "centos7")
+}
+
+func (s *interceptorSuite) TestInterceptorMayCallBack(c *gc.C) {
+	series.DefaultRegistry().AddInterceptor(series.InterceptorFunc(func(r series.Resolution) (string, error) {
+		if _, err := series.SeriesVersion(r.Series); err != nil {
+			return "", errors.Trace(err)
+		}
+		return r.Series, nil
+	}))
+	name, err := series.VersionSeries("18.04")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "bionic")
+}
+
+func (s *interceptorSuite) TestSupportedLists(c *gc.C) {
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	})
+	before := set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+	c.Assert(before.Contains("focal"), jc.IsTrue)
+	c.Assert(before.Contains("jammy"), jc.IsTrue)
+	c.Assert(before.Contains("centos7"), jc.IsTrue)
+
+	var lookups []string
+	series.DefaultRegistry().AddInterceptor(series.InterceptorFunc(func(r series.Resolution) (string, error) {
+		lookups = append(lookups, r.Lookup)
+		switch r.Series {
+		case "focal":
+			return "", errors.NotSupportedf("focal")
+		case "centos7":
+			return "jammy", nil
+		}
+		return r.Series, nil
+	}))
+
+	workload := series.SupportedJujuWorkloadSeries()
+	c.Check(workload, gc.HasLen, len(before)-2)
+	c.Check(set.NewStrings(workload...).Contains("focal"), jc.IsFalse)
+	c.Check(set.NewStrings(workload...).Contains("centos7"), jc.IsFalse)
+	c.Check(set.NewStrings(workload...).Contains("jammy"), jc.IsTrue)
+	c.Check(set.NewStrings(lookups...).Values(), jc.DeepEquals, []string{series.LookupSupportedJujuWorkloadSeries})
+
+	c.Check(set.NewStrings(series.SupportedJujuControllerSeries()...).Contains("focal"), jc.IsFalse)
+	c.Check(set.NewStrings(series.SupportedJujuSeriesAt(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))...).Contains("focal"), jc.IsFalse)
+
+	var names []string
+	for _, info := range series.SupportedJujuSeriesInfo() {
+		names = append(names, string(info.Name))
+	}
+	c.Check(names, jc.DeepEquals, workload)
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const kubernetesSeriesName = "kubernetes"
+
+// KubernetesVersion is the major and minor version of a Kubernetes cluster.
+// The zero KubernetesVersion means the version isn't known.
+type KubernetesVersion struct {
+	Major int
+	Minor int
+}
+
+var (
+	// MinKubernetesVersion and MaxKubernetesVersion bound the Kubernetes
+	// versions accepted by CheckKubernetesVersion.
+	MinKubernetesVersion = KubernetesVersion{Major: 1, Minor: 19}
+	MaxKubernetesVersion = KubernetesVersion{Major: 1, Minor: 28}
+)
+
+// ParseKubernetesSeries parses a kubernetes series, which may carry the
+// version of the cluster as "kubernetes/1.28" or, in the style of a base,
+// "kubernetes@1.28". The version of a plain "kubernetes" series is zero.
+func ParseKubernetesSeries(series string) (KubernetesVersion, error) {
+	name := FormatSeries(series)
+	if name == kubernetesSeriesName {
+		return KubernetesVersion{}, nil
+	}
+	for _, sep := range []string{"/", "@"} {
+		if !strings.HasPrefix(name, kubernetesSeriesName+sep) {
+			continue
+		}
+		version, err := parseKubernetesVersion(strings.TrimPrefix(name, kubernetesSeriesName+sep))
+		if err != nil {
+			return KubernetesVersion{}, errors.NotValidf("kubernetes series %q", series)
+		}
+		return version, nil
+	}
+	return KubernetesVersion{}, errors.NotValidf("kubernetes series %q", series)
+}
+
+// parseKubernetesVersion parses a version like "1.28". A patch version, as
+// in "1.28.2", is ignored.
+func parseKubernetesVersion(s string) (KubernetesVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return KubernetesVersion{}, errors.NotValidf("kubernetes version %q", s)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return KubernetesVersion{}, errors.NotValidf("kubernetes version %q", s)
+		}
+		numbers[i] = n
+	}
+	version := KubernetesVersion{Major: numbers[0], Minor: numbers[1]}
+	if version.IsZero() {
+		return KubernetesVersion{}, errors.NotValidf("kubernetes version %q", s)
+	}
+	return version, nil
+}
+
+// IsZero returns true if the version isn't known.
+func (v KubernetesVersion) IsZero() bool {
+	return v == KubernetesVersion{}
+}
+
+// String returns the version in the form "1.28", or an empty string for
+// the zero version.
+func (v KubernetesVersion) String() string {
+	if v.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Compare returns -1, 0 or 1 as v is older than, the same as, or newer
+// than other.
+func (v KubernetesVersion) Compare(other KubernetesVersion) int {
+	switch {
+	case v.Major < other.Major:
+		return -1
+	case v.Major > other.Major:
+		return 1
+	case v.Minor < other.Minor:
+		return -1
+	case v.Minor > other.Minor:
+		return 1
+	}
+	return 0
+}
+
+// Series returns the kubernetes series for the version, which is
+// "kubernetes/1.28", or "kubernetes" for the zero version.
+func (v KubernetesVersion) Series() string {
+	if v.IsZero() {
+		return kubernetesSeriesName
+	}
+	return kubernetesSeriesName + "/" + v.String()
+}
+
+// CheckKubernetesVersion returns a NotSupported error if the version is
+// outside MinKubernetesVersion to MaxKubernetesVersion. The zero version
+// can't be checked, so it is accepted.
+func CheckKubernetesVersion(v KubernetesVersion) error {
+	if v.IsZero() {
+		return nil
+	}
+	if v.Compare(MinKubernetesVersion) < 0 || v.Compare(MaxKubernetesVersion) > 0 {
+		return errors.NotSupportedf("kubernetes version %s (supported versions are %s to %s)",
+			v, MinKubernetesVersion, MaxKubernetesVersion)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type kubernetesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&kubernetesSuite{})
+
+func (s *kubernetesSuite) TestParseKubernetesSeries(c *gc.C) {
+	for i, test := range []struct {
+		series  string
+		version series.KubernetesVersion
+	}{
+		{"kubernetes", series.KubernetesVersion{}},
+		{" Kubernetes ", series.KubernetesVersion{}},
+		{"kubernetes/1.28", series.KubernetesVersion{Major: 1, Minor: 28}},
+		{"kubernetes@1.19", series.KubernetesVersion{Major: 1, Minor: 19}},
+		{"kubernetes/v1.27.3", series.KubernetesVersion{Major: 1, Minor: 27}},
+	} {
+		c.Logf("test %d: %s", i, test.series)
+		version, err := series.ParseKubernetesSeries(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(version, gc.Equals, test.version)
+	}
+}
+
+func (s *kubernetesSuite) TestParseKubernetesSeriesInvalid(c *gc.C) {
+	for _, input := range []string{"", "focal", "kubernetes/", "kubernetes/1", "kubernetes/one.two", "kubernetes/0.0", "kubernetes-1.28", "kubernetes/1.2.3.4"} {
+		_, err := series.ParseKubernetesSeries(input)
+		c.Check(err, jc.Satisfies, errors.IsNotValid, gc.Commentf("input %q", input))
+	}
+}
+
+func (s *kubernetesSuite) TestSeries(c *gc.C) {
+	c.Assert(series.KubernetesVersion{}.Series(), gc.Equals, "kubernetes")
+	c.Assert(series.KubernetesVersion{Major: 1, Minor: 28}.Series(), gc.Equals, "kubernetes/1.28")
+}
+
+func (s *kubernetesSuite) TestCompare(c *gc.C) {
+	v1_19 := series.KubernetesVersion{Major: 1, Minor: 19}
+	v1_28 := series.KubernetesVersion{Major: 1, Minor: 28}
+	v2_0 := series.KubernetesVersion{Major: 2}
+	c.Check(v1_19.Compare(v1_28), gc.Equals, -1)
+	c.Check(v1_28.Compare(v1_19), gc.Equals, 1)
+	c.Check(v1_28.Compare(v2_0), gc.Equals, -1)
+	c.Check(v1_28.Compare(v1_28), gc.Equals, 0)
+}
+
+func (s *kubernetesSuite) TestCheckKubernetesVersion(c *gc.C) {
+	s.PatchValue(&series.MinKubernetesVersion, series.KubernetesVersion{Major: 1, Minor: 20})
+	s.PatchValue(&series.MaxKubernetesVersion, series.KubernetesVersion{Major: 1, Minor: 25})
+
+	c.Check(series.CheckKubernetesVersion(series.KubernetesVersion{}), jc.ErrorIsNil)
+	c.Check(series.CheckKubernetesVersion(series.KubernetesVersion{Major: 1, Minor: 20}), jc.ErrorIsNil)
+	c.Check(series.CheckKubernetesVersion(series.KubernetesVersion{Major: 1, Minor: 25}), jc.ErrorIsNil)
+	err := series.CheckKubernetesVersion(series.KubernetesVersion{Major: 1, Minor: 26})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `kubernetes version 1.26 \(supported versions are 1.20 to 1.25\) not supported`)
+	err = series.CheckKubernetesVersion(series.KubernetesVersion{Major: 1, Minor: 19})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
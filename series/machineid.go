@@ -0,0 +1,43 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// MachineID returns the durable identity of the machine the current
+// process is running on, which unlike the BootID of HostInfo survives
+// reboots: the systemd machine ID from /etc/machine-id on linux, the
+// MachineGuid in the Windows registry, or the IOPlatformUUID on macOS.
+// The ID is returned as the host records it, so its format depends on
+// the platform. An error satisfying errors.IsNotFound is returned if the
+// host hasn't been given an ID, and one satisfying errors.IsNotSupported
+// on platforms without one.
+//
+// Machine IDs should be treated as confidential, as with other host
+// identifiers, and not exposed to untrusted parties.
+func MachineID() (string, error) {
+	id, err := readMachineID()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", errors.NotFoundf("machine ID")
+	}
+	return id, nil
+}
+
+// detectMachineID returns the machine ID, or "" if it can't be read.
+func detectMachineID() string {
+	id, err := MachineID()
+	if err != nil {
+		logger.Tracef("cannot determine machine ID: %v", err)
+		return ""
+	}
+	return id
+}
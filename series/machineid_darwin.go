@@ -0,0 +1,34 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"os/exec"
+	"regexp"
+
+	"github.com/juju/errors"
+)
+
+// ioregPlatformExpert returns the output of ioreg for the platform expert
+// device, which holds the hardware UUID of the host.
+var ioregPlatformExpert = func() ([]byte, error) {
+	return exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+}
+
+// platformUUIDPattern matches the IOPlatformUUID property of ioreg, shown
+// as `"IOPlatformUUID" = "564D1A2B-..."`.
+var platformUUIDPattern = regexp.MustCompile(`"IOPlatformUUID" = "([^"]+)"`)
+
+// readMachineID reads the IOPlatformUUID of the host from ioreg.
+func readMachineID() (string, error) {
+	output, err := ioregPlatformExpert()
+	if err != nil {
+		return "", errors.Annotate(err, "running ioreg")
+	}
+	match := platformUUIDPattern.FindSubmatch(output)
+	if match == nil {
+		return "", errors.NotFoundf("IOPlatformUUID in ioreg output")
+	}
+	return string(match[1]), nil
+}
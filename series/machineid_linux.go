@@ -0,0 +1,47 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// machineIDFiles are where the machine ID is looked for, in order. The
+// D-Bus file is kept by older hosts, and by those without systemd.
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// uninitializedMachineID is written to /etc/machine-id by systemd while
+// the first boot of a host is in progress, before the ID is committed.
+const uninitializedMachineID = "uninitialized"
+
+// machineIDPattern matches machine IDs, which are 128 bit IDs written as
+// 32 lower case hexadecimal digits, see machine-id(5).
+var machineIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// readMachineID reads the machine ID from the first machine ID file that
+// holds one.
+func readMachineID() (string, error) {
+	for _, path := range machineIDFiles {
+		contents, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+		id := strings.TrimSpace(string(contents))
+		if id == "" || id == uninitializedMachineID {
+			continue
+		}
+		if !machineIDPattern.MatchString(id) {
+			return "", errors.NotValidf("machine ID %q in %s", id, path)
+		}
+		return id, nil
+	}
+	return "", errors.NotFoundf("machine ID in %s", strings.Join(machineIDFiles, " or "))
+}
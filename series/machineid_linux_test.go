@@ -0,0 +1,74 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type machineIDSuite struct {
+	testing.CleanupSuite
+	dir string
+}
+
+var _ = gc.Suite(&machineIDSuite{})
+
+func (s *machineIDSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.PatchValue(series.MachineIDFiles, []string{
+		filepath.Join(s.dir, "machine-id"),
+		filepath.Join(s.dir, "dbus-machine-id"),
+	})
+}
+
+func (s *machineIDSuite) writeFile(c *gc.C, name, content string) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, name), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *machineIDSuite) TestMachineID(c *gc.C) {
+	s.writeFile(c, "machine-id", "0123456789abcdef0123456789abcdef\n")
+	s.writeFile(c, "dbus-machine-id", "fedcba9876543210fedcba9876543210\n")
+	id, err := series.MachineID()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "0123456789abcdef0123456789abcdef")
+}
+
+func (s *machineIDSuite) TestMachineIDFallsBackToDBus(c *gc.C) {
+	s.writeFile(c, "dbus-machine-id", "fedcba9876543210fedcba9876543210\n")
+	id, err := series.MachineID()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "fedcba9876543210fedcba9876543210")
+}
+
+func (s *machineIDSuite) TestMachineIDUninitialized(c *gc.C) {
+	// Both an empty file and systemd's first boot marker mean that the
+	// ID hasn't been committed yet.
+	for _, content := range []string{"", "uninitialized\n"} {
+		s.writeFile(c, "machine-id", content)
+		_, err := series.MachineID()
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+	}
+}
+
+func (s *machineIDSuite) TestMachineIDNotFound(c *gc.C) {
+	_, err := series.MachineID()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *machineIDSuite) TestMachineIDNotValid(c *gc.C) {
+	s.writeFile(c, "machine-id", "not-a-machine-id\n")
+	_, err := series.MachineID()
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `machine ID "not-a-machine-id" in .*machine-id not valid`)
+}
@@ -0,0 +1,18 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !linux,!windows,!darwin
+
+package series
+
+import (
+	"runtime"
+
+	"github.com/juju/errors"
+)
+
+// readMachineID returns the machine ID of the host, which is only known
+// on linux, windows and darwin.
+func readMachineID() (string, error) {
+	return "", errors.NotSupportedf("machine ID on %s", runtime.GOOS)
+}
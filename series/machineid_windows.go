@@ -0,0 +1,33 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+	"golang.org/x/sys/windows/registry"
+)
+
+// cryptographyKey holds the MachineGuid value, generated when Windows is
+// installed.
+var cryptographyKey = "SOFTWARE\\Microsoft\\Cryptography"
+
+// readMachineID reads the MachineGuid from the registry. The 64 bit view
+// is read so that 32 bit processes don't get the redirected key, which
+// has no MachineGuid.
+func readMachineID() (string, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, cryptographyKey, registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err == registry.ErrNotExist {
+		return "", errors.NotFoundf("machine ID")
+	} else if err != nil {
+		return "", errors.Annotatef(err, "opening key %s", cryptographyKey)
+	}
+	defer k.Close()
+	id, _, err := k.GetStringValue("MachineGuid")
+	if err == registry.ErrNotExist {
+		return "", errors.NotFoundf("machine ID")
+	} else if err != nil {
+		return "", errors.Annotatef(err, "reading MachineGuid of %s", cryptographyKey)
+	}
+	return id, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Metrics describes how the series data has been used, for monitoring.
+type Metrics struct {
+	// UnknownLookups counts the lookups of series that aren't known.
+	UnknownLookups uint64
+	// CacheHits and CacheMisses count the uses of the cached supported
+	// series lists and snapshot, and the times they had to be rebuilt.
+	CacheHits   uint64
+	CacheMisses uint64
+	// LastRefresh is when the series data was last updated from
+	// distro-info, definitions or a remote source. It is zero if only the
+	// embedded data has been used.
+	LastRefresh time.Time
+	// DataVersion describes the series data in use.
+	DataVersion DataVersionInfo
+}
+
+// counters holds the metrics that are updated without holding
+// seriesVersionsMutex.
+var counters struct {
+	unknownLookups uint64
+	cacheHits      uint64
+	cacheMisses    uint64
+}
+
+// lastRefresh is when the series data was last updated. It is guarded by
+// seriesVersionsMutex.
+var lastRefresh time.Time
+
+// CurrentMetrics returns the current series data metrics.
+func CurrentMetrics() Metrics {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	return Metrics{
+		UnknownLookups: atomic.LoadUint64(&counters.unknownLookups),
+		CacheHits:      atomic.LoadUint64(&counters.cacheHits),
+		CacheMisses:    atomic.LoadUint64(&counters.cacheMisses),
+		LastRefresh:    lastRefresh,
+		DataVersion:    dataVersion,
+	}
+}
+
+// countCache records a use of a cache.
+func countCache(hit bool) {
+	if hit {
+		atomic.AddUint64(&counters.cacheHits, 1)
+	} else {
+		atomic.AddUint64(&counters.cacheMisses, 1)
+	}
+}
+
+// setDataVersion records that the series data was refreshed from the
+// described data. The caller must hold seriesVersionsMutex.
+func setDataVersion(version DataVersionInfo) {
+	dataVersion = version
+	lastRefresh = defaultRegistry.today()
+}
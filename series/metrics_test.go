@@ -0,0 +1,59 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type metricsSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *metricsSuite) TestUnknownLookups(c *gc.C) {
+	before := series.CurrentMetrics().UnknownLookups
+	_, err := series.GetOSFromSeries("sulu")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+	c.Assert(series.CurrentMetrics().UnknownLookups, gc.Equals, before+1)
+}
+
+func (s *metricsSuite) TestCacheUse(c *gc.C) {
+	series.CurrentSnapshot()
+	before := series.CurrentMetrics()
+	series.CurrentSnapshot()
+	after := series.CurrentMetrics()
+	c.Assert(after.CacheHits, gc.Equals, before.CacheHits+1)
+	c.Assert(after.CacheMisses, gc.Equals, before.CacheMisses)
+
+	err := series.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"})
+	c.Assert(err, jc.ErrorIsNil)
+	series.CurrentSnapshot()
+	c.Assert(series.CurrentMetrics().CacheMisses > after.CacheMisses, jc.IsTrue)
+}
+
+func (s *metricsSuite) TestLastRefresh(c *gc.C) {
+	now := time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)
+	series.DefaultRegistry().SetClock(func() time.Time { return now })
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "version": "2031.01", "series": []}`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	metrics := series.CurrentMetrics()
+	c.Assert(metrics.LastRefresh, gc.Equals, now)
+	c.Assert(metrics.DataVersion.Version, gc.Equals, "2031.01")
+}
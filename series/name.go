@@ -0,0 +1,47 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// Name is the name of a series, such as "focal" or "centos8". Using Name
+// rather than string in signatures distinguishes series names from other
+// strings, such as versions.
+type Name string
+
+// ParseName returns the series name held in s, ignoring case and
+// surrounding whitespace, after checking it is valid.
+func ParseName(s string) (Name, error) {
+	name := Name(FormatSeries(s))
+	if err := name.Validate(); err != nil {
+		return "", errors.Trace(err)
+	}
+	return name, nil
+}
+
+// Validate returns an error if the name isn't a valid series name. It
+// doesn't check whether the series is known.
+func (n Name) Validate() error {
+	if n == "" {
+		return errors.NotValidf("empty series name")
+	}
+	if !validSeriesName.MatchString(string(n)) {
+		return errors.NotValidf("series name %q", string(n))
+	}
+	return nil
+}
+
+// String returns the name as a string.
+func (n Name) String() string {
+	return string(n)
+}
+
+// sortNames sorts names in increasing order.
+func sortNames(names []Name) {
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+}
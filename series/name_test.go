@@ -0,0 +1,35 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type nameSuite struct{}
+
+var _ = gc.Suite(&nameSuite{})
+
+func (s *nameSuite) TestValidate(c *gc.C) {
+	for _, name := range []series.Name{"focal", "centos8", "opensuse15.2", "win2016-nano"} {
+		c.Check(name.Validate(), jc.ErrorIsNil, gc.Commentf("name %q", name))
+	}
+	for _, name := range []series.Name{"", "Focal", "8centos", "focal fossa", " focal"} {
+		c.Check(name.Validate(), jc.Satisfies, errors.IsNotValid, gc.Commentf("name %q", name))
+	}
+}
+
+func (s *nameSuite) TestParseName(c *gc.C) {
+	name, err := series.ParseName(" Focal\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, series.Name("focal"))
+	c.Assert(name.String(), gc.Equals, "focal")
+
+	_, err = series.ParseName("focal fossa")
+	c.Assert(err, gc.ErrorMatches, `series name "focal fossa" not valid`)
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// The well known kubernetes node labels used to select nodes by operating
+// system.
+const (
+	KubernetesOSLabel           = "kubernetes.io/os"
+	KubernetesArchLabel         = "kubernetes.io/arch"
+	KubernetesWindowsBuildLabel = "node.kubernetes.io/windows-build"
+)
+
+// Toleration describes a kubernetes toleration, mirroring the fields of
+// the core/v1 Toleration type so that this package needn't depend on the
+// kubernetes API.
+type Toleration struct {
+	Key      string
+	Operator string
+	Value    string
+	Effect   string
+}
+
+// windowsTaint is tolerated by workloads targeting Windows nodes, which
+// are conventionally tainted so linux workloads aren't scheduled on them.
+var windowsTaint = Toleration{
+	Key:      "os",
+	Operator: "Equal",
+	Value:    "windows",
+	Effect:   "NoSchedule",
+}
+
+// windowsNodeBuilds holds the node.kubernetes.io/windows-build label value
+// of the Windows Server series that can run kubernetes nodes.
+var windowsNodeBuilds = map[string]string{
+	"win2016": "10.0.14393",
+	"win2019": "10.0.17763",
+	"win2022": "10.0.20348",
+	"win23h2": "10.0.25398",
+}
+
+// kubernetesArches maps Juju architecture names to the names used by
+// kubernetes, where they differ.
+var kubernetesArches = map[string]string{
+	"ppc64el": "ppc64le",
+	"i386":    "386",
+	"armhf":   "arm",
+}
+
+// KubernetesNodeSelector returns the node selector labels and tolerations
+// needed to schedule a workload onto kubernetes nodes running the series.
+// The arch, which may be empty to allow any architecture, is a Juju
+// architecture name such as "amd64" or "ppc64el". An error satisfying
+// errors.IsNotSupported is returned for series that can't run kubernetes
+// nodes.
+func KubernetesNodeSelector(series, arch string) (map[string]string, []Toleration, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	osType, err := GetOSFromSeries(string(name))
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	selector := make(map[string]string)
+	var tolerations []Toleration
+	switch {
+	case osType.IsLinux():
+		selector[KubernetesOSLabel] = "linux"
+	case osType == os.Windows:
+		build, ok := windowsNodeBuilds[string(name)]
+		if !ok {
+			return nil, nil, errors.NotSupportedf("kubernetes nodes running %q", series)
+		}
+		selector[KubernetesOSLabel] = "windows"
+		selector[KubernetesWindowsBuildLabel] = build
+		tolerations = append(tolerations, windowsTaint)
+	default:
+		return nil, nil, errors.NotSupportedf("kubernetes nodes running %q", series)
+	}
+	if arch != "" {
+		if kubeArch, ok := kubernetesArches[arch]; ok {
+			arch = kubeArch
+		}
+		selector[KubernetesArchLabel] = arch
+	}
+	return selector, tolerations, nil
+}
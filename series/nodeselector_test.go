@@ -0,0 +1,59 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type nodeSelectorSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&nodeSelectorSuite{})
+
+func (s *nodeSelectorSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *nodeSelectorSuite) TestLinux(c *gc.C) {
+	selector, tolerations, err := series.KubernetesNodeSelector("focal", "ppc64el")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(selector, jc.DeepEquals, map[string]string{
+		"kubernetes.io/os":   "linux",
+		"kubernetes.io/arch": "ppc64le",
+	})
+	c.Assert(tolerations, gc.HasLen, 0)
+}
+
+func (s *nodeSelectorSuite) TestWindows(c *gc.C) {
+	selector, tolerations, err := series.KubernetesNodeSelector("win2019", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(selector, jc.DeepEquals, map[string]string{
+		"kubernetes.io/os":                 "windows",
+		"node.kubernetes.io/windows-build": "10.0.17763",
+	})
+	c.Assert(tolerations, jc.DeepEquals, []series.Toleration{{
+		Key:      "os",
+		Operator: "Equal",
+		Value:    "windows",
+		Effect:   "NoSchedule",
+	}})
+}
+
+func (s *nodeSelectorSuite) TestNotSupported(c *gc.C) {
+	for _, name := range []string{"win2012", "mountainlion", "kubernetes"} {
+		_, _, err := series.KubernetesNodeSelector(name, "amd64")
+		c.Check(err, jc.Satisfies, errors.IsNotSupported, gc.Commentf("series %q", name))
+	}
+	_, _, err := series.KubernetesNodeSelector("sulu", "amd64")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
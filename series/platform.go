@@ -0,0 +1,99 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// architectures holds the Juju names of the architectures that platforms
+// may target.
+var architectures = map[string]bool{
+	"amd64":   true,
+	"arm64":   true,
+	"armhf":   true,
+	"i386":    true,
+	"ppc64el": true,
+	"riscv64": true,
+	"s390x":   true,
+}
+
+// Architectures returns the names of the architectures that platforms may
+// target, sorted.
+func Architectures() []string {
+	result := make([]string, 0, len(architectures))
+	for arch := range architectures {
+		result = append(result, arch)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Platform is an operating system, channel and architecture triplet, as
+// found in the "platforms" of charm metadata, for example
+// "ubuntu/22.04/amd64".
+type Platform struct {
+	OS      os.OSType
+	Channel string
+	Arch    string
+}
+
+// ParsePlatform parses a platform string of the form
+// "<os>/<channel>/<arch>", ignoring case and surrounding whitespace, and
+// checks that it names a known series and architecture.
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(s)), "/")
+	if len(parts) != 3 {
+		return Platform{}, errors.NotValidf("platform %q, expected <os>/<channel>/<arch>", s)
+	}
+	osType, err := os.ParseOSType(parts[0])
+	if err != nil {
+		return Platform{}, errors.NotValidf("platform %q OS", s)
+	}
+	p := Platform{OS: osType, Channel: parts[1], Arch: parts[2]}
+	if err := p.Validate(); err != nil {
+		return Platform{}, errors.Annotatef(err, "platform %q", s)
+	}
+	return p, nil
+}
+
+// Validate returns an error if the platform's channel doesn't identify a
+// series of its OS, or its architecture isn't known.
+func (p Platform) Validate() error {
+	if p.Channel == "" {
+		return errors.NotValidf("empty channel")
+	}
+	if !architectures[p.Arch] {
+		return errors.NotValidf("architecture %q", p.Arch)
+	}
+	_, err := p.Series()
+	return errors.Trace(err)
+}
+
+// Series returns the series identified by the platform's OS and channel.
+// The channel is usually the version of the series, as in "ubuntu/22.04",
+// but series versioned by name are also matched by the version suffix, as
+// in "centos/7", or by the series itself, as in "windows/win2019".
+func (p Platform) Series() (Name, error) {
+	candidates := []string{os.FormatOSType(p.OS) + p.Channel, p.Channel}
+	if series, err := resolveVersionSeries(p.Channel); err == nil {
+		candidates = append([]string{series}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if osType, err := GetOSFromSeries(candidate); err == nil && osType == p.OS {
+			return Name(candidate), nil
+		}
+	}
+	return "", errors.NotFoundf("%s series with channel %q", p.OS, p.Channel)
+}
+
+// String returns the platform in the form "<os>/<channel>/<arch>".
+func (p Platform) String() string {
+	return fmt.Sprintf("%s/%s/%s", os.FormatOSType(p.OS), p.Channel, p.Arch)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type platformSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&platformSuite{})
+
+func (s *platformSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *platformSuite) TestParsePlatform(c *gc.C) {
+	for i, test := range []struct {
+		input    string
+		platform series.Platform
+		series   series.Name
+		str      string
+	}{
+		{"ubuntu/22.04/amd64", series.Platform{OS: os.Ubuntu, Channel: "22.04", Arch: "amd64"}, "jammy", "ubuntu/22.04/amd64"},
+		{" Ubuntu/20.04/PPC64EL ", series.Platform{OS: os.Ubuntu, Channel: "20.04", Arch: "ppc64el"}, "focal", "ubuntu/20.04/ppc64el"},
+		{"centos/7/arm64", series.Platform{OS: os.CentOS, Channel: "7", Arch: "arm64"}, "centos7", "centos/7/arm64"},
+		{"windows/win2019/amd64", series.Platform{OS: os.Windows, Channel: "win2019", Arch: "amd64"}, "win2019", "windows/win2019/amd64"},
+	} {
+		c.Logf("test %d: %q", i, test.input)
+		p, err := series.ParsePlatform(test.input)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(p, gc.Equals, test.platform)
+		c.Check(p.String(), gc.Equals, test.str)
+		name, err := p.Series()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(name, gc.Equals, test.series)
+	}
+}
+
+func (s *platformSuite) TestParsePlatformInvalid(c *gc.C) {
+	for i, test := range []struct {
+		input string
+		err   string
+	}{
+		{"ubuntu/22.04", `platform "ubuntu/22.04", expected <os>/<channel>/<arch> not valid`},
+		{"plan9/4/amd64", `platform "plan9/4/amd64" OS not valid`},
+		{"ubuntu//amd64", `platform "ubuntu//amd64": empty channel not valid`},
+		{"ubuntu/22.04/vax", `platform "ubuntu/22.04/vax": architecture "vax" not valid`},
+		{"ubuntu/7/amd64", `platform "ubuntu/7/amd64": Ubuntu series with channel "7" not found`},
+		{"centos/20.04/amd64", `platform "centos/20.04/amd64": CentOS series with channel "20.04" not found`},
+	} {
+		c.Logf("test %d: %q", i, test.input)
+		_, err := series.ParsePlatform(test.input)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	_, err := series.ParsePlatform("ubuntu/22.04/vax")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"os"
+)
+
+// PlatformCapabilities describes which of the facilities this package
+// relies on are available on the current platform and build, so that
+// embedders can adapt to a limited platform rather than discovering its
+// limitations through errors at runtime.
+type PlatformCapabilities struct {
+	// SeriesDetection is true if the series of the host can be detected
+	// by HostSeries. It can't on platforms no series is defined for.
+	SeriesDetection bool
+	// RegistryAccess is true if the Windows registry is read to identify
+	// the host.
+	RegistryAccess bool
+	// ExecProbes is true if identifying the host runs commands, such as
+	// sw_vers or uname, which sandboxes may forbid.
+	ExecProbes bool
+	// DistroInfo is true if the ubuntu distro-info data is read from
+	// UbuntuDistroInfo, and the file exists, so that the ubuntu series
+	// stay up to date without being fetched.
+	DistroInfo bool
+}
+
+// CurrentPlatformCapabilities returns the capabilities of the current
+// platform and build. It is unrelated to Capabilities, which describes
+// the workloads a series can run.
+func CurrentPlatformCapabilities() PlatformCapabilities {
+	caps := platformCapabilities
+	if caps.DistroInfo {
+		_, err := os.Stat(UbuntuDistroInfo)
+		caps.DistroInfo = err == nil
+	}
+	return caps
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type platformCapabilitiesSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&platformCapabilitiesSuite{})
+
+func (s *platformCapabilitiesSuite) TestCurrentPlatformCapabilities(c *gc.C) {
+	caps := series.CurrentPlatformCapabilities()
+	switch runtime.GOOS {
+	case "linux", "darwin", "windows", "openbsd", "netbsd", "aix":
+		c.Check(caps.SeriesDetection, jc.IsTrue)
+	default:
+		c.Check(caps, jc.DeepEquals, series.PlatformCapabilities{})
+	}
+	c.Check(caps.RegistryAccess, gc.Equals, runtime.GOOS == "windows")
+}
+
+func (s *platformCapabilitiesSuite) TestDistroInfo(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "ubuntu.csv")
+	s.PatchValue(&series.UbuntuDistroInfo, path)
+	c.Check(series.CurrentPlatformCapabilities().DistroInfo, jc.IsFalse)
+
+	err := ioutil.WriteFile(path, []byte("version,codename,series,created,release,eol\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(series.CurrentPlatformCapabilities().DistroInfo, gc.Equals, runtime.GOOS == "linux")
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// ubuntuPointReleases maps LTS series to their latest point release. It
+// only seeds the point releases made before this package was released:
+// later ones are only known once loaded with LoadMetaRelease.
+var ubuntuPointReleases = map[string]string{
+	"precise": "12.04.5",
+	"trusty":  "14.04.6",
+	"xenial":  "16.04.7",
+	"bionic":  "18.04.6",
+	"focal":   "20.04.6",
+	"jammy":   "22.04.5",
+	"noble":   "24.04.4",
+}
+
+// LatestPointRelease returns the latest point release of the series, for
+// example "20.04.6" for focal. The embedded point releases may be out of
+// date, so callers that need the latest should load the Ubuntu
+// meta-release data with LoadMetaRelease first.
+//
+// An error satisfying errors.IsNotFound is returned for known series
+// whose point releases aren't known: those that aren't LTS releases, and
+// LTS releases newer than the embedded and loaded data. An error
+// satisfying IsUnknownSeriesVersionError is returned for series that
+// aren't known ubuntu series.
+func LatestPointRelease(series string) (string, error) {
+	name := FormatSeries(series)
+	if _, err := UbuntuSeriesVersion(name); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	if release, ok := ubuntuPointReleases[name]; ok {
+		return release, nil
+	}
+	return "", errors.NotFoundf("point release of series %q", series)
+}
+
+// PointReleasesBehind returns how many point releases of the series have
+// been made since release, such as "20.04.1", which is the point release
+// an image was built from.
+func PointReleasesBehind(series, release string) (int, error) {
+	latest, err := LatestPointRelease(series)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	latestVersion, latestPoint, _ := splitPointRelease(latest)
+	version, point, ok := splitPointRelease(release)
+	if !ok || version != latestVersion {
+		return 0, errors.NotValidf("point release %q of series %q", release, series)
+	}
+	if point >= latestPoint {
+		return 0, nil
+	}
+	return latestPoint - point, nil
+}
+
+// splitPointRelease splits a point release like "20.04.3" into the version
+// and the point. The initial release, "20.04", is point zero.
+func splitPointRelease(release string) (string, int, bool) {
+	release = strings.TrimSuffix(strings.TrimSpace(release), " LTS")
+	parts := strings.Split(release, ".")
+	switch len(parts) {
+	case 2:
+		return release, 0, true
+	case 3:
+		point, err := strconv.Atoi(parts[2])
+		if err != nil || point < 0 {
+			return "", 0, false
+		}
+		return parts[0] + "." + parts[1], point, true
+	}
+	return "", 0, false
+}
+
+// LoadMetaRelease updates the latest point releases from the Ubuntu
+// meta-release data in r, as published at
+// https://changelogs.ubuntu.com/meta-release-lts. Series that aren't
+// known are ignored.
+func LoadMetaRelease(r io.Reader) error {
+	releases := make(map[string]string)
+	var dist, version string
+	record := func() {
+		if dist != "" && version != "" {
+			if _, _, ok := splitPointRelease(version); ok {
+				releases[dist] = strings.TrimSuffix(version, " LTS")
+			}
+		}
+		dist, version = "", ""
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			record()
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "Dist":
+			dist = FormatSeries(parts[1])
+		case "Version":
+			version = strings.TrimSpace(parts[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Annotate(err, "reading meta-release data")
+	}
+	record()
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	for dist, release := range releases {
+		if _, ok := ubuntuSeries[dist]; ok {
+			ubuntuPointReleases[dist] = release
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type pointReleaseSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&pointReleaseSuite{})
+
+func (s *pointReleaseSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+const metaReleaseData = `Dist: bionic
+Name: Bionic Beaver
+Version: 18.04.6 LTS
+Date: Thu, 26 April 2018 22:04:00 UTC
+Supported: 1
+Description: This is the 18.04.6 LTS release
+
+Dist: focal
+Name: Focal Fossa
+Version: 20.04.5 LTS
+Date: Thu, 23 April 2020 22:04:00 UTC
+Supported: 1
+Description: This is the 20.04.5 LTS release
+
+Dist: sisko
+Name: Sisko
+Version: 99.04.1 LTS
+Supported: 1
+`
+
+func (s *pointReleaseSuite) TestLatestPointRelease(c *gc.C) {
+	release, err := series.LatestPointRelease("xenial")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(release, gc.Equals, "16.04.7")
+	release, err = series.LatestPointRelease("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(release, gc.Equals, "20.04.6")
+
+	_, err = series.LatestPointRelease("groovy")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = series.LatestPointRelease("win2019")
+	c.Assert(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+}
+
+func (s *pointReleaseSuite) TestLatestPointReleaseWithoutDistroInfo(c *gc.C) {
+	s.PatchValue(&series.UbuntuDistroInfo, filepath.Join(c.MkDir(), "missing.csv"))
+	series.SetSeriesVersions(nil)
+	for _, test := range []struct {
+		series  string
+		release string
+	}{
+		{"jammy", "22.04.5"},
+		{"noble", "24.04.4"},
+	} {
+		release, err := series.LatestPointRelease(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(release, gc.Equals, test.release)
+	}
+}
+
+func (s *pointReleaseSuite) TestLoadMetaRelease(c *gc.C) {
+	err := series.LoadMetaRelease(strings.NewReader(metaReleaseData))
+	c.Assert(err, jc.ErrorIsNil)
+
+	release, err := series.LatestPointRelease("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(release, gc.Equals, "20.04.5")
+	release, err = series.LatestPointRelease("bionic")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(release, gc.Equals, "18.04.6")
+	_, err = series.LatestPointRelease("sisko")
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *pointReleaseSuite) TestPointReleasesBehind(c *gc.C) {
+	err := series.LoadMetaRelease(strings.NewReader(metaReleaseData))
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, test := range []struct {
+		release string
+		behind  int
+	}{
+		{"20.04", 5},
+		{"20.04.1", 4},
+		{"20.04.5 LTS", 0},
+		{"20.04.6", 0},
+	} {
+		behind, err := series.PointReleasesBehind("focal", test.release)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(behind, gc.Equals, test.behind, gc.Commentf("release %q", test.release))
+	}
+
+	_, err = series.PointReleasesBehind("focal", "18.04.1")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	_, err = series.PointReleasesBehind("focal", "twenty")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Registry is a handle on the series data used by the package level
+// functions. There is a single Registry, returned by DefaultRegistry.
+type Registry struct {
+	// now returns the current time. It is guarded by seriesVersionsMutex.
+	now func() time.Time
+	// rejectRetired is true if lookups of retired series fail. It is
+	// guarded by seriesVersionsMutex.
+	rejectRetired bool
+	// futurePolicy determines how unreleased series are treated. It is
+	// guarded by seriesVersionsMutex.
+	futurePolicy FuturePolicy
+	// interceptors are applied to resolved series, in order. They are
+	// guarded by seriesVersionsMutex, and never modified in place.
+	interceptors      []registeredInterceptor
+	nextInterceptorID int
+	// changelogPath is the path of the changelog of updates, if any. It
+	// is guarded by seriesVersionsMutex.
+	changelogPath string
+	// requireDistroInfo is true if supported series can't be computed
+	// without distro-info data. It is guarded by seriesVersionsMutex.
+	requireDistroInfo bool
+	// compatibility holds the rules of which host series workloads can
+	// run on. It is guarded by seriesVersionsMutex, and never modified in
+	// place.
+	compatibility compatibilityRules
+}
+
+var defaultRegistry = &Registry{now: time.Now}
+
+// DefaultRegistry returns the Registry holding the package's series data.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// SetClock sets the function used to find the current date in every date
+// sensitive computation, such as whether a series from distro-info is
+// supported and which LTS series is the latest. This allows replaying the
+// answers that would have been given on another date. Passing nil restores
+// the system clock.
+func (r *Registry) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+
+	r.now = now
+	updateVersionSeries()
+	latestLtsSeries = ""
+}
+
+// Now returns the current time according to the registry's clock.
+func (r *Registry) Now() time.Time {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	return r.today()
+}
+
+// today returns the current time in UTC according to the registry's
+// clock. The caller must hold seriesVersionsMutex.
+func (r *Registry) today() time.Time {
+	return r.now().UTC()
+}
+
+// SetRejectRetiredSeries sets whether looking up a retired series, such as
+// win7, win8 or win2008r2, fails with an error satisfying
+// IsRetiredSeriesError, for organizations that enforce a baseline of
+// supported operating systems. Retired series are accepted by default.
+func (r *Registry) SetRejectRetiredSeries(reject bool) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	r.rejectRetired = reject
+}
+
+// SetRequireDistroInfo sets whether the supported series are only
+// computed from distro-info data, loaded from the local distro-info file,
+// a bundle or a remote source. Without it, the series compiled into this
+// package are used, which can leave out recent series. When it is set and
+// there is no distro-info data, CheckDistroInfo and the functions that
+// use it fail with ErrNoDistroInfo, so that callers can fail closed.
+func (r *Registry) SetRequireDistroInfo(require bool) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	r.requireDistroInfo = require
+}
+
+// checkDistroInfoLocked returns ErrNoDistroInfo if distro-info data is
+// required but there is none. The caller must hold seriesVersionsMutex.
+func (r *Registry) checkDistroInfoLocked() error {
+	if !r.requireDistroInfo {
+		return nil
+	}
+	for _, source := range seriesSources {
+		if source == SourceDistroInfo {
+			return nil
+		}
+	}
+	return errors.Trace(ErrNoDistroInfo)
+}
+
+// checkRetired returns a retiredSeriesError if the normalized series is
+// retired and retired series are rejected.
+func (r *Registry) checkRetired(series string) error {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	return r.checkRetiredLocked(series)
+}
+
+// checkRetiredLocked is checkRetired for callers that hold
+// seriesVersionsMutex.
+func (r *Registry) checkRetiredLocked(series string) error {
+	if r.rejectRetired && retiredSeries[series] {
+		return errors.Trace(retiredSeriesError(series))
+	}
+	return nil
+}
+
+// retiredSeries holds the series that are no longer supported by their
+// vendor, which lookups reject if the registry is set to.
+var retiredSeries = map[string]bool{
+	"win7":      true,
+	"win8":      true,
+	"win2008r2": true,
+}
+
+type retiredSeriesError string
+
+func (e retiredSeriesError) Error() string {
+	return `series "` + string(e) + `" is retired`
+}
+
+// IsRetiredSeriesError returns true if err is of type retiredSeriesError.
+func IsRetiredSeriesError(err error) bool {
+	_, ok := errors.Cause(err).(retiredSeriesError)
+	return ok
+}
+
+// supportedAt reports whether the series is supported at t. Series with
+// release and end of life dates, which come from distro-info, are
+// supported between the two, unless their support is overridden. Other
+// series use their Supported flag.
+func (v seriesVersion) supportedAt(t time.Time) bool {
+	if v.SupportOverridden || v.Released.IsZero() || v.EOL.IsZero() {
+		return v.Supported
+	}
+	return t.After(v.Released) && t.Before(v.EOL)
+}
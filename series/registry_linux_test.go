@@ -0,0 +1,159 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type registrySuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&registrySuite{})
+
+const registryDistroInfo = `version,codename,series,created,release,eol
+12.04 LTS,Precise Pangolin,precise,2011-10-13,2012-04-26,2017-04-28
+98.04 LTS,Picard,picard,2029-10-01,2030-04-01,2035-04-01
+98.10,Riker,riker,2030-04-01,2030-10-01,2031-07-01
+`
+
+func (s *registrySuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+
+	filename := filepath.Join(c.MkDir(), "ubuntu.csv")
+	err := ioutil.WriteFile(filename, []byte(registryDistroInfo), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(series.UbuntuDistroInfoPath, filename)
+	err = series.UpdateSeriesVersions()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *registrySuite) setDate(year int, month time.Month, day int) {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	series.DefaultRegistry().SetClock(func() time.Time { return date })
+}
+
+func (s *registrySuite) TestClockControlsSupport(c *gc.C) {
+	s.setDate(2031, 1, 1)
+	controller := set.NewStrings(series.SupportedJujuControllerSeries()...)
+	c.Check(controller.Contains("picard"), jc.IsTrue)
+	c.Check(controller.Contains("riker"), jc.IsTrue)
+	c.Check(set.NewStrings(series.SupportedLts()...).Contains("picard"), jc.IsTrue)
+	c.Check(series.LatestLts(), gc.Equals, "picard")
+
+	s.setDate(2032, 1, 1)
+	controller = set.NewStrings(series.SupportedJujuControllerSeries()...)
+	c.Check(controller.Contains("picard"), jc.IsTrue)
+	c.Check(controller.Contains("riker"), jc.IsFalse)
+
+	s.setDate(2036, 1, 1)
+	controller = set.NewStrings(series.SupportedJujuControllerSeries()...)
+	c.Check(controller.Contains("picard"), jc.IsFalse)
+	c.Check(set.NewStrings(series.SupportedLts()...).Contains("picard"), jc.IsFalse)
+	c.Check(series.LatestLts(), gc.Not(gc.Equals), "picard")
+}
+
+func (s *registrySuite) TestNow(c *gc.C) {
+	s.setDate(2031, 1, 1)
+	c.Assert(series.DefaultRegistry().Now(), gc.Equals, time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	series.DefaultRegistry().SetClock(nil)
+	c.Assert(series.DefaultRegistry().Now().Year() >= 2020, jc.IsTrue)
+}
+
+func (s *registrySuite) TestSupportedJujuSeriesAt(c *gc.C) {
+	s.setDate(2036, 1, 1)
+	at := time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	controller := set.NewStrings(series.SupportedJujuControllerSeriesAt(at)...)
+	c.Check(controller.Contains("picard"), jc.IsTrue)
+	c.Check(controller.Contains("riker"), jc.IsTrue)
+	workload := set.NewStrings(series.SupportedJujuSeriesAt(at)...)
+	c.Check(workload.Contains("picard"), jc.IsTrue)
+	c.Check(workload.Contains("riker"), jc.IsTrue)
+	c.Check(set.NewStrings(series.SupportedLtsAt(at)...).Contains("picard"), jc.IsTrue)
+
+	// The current answers are unaffected.
+	c.Check(set.NewStrings(series.SupportedJujuControllerSeries()...).Contains("picard"), jc.IsFalse)
+
+	before := time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.Check(set.NewStrings(series.SupportedJujuWorkloadSeriesAt(before)...).Contains("picard"), jc.IsFalse)
+}
+
+func (s *registrySuite) TestCentOSEndOfLife(c *gc.C) {
+	s.setDate(2021, 6, 1)
+	workload := set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+	c.Check(workload.Contains("centos8"), jc.IsTrue)
+	c.Check(workload.Contains("centos8-stream"), jc.IsTrue)
+
+	s.setDate(2022, 6, 1)
+	workload = set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+	c.Check(workload.Contains("centos8"), jc.IsFalse)
+	c.Check(workload.Contains("centos8-stream"), jc.IsTrue)
+	c.Check(workload.Contains("centos9"), jc.IsTrue)
+}
+
+func (s *registrySuite) TestDefaultSupportedLTS(c *gc.C) {
+	s.setDate(2031, 1, 1)
+	lts, err := series.DefaultSupportedLTS(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lts, gc.Equals, "picard")
+
+	lts, err = series.DefaultSupportedLTS([]string{"focal", "bionic", "riker"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lts, gc.Equals, "focal")
+
+	lts, err = series.DefaultSupportedLTS([]string{"18.04", "98.04"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lts, gc.Equals, "picard")
+
+	_, err = series.DefaultSupportedLTS([]string{"riker"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = series.DefaultSupportedLTS([]string{})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *registrySuite) TestSupportedJujuControllerSeriesAtLeast(c *gc.C) {
+	s.setDate(2031, 1, 1)
+	controller, err := series.SupportedJujuControllerSeriesAtLeast("98.10")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controller, jc.DeepEquals, []string{"riker"})
+
+	controller, err = series.SupportedJujuControllerSeriesAtLeast("98.04")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controller, jc.DeepEquals, []string{"picard", "riker"})
+
+	_, err = series.SupportedJujuControllerSeriesAtLeast("focal")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *registrySuite) TestDistroInfoSupported(c *gc.C) {
+	s.setDate(2032, 1, 1)
+	supported, err := series.DistroInfoSupported("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsTrue)
+
+	supported, err = series.DistroInfoSupported("riker")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsFalse)
+
+	_, err = series.DistroInfoSupported("win2019")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = series.DistroInfoSupported("sulu")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type retiredSeriesSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&retiredSeriesSuite{})
+
+func (s *retiredSeriesSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *retiredSeriesSuite) TestRetiredSeriesAcceptedByDefault(c *gc.C) {
+	osType, err := series.GetOSFromSeries("win7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.Windows)
+}
+
+func (s *retiredSeriesSuite) TestRejectRetiredSeries(c *gc.C) {
+	series.DefaultRegistry().SetRejectRetiredSeries(true)
+
+	for _, name := range []string{"win7", "Win8", "win2008r2"} {
+		_, err := series.GetOSFromSeries(name)
+		c.Check(err, jc.Satisfies, series.IsRetiredSeriesError, gc.Commentf("series %q", name))
+		_, err = series.SeriesVersion(name)
+		c.Check(err, jc.Satisfies, series.IsRetiredSeriesError, gc.Commentf("series %q", name))
+		_, err = series.CanonicalSeries(name)
+		c.Check(err, jc.Satisfies, series.IsRetiredSeriesError, gc.Commentf("series %q", name))
+	}
+	_, err := series.GetOSFromSeries("win7")
+	c.Assert(err, gc.ErrorMatches, `series "win7" is retired`)
+	_, err = series.VersionSeries("win2008r2")
+	c.Assert(err, jc.Satisfies, series.IsRetiredSeriesError)
+	_, err = series.WindowsVersionSeries("Windows Server 2008 R2 Standard")
+	c.Assert(err, jc.Satisfies, series.IsRetiredSeriesError)
+
+	osType, err := series.GetOSFromSeries("win2019")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.Windows)
+
+	series.DefaultRegistry().SetRejectRetiredSeries(false)
+	_, err = series.GetOSFromSeries("win7")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,321 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// maxRemoteDataSize limits how much series data is read from a remote
+	// source, so a misbehaving server can't exhaust memory.
+	maxRemoteDataSize = 10 << 20
+
+	defaultFetchRetries = 3
+	defaultFetchBackoff = time.Second
+	defaultFetchTimeout = 30 * time.Second
+)
+
+// HTTPSource fetches series data over HTTP. Responses are cached, so that
+// unchanged data is revalidated with a conditional request rather than
+// downloaded again, and so that the cached data can be used when the
+// server can't be reached.
+type HTTPSource struct {
+	// URL is the location of the series data.
+	URL string
+	// CacheDir is the directory used to cache the fetched data when Cache
+	// is nil. If both are empty no caching is done.
+	CacheDir string
+	// Cache, if set, caches the fetched data in place of CacheDir.
+	Cache Cache
+	// MaxAge is how long cached data is used without revalidating it, so
+	// that sources sharing a Cache fetch the data once between them. If it
+	// is zero the data is revalidated on every fetch.
+	MaxAge time.Duration
+	// Client is the HTTP client used for requests. If it is nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+	// Retries is the number of times a failed request is retried.
+	Retries int
+	// Backoff is the delay before the first retry, which doubles after
+	// every subsequent failure.
+	Backoff time.Duration
+	// Timeout bounds the total time spent fetching, including retries.
+	Timeout time.Duration
+	// Verifier, if set, checks the fetched data against a detached
+	// signature before it is returned.
+	Verifier Verifier
+	// SignatureURL is the location of the detached signature. If it is
+	// empty, ".sig" is appended to URL.
+	SignatureURL string
+}
+
+// NewHTTPSource returns an HTTPSource for the url, caching into cacheDir,
+// with default retry and timeout settings.
+func NewHTTPSource(url, cacheDir string) *HTTPSource {
+	return &HTTPSource{
+		URL:      url,
+		CacheDir: cacheDir,
+		Retries:  defaultFetchRetries,
+		Backoff:  defaultFetchBackoff,
+		Timeout:  defaultFetchTimeout,
+	}
+}
+
+// errNotRetryable wraps errors that retrying won't fix.
+type errNotRetryable struct {
+	error
+}
+
+// Fetch returns the series data from the source. If the server reports the
+// data is unchanged, or it can't be reached once all retries are exhausted,
+// the cached data is returned instead. When the source has a Verifier,
+// data that fails verification, cached or not, is never returned, and
+// fetched data is only cached once it has been verified.
+func (s *HTTPSource) Fetch(ctx context.Context) ([]byte, error) {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+	return s.fetch(ctx, s.verifyFunc(ctx))
+}
+
+// verifyFunc returns a function that checks data against the detached
+// signature with the source's Verifier, fetching the signature when first
+// needed. It returns nil if the source has no Verifier.
+func (s *HTTPSource) verifyFunc(ctx context.Context) func([]byte) error {
+	if s.Verifier == nil {
+		return nil
+	}
+	var signature []byte
+	return func(data []byte) error {
+		if signature == nil {
+			fetched, err := s.signatureSource().fetch(ctx, nil)
+			if err != nil {
+				return errors.Annotate(err, "fetching signature")
+			}
+			signature = fetched
+		}
+		if err := s.Verifier.Verify(data, signature); err != nil {
+			return errors.Annotatef(err, "verifying series data from %s", s.URL)
+		}
+		return nil
+	}
+}
+
+// signatureSource returns a source for the detached signature of the data,
+// sharing the retry and cache settings of s.
+func (s *HTTPSource) signatureSource() *HTTPSource {
+	source := *s
+	source.URL = s.SignatureURL
+	if source.URL == "" {
+		source.URL = s.URL + ".sig"
+	}
+	source.Verifier = nil
+	return &source
+}
+
+// cache returns the cache of the source, or nil if it has none.
+func (s *HTTPSource) cache() Cache {
+	if s.Cache != nil {
+		return s.Cache
+	}
+	if s.CacheDir != "" {
+		return DirCache(s.CacheDir)
+	}
+	return nil
+}
+
+// fetch returns the data from the source, or from its cache, as Fetch
+// does. If verify is not nil, only data it accepts is returned or cached.
+func (s *HTTPSource) fetch(ctx context.Context, verify func([]byte) error) ([]byte, error) {
+	if verify == nil {
+		verify = func([]byte) error { return nil }
+	}
+	var entry CacheEntry
+	cache := s.cache()
+	if cache != nil {
+		cached, err := cache.Get(ctx, s.URL)
+		if err == nil {
+			entry = cached
+		} else if !errors.IsNotFound(err) {
+			logger.Debugf("ignoring series data cache for %s: %v", s.URL, err)
+		}
+	}
+	if entry.Data != nil && s.MaxAge > 0 && time.Since(entry.Fetched) < s.MaxAge {
+		if err := verify(entry.Data); err == nil {
+			return entry.Data, nil
+		}
+		// The cached data can't be trusted, even to revalidate.
+		entry = CacheEntry{}
+	}
+
+	backoff := s.Backoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		var fetched CacheEntry
+		fetched, err = s.fetchOnce(ctx, entry)
+		if err == nil {
+			if err := verify(fetched.Data); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if cache != nil {
+				if err := cache.Put(ctx, s.URL, fetched); err != nil {
+					logger.Warningf("unable to cache series data from %s: %v", s.URL, err)
+				}
+			}
+			return fetched.Data, nil
+		}
+		if _, ok := err.(errNotRetryable); ok || attempt >= s.Retries {
+			break
+		}
+		logger.Debugf("fetching series data from %s failed, retrying in %v: %v", s.URL, backoff, err)
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(backoff):
+			backoff *= 2
+			continue
+		}
+		break
+	}
+
+	if entry.Data != nil {
+		if verifyErr := verify(entry.Data); verifyErr != nil {
+			return nil, errors.Trace(verifyErr)
+		}
+		logger.Warningf("using cached series data, fetching from %s failed: %v", s.URL, err)
+		return entry.Data, nil
+	}
+	if e, ok := err.(errNotRetryable); ok {
+		err = e.error
+	}
+	return nil, errors.Annotatef(err, "fetching series data from %s", s.URL)
+}
+
+// fetchOnce makes a single request for the data, conditional on the
+// validators of the cached entry, and returns the entry to cache.
+func (s *HTTPSource) fetchOnce(ctx context.Context, cached CacheEntry) (CacheEntry, error) {
+	req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+	if err != nil {
+		return CacheEntry{}, errNotRetryable{errors.Trace(err)}
+	}
+	req = req.WithContext(ctx)
+	if cached.Data != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return CacheEntry{}, errors.Trace(err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached.Data != nil:
+		cached.Fetched = time.Now()
+		return cached, nil
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode >= http.StatusInternalServerError,
+		resp.StatusCode == http.StatusTooManyRequests:
+		return CacheEntry{}, errors.Errorf("unexpected status %q", resp.Status)
+	default:
+		return CacheEntry{}, errNotRetryable{errors.Errorf("unexpected status %q", resp.Status)}
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRemoteDataSize+1))
+	if err != nil {
+		return CacheEntry{}, errors.Trace(err)
+	}
+	if len(data) > maxRemoteDataSize {
+		return CacheEntry{}, errNotRetryable{errors.Errorf("series data exceeds %d bytes", maxRemoteDataSize)}
+	}
+
+	return CacheEntry{
+		Data:         data,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Fetched:      time.Now(),
+	}, nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return errors.Trace(err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(f.Name(), path))
+}
+
+// UpdateFromURL fetches series definitions from the url and applies them,
+// as LoadDefinitions does. Fetched data is cached in cacheDir, which is
+// used to avoid downloading unchanged data and to fall back on when the
+// url can't be reached.
+func UpdateFromURL(ctx context.Context, url, cacheDir string) error {
+	return errors.Trace(UpdateFromSource(ctx, NewHTTPSource(url, cacheDir)))
+}
+
+// UpdateFromSource fetches series definitions from the source and applies
+// them, as LoadDefinitions does. If the source has a Verifier, nothing is
+// applied unless the data passes verification.
+func UpdateFromSource(ctx context.Context, source *HTTPSource) error {
+	data, err := source.Fetch(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(loadDefinitions(bytes.NewReader(data), source.URL))
+}
+
+// PreviewUpdateFromURL fetches series definitions from the url as
+// UpdateFromURL does, and returns the changes that applying them would
+// make to the known series, without applying them. The fetched data is
+// still cached.
+func PreviewUpdateFromURL(ctx context.Context, url, cacheDir string) (ChangeSet, error) {
+	changes, err := PreviewUpdateFromSource(ctx, NewHTTPSource(url, cacheDir))
+	return changes, errors.Trace(err)
+}
+
+// PreviewUpdateFromSource fetches series definitions from the source as
+// UpdateFromSource does, and returns the changes that applying them would
+// make to the known series, without applying them.
+func PreviewUpdateFromSource(ctx context.Context, source *HTTPSource) (ChangeSet, error) {
+	data, err := source.Fetch(ctx)
+	if err != nil {
+		return ChangeSet{}, errors.Trace(err)
+	}
+	changes, err := PreviewDefinitions(bytes.NewReader(data))
+	return changes, errors.Trace(err)
+}
@@ -0,0 +1,343 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type remoteSuite struct {
+	testing.CleanupSuite
+
+	mu        sync.Mutex
+	requests  []*http.Request
+	status    int
+	etag      string
+	body      string
+	signature []byte
+}
+
+var _ = gc.Suite(&remoteSuite{})
+
+func (s *remoteSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.requests = nil
+	s.status = http.StatusOK
+	s.etag = `"v1"`
+	s.body = definitionsData
+	s.signature = nil
+}
+
+func (s *remoteSuite) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	if strings.HasSuffix(r.URL.Path, ".sig") {
+		_, _ = w.Write(s.signature)
+		return
+	}
+	if s.status != http.StatusOK {
+		w.WriteHeader(s.status)
+		return
+	}
+	if r.Header.Get("If-None-Match") == s.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", s.etag)
+	_, _ = w.Write([]byte(s.body))
+}
+
+func (s *remoteSuite) newSource(c *gc.C, url string) *series.HTTPSource {
+	source := series.NewHTTPSource(url, c.MkDir())
+	source.Backoff = time.Millisecond
+	return source
+}
+
+func (s *remoteSuite) TestFetchCachesWithETag(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	source := s.newSource(c, server.URL)
+
+	data, err := source.Fetch(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, definitionsData)
+
+	data, err = source.Fetch(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, definitionsData)
+
+	c.Assert(s.requests, gc.HasLen, 2)
+	c.Assert(s.requests[0].Header.Get("If-None-Match"), gc.Equals, "")
+	c.Assert(s.requests[1].Header.Get("If-None-Match"), gc.Equals, `"v1"`)
+}
+
+func (s *remoteSuite) TestFetchRetriesThenUsesCache(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	source := s.newSource(c, server.URL)
+
+	_, err := source.Fetch(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.status = http.StatusServiceUnavailable
+	data, err := source.Fetch(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, definitionsData)
+	c.Assert(s.requests, gc.HasLen, 2+source.Retries)
+}
+
+func (s *remoteSuite) TestFetchNoCache(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	source := s.newSource(c, server.URL)
+
+	s.status = http.StatusServiceUnavailable
+	_, err := source.Fetch(context.Background())
+	c.Assert(err, gc.ErrorMatches, `fetching series data from .*: unexpected status "503 Service Unavailable"`)
+	c.Assert(s.requests, gc.HasLen, 1+source.Retries)
+}
+
+func (s *remoteSuite) TestFetchNotFoundIsNotRetried(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	source := s.newSource(c, server.URL)
+
+	s.status = http.StatusNotFound
+	_, err := source.Fetch(context.Background())
+	c.Assert(err, gc.ErrorMatches, `fetching series data from .*: unexpected status "404 Not Found"`)
+	c.Assert(s.requests, gc.HasLen, 1)
+}
+
+func (s *remoteSuite) TestFetchDeadline(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	source := s.newSource(c, server.URL)
+	source.Backoff = time.Minute
+	source.Timeout = 10 * time.Millisecond
+
+	s.status = http.StatusServiceUnavailable
+	_, err := source.Fetch(context.Background())
+	c.Assert(err, gc.ErrorMatches, `fetching series data from .*: context deadline exceeded`)
+}
+
+func (s *remoteSuite) TestUpdateFromURL(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+
+	err := series.UpdateFromURL(context.Background(), server.URL, c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+
+	osType, err := series.GetOSFromSeries("centos9")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.CentOS)
+	c.Assert(series.DataVersion().Source, gc.Equals, server.URL)
+}
+
+func (s *remoteSuite) TestUpdateFromSourceWithConcurrentReaders(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	source := s.newSource(c, server.URL)
+
+	var started, stopped sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		started.Add(1)
+		stopped.Add(1)
+		go func() {
+			defer stopped.Done()
+			first := true
+			for {
+				_, _ = series.GetOSFromSeries("spock")
+				_, _ = series.GetOSFromSeries("centos9")
+				if first {
+					started.Done()
+					first = false
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+	started.Wait()
+	for i := 0; i < 10; i++ {
+		undo := series.BackupSeriesState()
+		err := series.UpdateFromSource(context.Background(), source)
+		c.Assert(err, jc.ErrorIsNil)
+		undo()
+	}
+	close(done)
+	stopped.Wait()
+}
+
+func (s *remoteSuite) TestPreviewUpdateFromURL(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+
+	changes, err := series.PreviewUpdateFromURL(context.Background(), server.URL, c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, jc.DeepEquals, []series.Name{"spock"})
+
+	_, err = series.GetOSFromSeries("spock")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+	c.Assert(series.DataVersion().Source, gc.Not(gc.Equals), server.URL)
+}
+
+func (s *remoteSuite) TestFetchVerified(c *gc.C) {
+	public, private, err := ed25519.GenerateKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	source := s.newSource(c, server.URL+"/series.json")
+	source.Verifier = series.SignatureVerifier{Keys: []ed25519.PublicKey{public}}
+
+	s.signature = ed25519.Sign(private, []byte(definitionsData))
+	data, err := source.Fetch(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, definitionsData)
+	c.Assert(s.requests[1].URL.Path, gc.Equals, "/series.json.sig")
+}
+
+func (s *remoteSuite) TestUpdateFromSourceRejectsBadSignature(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+	public, _, err := ed25519.GenerateKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, otherPrivate, err := ed25519.GenerateKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	source := s.newSource(c, server.URL+"/series.json")
+	source.Verifier = series.SignatureVerifier{Keys: []ed25519.PublicKey{public}}
+
+	s.signature = ed25519.Sign(otherPrivate, []byte(definitionsData))
+	err = series.UpdateFromSource(context.Background(), source)
+	c.Assert(err, gc.ErrorMatches, `verifying series data from .*: signature does not match any trusted key`)
+
+	seriesSource, err := series.SeriesSource("centos9")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(seriesSource, gc.Equals, series.SourceEmbedded)
+}
+
+func (s *remoteSuite) TestFetchCachesOnlyVerifiedData(c *gc.C) {
+	public, _, err := ed25519.GenerateKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, otherPrivate, err := ed25519.GenerateKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	cache := &memoryCache{entries: make(map[string]series.CacheEntry)}
+	source := series.NewHTTPSource(server.URL+"/series.json", "")
+	source.Cache = cache
+	source.Retries = 0
+	source.Verifier = series.SignatureVerifier{Keys: []ed25519.PublicKey{public}}
+
+	s.signature = ed25519.Sign(otherPrivate, []byte(definitionsData))
+	_, err = source.Fetch(context.Background())
+	c.Assert(err, gc.ErrorMatches, `verifying series data from .*: signature does not match any trusted key`)
+	_, err = cache.Get(context.Background(), source.URL)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// With the server unreachable, nothing is served from the cache.
+	server.Close()
+	_, err = source.Fetch(context.Background())
+	c.Assert(err, gc.ErrorMatches, `fetching series data from .*`)
+}
+
+// memoryCache is a series.Cache held in memory, standing in for the shared
+// storage of a cluster.
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]series.CacheEntry
+}
+
+func (m *memoryCache) Get(_ context.Context, key string) (series.CacheEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return series.CacheEntry{}, errors.NotFoundf("entry %q", key)
+	}
+	return entry, nil
+}
+
+func (m *memoryCache) Put(_ context.Context, key string, entry series.CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = make(map[string]series.CacheEntry)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+func (s *remoteSuite) TestFetchSharedCache(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	cache := &memoryCache{}
+	for i := 0; i < 3; i++ {
+		source := series.NewHTTPSource(server.URL, "")
+		source.Cache = cache
+		source.MaxAge = time.Hour
+		data, err := source.Fetch(context.Background())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(string(data), gc.Equals, definitionsData)
+	}
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Assert(cache.entries[server.URL].ETag, gc.Equals, `"v1"`)
+}
+
+func (s *remoteSuite) TestFetchSharedCacheRevalidates(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	defer server.Close()
+	cache := &memoryCache{}
+	for i := 0; i < 2; i++ {
+		source := series.NewHTTPSource(server.URL, "")
+		source.Cache = cache
+		_, err := source.Fetch(context.Background())
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(s.requests, gc.HasLen, 2)
+	c.Assert(s.requests[1].Header.Get("If-None-Match"), gc.Equals, `"v1"`)
+}
+
+func (s *remoteSuite) TestDirCache(c *gc.C) {
+	cache := series.DirCache(c.MkDir())
+	_, err := cache.Get(context.Background(), "https://example.com/series")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	entry := series.CacheEntry{
+		Data:    []byte("data"),
+		ETag:    `"v1"`,
+		Fetched: time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	err = cache.Put(context.Background(), "https://example.com/series", entry)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err := cache.Get(context.Background(), "https://example.com/series")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, entry)
+}
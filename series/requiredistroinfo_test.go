@@ -0,0 +1,70 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"bytes"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type requireDistroInfoSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&requireDistroInfoSuite{})
+
+func (s *requireDistroInfoSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	s.PatchValue(&series.UbuntuDistroInfo, filepath.Join(c.MkDir(), "ubuntu.csv"))
+	forget := series.ForgetLoadedSeriesData()
+	s.AddCleanup(func(*gc.C) { forget() })
+}
+
+func (s *requireDistroInfoSuite) TestNotRequiredByDefault(c *gc.C) {
+	c.Assert(series.CheckDistroInfo(), jc.ErrorIsNil)
+	supported, err := series.SupportedJujuControllerSeriesChecked()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.DeepEquals, series.SupportedJujuControllerSeries())
+}
+
+func (s *requireDistroInfoSuite) TestRequiredAndMissing(c *gc.C) {
+	series.DefaultRegistry().SetRequireDistroInfo(true)
+
+	err := series.CheckDistroInfo()
+	c.Assert(err, jc.Satisfies, series.IsNoDistroInfoError)
+	c.Assert(err, gc.ErrorMatches, "distro-info data not available")
+	_, err = series.SupportedJujuControllerSeriesChecked()
+	c.Assert(err, jc.Satisfies, series.IsNoDistroInfoError)
+	_, err = series.SupportedJujuWorkloadSeriesChecked()
+	c.Assert(err, jc.Satisfies, series.IsNoDistroInfoError)
+	_, err = series.SupportedJujuControllerSeriesAtLeast("18.04")
+	c.Assert(err, jc.Satisfies, series.IsNoDistroInfoError)
+}
+
+func (s *requireDistroInfoSuite) TestRequiredAndLoaded(c *gc.C) {
+	series.DefaultRegistry().SetRequireDistroInfo(true)
+
+	var buf bytes.Buffer
+	err := series.WriteBundle(&buf, series.Bundle{Files: []series.BundleFile{{
+		Kind: series.BundleDistroInfo,
+		Name: "ubuntu",
+		Data: []byte("version,codename,series,created,release,eol\n" +
+			"12.04 LTS,Precise Pangolin,precise,2011-10-13,2012-04-26,2017-04-26\n"),
+	}}}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.LoadBundle(&buf, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(series.CheckDistroInfo(), jc.ErrorIsNil)
+	_, err = series.SupportedJujuWorkloadSeriesChecked()
+	c.Assert(err, jc.ErrorIsNil)
+}
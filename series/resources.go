@@ -0,0 +1,80 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// Resources holds the minimum resources recommended for running the base
+// OS of a series, which provisioning code can check instances against
+// before creating them.
+type Resources struct {
+	// MinMemoryMB is the minimum memory in megabytes, or zero if there is
+	// no recommendation.
+	MinMemoryMB uint64 `json:"min-memory-mb,omitempty"`
+	// MinDiskMB is the minimum root disk size in megabytes, or zero if
+	// there is no recommendation.
+	MinDiskMB uint64 `json:"min-disk-mb,omitempty"`
+}
+
+// Check returns an error satisfying errors.IsNotValid if the memory or
+// disk, in megabytes, is less than recommended. A zero memory or disk
+// isn't checked, as it usually means the provider default.
+func (r Resources) Check(memoryMB, diskMB uint64) error {
+	if memoryMB != 0 && memoryMB < r.MinMemoryMB {
+		return errors.NewNotValid(nil, fmt.Sprintf("%dMB of memory is less than the recommended %dMB", memoryMB, r.MinMemoryMB))
+	}
+	if diskMB != 0 && diskMB < r.MinDiskMB {
+		return errors.NewNotValid(nil, fmt.Sprintf("%dMB of disk is less than the recommended %dMB", diskMB, r.MinDiskMB))
+	}
+	return nil
+}
+
+// seriesResources holds the recommended resources of the series that have
+// them. The values follow the documented minimums of each release for a
+// server installation. It is guarded by seriesVersionsMutex.
+var seriesResources = map[string]Resources{
+	"xenial":         {MinMemoryMB: 512, MinDiskMB: 1536},
+	"bionic":         {MinMemoryMB: 1024, MinDiskMB: 2560},
+	"focal":          {MinMemoryMB: 1024, MinDiskMB: 2560},
+	"jammy":          {MinMemoryMB: 1024, MinDiskMB: 2560},
+	"noble":          {MinMemoryMB: 1536, MinDiskMB: 5120},
+	"centos7":        {MinMemoryMB: 1024, MinDiskMB: 10240},
+	"centos8":        {MinMemoryMB: 1536, MinDiskMB: 10240},
+	"centos8-stream": {MinMemoryMB: 1536, MinDiskMB: 10240},
+	"centos9":        {MinMemoryMB: 1536, MinDiskMB: 10240},
+	"win2012r2":      {MinMemoryMB: 2048, MinDiskMB: 32768},
+	"win2016":        {MinMemoryMB: 2048, MinDiskMB: 32768},
+	"win2016nano":    {MinMemoryMB: 512, MinDiskMB: 1024},
+	"win2019":        {MinMemoryMB: 2048, MinDiskMB: 32768},
+	"win2022":        {MinMemoryMB: 2048, MinDiskMB: 32768},
+	"win23h2":        {MinMemoryMB: 2048, MinDiskMB: 32768},
+}
+
+// RegisterSeriesResources sets the recommended resources of a known
+// series, replacing any recommendation it had. Registering zero Resources
+// removes the recommendation.
+func RegisterSeriesResources(series string, resources Resources) error {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	setSeriesResources(string(name), resources)
+	return nil
+}
+
+// setSeriesResources records the recommended resources of the series. The
+// caller must hold seriesVersionsMutex.
+func setSeriesResources(series string, resources Resources) {
+	if resources == (Resources{}) {
+		delete(seriesResources, series)
+		return
+	}
+	seriesResources[series] = resources
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type resourcesSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&resourcesSuite{})
+
+func (s *resourcesSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *resourcesSuite) TestSeriesResources(c *gc.C) {
+	focal, err := series.GetSeries("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(focal.Resources, jc.DeepEquals, &series.Resources{MinMemoryMB: 1024, MinDiskMB: 2560})
+
+	win, err := series.GetSeries("win2019")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(win.Resources.MinDiskMB > focal.Resources.MinDiskMB, jc.IsTrue)
+
+	precise, err := series.GetSeries("precise")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(precise.Resources, gc.IsNil)
+}
+
+func (s *resourcesSuite) TestRegisterSeriesResources(c *gc.C) {
+	err := series.RegisterSeriesResources("Precise", series.Resources{MinMemoryMB: 256})
+	c.Assert(err, jc.ErrorIsNil)
+	precise, err := series.GetSeries("precise")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(precise.Resources, jc.DeepEquals, &series.Resources{MinMemoryMB: 256})
+
+	err = series.RegisterSeriesResources("precise", series.Resources{})
+	c.Assert(err, jc.ErrorIsNil)
+	precise, err = series.GetSeries("precise")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(precise.Resources, gc.IsNil)
+
+	err = series.RegisterSeriesResources("sulu", series.Resources{MinMemoryMB: 256})
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *resourcesSuite) TestDefinitionResources(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04", "resources": {"min-memory-mb": 4096, "min-disk-mb": 8192}}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	picard, err := series.GetSeries("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(picard.Resources, jc.DeepEquals, &series.Resources{MinMemoryMB: 4096, MinDiskMB: 8192})
+}
+
+func (s *resourcesSuite) TestCheck(c *gc.C) {
+	resources := series.Resources{MinMemoryMB: 1024, MinDiskMB: 2560}
+	c.Assert(resources.Check(2048, 8192), jc.ErrorIsNil)
+	c.Assert(resources.Check(0, 0), jc.ErrorIsNil)
+
+	err := resources.Check(512, 8192)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `512MB of memory is less than the recommended 1024MB`)
+	err = resources.Check(2048, 1024)
+	c.Assert(err, gc.ErrorMatches, `1024MB of disk is less than the recommended 2560MB`)
+}
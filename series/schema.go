@@ -0,0 +1,260 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// DefinitionsJSONSchema is the JSON Schema describing the series
+// definitions format read by LoadDefinitions. The same rules are enforced
+// in code, so files that validate against it will load.
+const DefinitionsJSONSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Juju series definitions",
+  "type": "object",
+  "required": ["schema", "series"],
+  "additionalProperties": false,
+  "properties": {
+    "schema": {"type": "integer", "minimum": 1, "maximum": 1},
+    "version": {"type": "string"},
+    "timestamp": {"type": "string", "format": "date-time"},
+    "series": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["series", "os", "version"],
+        "additionalProperties": false,
+        "properties": {
+          "series": {"type": "string", "pattern": "^[a-z][a-z0-9.-]*$"},
+          "os": {"enum": ["ubuntu", "windows", "macos", "osx", "centos", "genericlinux", "opensuse", "kubernetes", "openbsd", "netbsd", "aix", "android", "debian", "fedora", "rhel", "rocky", "almalinux"]},
+          "version": {"type": "string", "minLength": 1},
+          "lts": {"type": "boolean"},
+          "supported": {"type": "boolean"},
+          "esm-supported": {"type": "boolean"},
+          "resources": {
+            "type": "object",
+            "additionalProperties": false,
+            "properties": {
+              "min-memory-mb": {"type": "integer", "minimum": 0},
+              "min-disk-mb": {"type": "integer", "minimum": 0}
+            }
+          },
+          "tags": {
+            "type": "array",
+            "items": {"type": "string", "pattern": "^[a-z0-9][a-z0-9.-]*$"}
+          }
+        }
+      }
+    }
+  }
+}`
+
+// validSeriesName matches the series names allowed in definitions.
+var validSeriesName = regexp.MustCompile(`^[a-z][a-z0-9.-]*$`)
+
+// DefinitionError describes a problem with a single series definition.
+type DefinitionError struct {
+	// Index is the position of the definition in the file.
+	Index int
+	// Series is the name of the series, if it has one.
+	Series string
+	// Field is the name of the offending field.
+	Field string
+	// Message describes the problem.
+	Message string
+}
+
+func (e *DefinitionError) Error() string {
+	where := fmt.Sprintf("series[%d]", e.Index)
+	if e.Series != "" {
+		where += fmt.Sprintf(" (%q)", e.Series)
+	}
+	return fmt.Sprintf("%s: %s %s", where, e.Field, e.Message)
+}
+
+// DefinitionErrors holds every problem found with a series definitions
+// document, so they can all be fixed in one pass.
+type DefinitionErrors []*DefinitionError
+
+func (e DefinitionErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	messages := make([]string, len(e))
+	for i, problem := range e {
+		messages[i] = problem.Error()
+	}
+	return fmt.Sprintf("%d problems with series definitions: %s", len(e), strings.Join(messages, "; "))
+}
+
+// IsDefinitionError returns true if err is caused by a DefinitionError, or
+// by DefinitionErrors.
+func IsDefinitionError(err error) bool {
+	switch errors.Cause(err).(type) {
+	case *DefinitionError, DefinitionErrors:
+		return true
+	}
+	return false
+}
+
+// DefinitionProblems returns the problems with series definitions that
+// caused err, in the order they appear in the definitions.
+func DefinitionProblems(err error) []*DefinitionError {
+	switch err := errors.Cause(err).(type) {
+	case *DefinitionError:
+		return []*DefinitionError{err}
+	case DefinitionErrors:
+		return append([]*DefinitionError(nil), err...)
+	}
+	return nil
+}
+
+// decodeDefinitions reads a series definitions document from r and checks
+// it against DefinitionsJSONSchema.
+func decodeDefinitions(r io.Reader) (definitions, error) {
+	var doc definitions
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return doc, errors.Annotate(err, "reading series definitions")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return doc, errors.Annotatef(err, "decoding series definitions%s", jsonErrorPosition(data, err))
+	}
+	if doc.Schema == 0 {
+		return doc, errors.NotValidf("series definitions without schema version")
+	}
+	if doc.Schema > DefinitionsSchemaVersion {
+		return doc, errors.NotSupportedf("series definitions schema version %d", doc.Schema)
+	}
+
+	var problems DefinitionErrors
+	seen := make(map[Name]int)
+	for i, def := range doc.Series {
+		problems = append(problems, validateDefinition(i, def)...)
+		if def.Series == "" {
+			continue
+		}
+		if first, ok := seen[def.Series]; ok {
+			problems = append(problems, &DefinitionError{
+				Index:   i,
+				Series:  def.Series.String(),
+				Field:   "series",
+				Message: fmt.Sprintf("duplicates series[%d]", first),
+			})
+			continue
+		}
+		seen[def.Series] = i
+	}
+	if len(problems) > 0 {
+		return doc, errors.Trace(problems)
+	}
+	return doc, nil
+}
+
+// validateDefinition checks the definition at index i against the rules of
+// DefinitionsJSONSchema, returning every problem found.
+func validateDefinition(i int, def Definition) DefinitionErrors {
+	var problems DefinitionErrors
+	fail := func(field, format string, args ...interface{}) {
+		problems = append(problems, &DefinitionError{
+			Index:   i,
+			Series:  def.Series.String(),
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	switch {
+	case def.Series == "":
+		fail("series", "is required")
+	case !validSeriesName.MatchString(def.Series.String()):
+		fail("series", "must match %s", validSeriesName)
+	}
+	if def.OS == "" {
+		fail("os", "is required")
+	} else if _, err := parseDefinitionOS(def.OS); err != nil {
+		names := make([]string, len(definitionOSTypes))
+		for i, osType := range definitionOSTypes {
+			names[i] = os.FormatOSType(osType)
+		}
+		fail("os", "%q is not one of %s", def.OS, strings.Join(names, ", "))
+	}
+	if def.Version == "" {
+		fail("version", "is required")
+	}
+	for _, tag := range def.Tags {
+		if !validTag.MatchString(tag) {
+			fail("tags", "%q must match %s", tag, validTag)
+		}
+	}
+	return problems
+}
+
+// jsonErrorPosition returns the line and column of a JSON decoding error
+// within data, if the error records an offset.
+func jsonErrorPosition(data []byte, err error) string {
+	var offset int64
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		offset = err.Offset
+	case *json.UnmarshalTypeError:
+		offset = err.Offset
+	default:
+		return ""
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return fmt.Sprintf(" at line %d, column %d", line, column)
+}
+
+// definitionOSTypes holds the operating systems that series may be defined
+// for.
+var definitionOSTypes = []os.OSType{
+	os.Ubuntu,
+	os.Windows,
+	os.MacOS,
+	os.CentOS,
+	os.GenericLinux,
+	os.OpenSUSE,
+	os.Kubernetes,
+	os.OpenBSD,
+	os.NetBSD,
+	os.AIX,
+	os.Android,
+	os.Debian,
+	os.Fedora,
+	os.RHEL,
+	os.Rocky,
+	os.AlmaLinux,
+}
+
+// parseDefinitionOS returns the OS type for the lowercase name used in
+// definitions. MacOS may also be given by its former name, "osx".
+func parseDefinitionOS(name string) (os.OSType, error) {
+	osType, err := os.ParseOSType(name)
+	if err != nil || name != strings.ToLower(strings.TrimSpace(name)) {
+		return os.Unknown, errors.NotValidf("OS %q", name)
+	}
+	for _, defined := range definitionOSTypes {
+		if osType == defined {
+			return osType, nil
+		}
+	}
+	return os.Unknown, errors.NotValidf("OS %q", name)
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// FeedIdentifiers holds the identifiers that vulnerability feeds use for a
+// series, so that host inventory can be joined to CVE data. Fields that
+// don't apply to the series are empty.
+type FeedIdentifiers struct {
+	// Codename is the release codename used by Ubuntu OVAL data and
+	// security notices, for example "focal".
+	Codename string
+	// CPE is the CPE name of the operating system that vendor OVAL data
+	// is keyed on, for example "cpe:/o:canonical:ubuntu_linux:20.04". CentOS
+	// series use the Red Hat Enterprise Linux CPE, as CentOS is covered by
+	// Red Hat's security data.
+	CPE string
+	// MSRC is the product name used by the Microsoft Security Response
+	// Center, for example "Windows Server 2019".
+	MSRC string
+}
+
+// msrcProducts maps windows series to their MSRC product names.
+var msrcProducts = map[string]string{
+	"win2008r2":   "Windows Server 2008 R2",
+	"win2012":     "Windows Server 2012",
+	"win2012hv":   "Windows Server 2012",
+	"win2012r2":   "Windows Server 2012 R2",
+	"win2012hvr2": "Windows Server 2012 R2",
+	"win2016":     "Windows Server 2016",
+	"win2016hv":   "Windows Server 2016",
+	"win2016nano": "Windows Server 2016",
+	"win2019":     "Windows Server 2019",
+	"win2022":     "Windows Server 2022",
+	"win23h2":     "Windows Server, version 23H2",
+	"win7":        "Windows 7",
+	"win8":        "Windows 8",
+	"win81":       "Windows 8.1",
+	"win10":       "Windows 10",
+	"win11":       "Windows 11",
+}
+
+// SecurityFeedIdentifiers returns the identifiers vulnerability feeds use
+// for the series. A NotFound error is returned for series whose operating
+// system isn't covered by any feed.
+func SecurityFeedIdentifiers(series string) (FeedIdentifiers, error) {
+	var ids FeedIdentifiers
+	osType, err := GetOSFromSeries(series)
+	if err != nil {
+		return ids, errors.Trace(err)
+	}
+	name := FormatSeries(series)
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+
+	switch osType {
+	case os.Ubuntu:
+		ids.Codename = name
+		version := strings.TrimSuffix(ubuntuSeries[name].Version, " LTS")
+		ids.CPE = "cpe:/o:canonical:ubuntu_linux:" + version
+	case os.CentOS:
+		if version := nonUbuntuVersion(centosSeries, name); version != "" {
+			release := strings.TrimSuffix(strings.TrimPrefix(version, "centos"), "-stream")
+			ids.CPE = "cpe:/o:redhat:enterprise_linux:" + release
+		}
+	case os.OpenSUSE:
+		if version := nonUbuntuVersion(opensuseSeries, name); version != "" {
+			ids.CPE = "cpe:/o:opensuse:leap:" + strings.TrimPrefix(version, "opensuse")
+		}
+	case os.Windows:
+		ids.MSRC = msrcProducts[name]
+	}
+	if ids == (FeedIdentifiers{}) {
+		return ids, errors.NotFoundf("security feed identifiers for %s series %q", osType, series)
+	}
+	return ids, nil
+}
+
+// nonUbuntuVersion returns the version of the series from the table, or for
+// series added by definitions, from nonUbuntuSeries. The caller must hold
+// seriesVersionsMutex.
+func nonUbuntuVersion(table map[string]string, series string) string {
+	if version, ok := table[series]; ok {
+		return version
+	}
+	return nonUbuntuSeries[series].Version
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type securityFeedsSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&securityFeedsSuite{})
+
+func (s *securityFeedsSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	cleanup := series.SetSeriesVersions(make(map[string]string))
+	s.AddCleanup(func(*gc.C) { cleanup() })
+}
+
+func (s *securityFeedsSuite) TestSecurityFeedIdentifiers(c *gc.C) {
+	for i, test := range []struct {
+		series string
+		want   series.FeedIdentifiers
+	}{{
+		series: "bionic",
+		want: series.FeedIdentifiers{
+			Codename: "bionic",
+			CPE:      "cpe:/o:canonical:ubuntu_linux:18.04",
+		},
+	}, {
+		series: "centos8",
+		want:   series.FeedIdentifiers{CPE: "cpe:/o:redhat:enterprise_linux:8"},
+	}, {
+		series: "centos8-stream",
+		want:   series.FeedIdentifiers{CPE: "cpe:/o:redhat:enterprise_linux:8"},
+	}, {
+		series: "opensuseleap",
+		want:   series.FeedIdentifiers{CPE: "cpe:/o:opensuse:leap:42"},
+	}, {
+		series: "opensuse15.6",
+		want:   series.FeedIdentifiers{CPE: "cpe:/o:opensuse:leap:15.6"},
+	}, {
+		series: "win2012hvr2",
+		want:   series.FeedIdentifiers{MSRC: "Windows Server 2012 R2"},
+	}, {
+		series: "Win10",
+		want:   series.FeedIdentifiers{MSRC: "Windows 10"},
+	}} {
+		c.Logf("test %d: %s", i, test.series)
+		ids, err := series.SecurityFeedIdentifiers(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(ids, jc.DeepEquals, test.want)
+	}
+}
+
+func (s *securityFeedsSuite) TestSecurityFeedIdentifiersDefinedSeries(c *gc.C) {
+	err := series.RegisterSeries(series.Definition{Series: "centos9", OS: "centos", Version: "centos9"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ids, err := series.SecurityFeedIdentifiers("centos9")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids.CPE, gc.Equals, "cpe:/o:redhat:enterprise_linux:9")
+}
+
+func (s *securityFeedsSuite) TestSecurityFeedIdentifiersNotCovered(c *gc.C) {
+	_, err := series.SecurityFeedIdentifiers("kubernetes")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `security feed identifiers for Kubernetes series "kubernetes" not found`)
+
+	_, err = series.SecurityFeedIdentifiers("firewolf")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
@@ -31,16 +31,22 @@ var (
 )
 
 // HostSeries returns the series of the machine the current process is
-// running on.
+// running on. Hosts that aren't recognized, or are only recognized as
+// genericlinux, are passed to the providers added with
+// RegisterDetectionProvider. The series is determined once, so providers
+// have to be registered before the first call.
 func HostSeries() (string, error) {
 	var err error
 	seriesOnce.Do(func() {
-		series, err = readSeries()
+		series, err = detectHostSeries()
 		if err != nil {
 			seriesErr = errors.Annotate(err, "cannot determine host series")
 		}
 	})
-	return series, seriesErr
+	if seriesErr != nil {
+		return series, seriesErr
+	}
+	return defaultRegistry.intercept(LookupHostSeries, "", series)
 }
 
 // mustHostSeries calls HostSeries and panics if there is an error.
@@ -62,6 +68,27 @@ func MustOSFromSeries(series string) os.OSType {
 	return operatingSystem
 }
 
+// MustSeriesVersion returns the version of the series, and panics if the
+// series is unknown. It is intended for initializing package level
+// variables from constant series names, like regexp.MustCompile.
+func MustSeriesVersion(series string) string {
+	version, err := SeriesVersion(series)
+	if err != nil {
+		panic("SeriesVersion reported an error: " + err.Error())
+	}
+	return version
+}
+
+// MustVersionSeries returns the series for the version, and panics if the
+// version is unknown.
+func MustVersionSeries(version string) string {
+	series, err := VersionSeries(version)
+	if err != nil {
+		panic("VersionSeries reported an error: " + err.Error())
+	}
+	return series
+}
+
 // kernelToMajor takes a dotted version and returns just the Major portion
 func kernelToMajor(getKernelVersion func() (string, error)) (int, error) {
 	fullVersion, err := getKernelVersion()
@@ -90,28 +117,40 @@ func macOSXSeriesFromKernelVersion(getKernelVersion func() (string, error)) (str
 // recompiling Juju. For now, this is a lot easier, and also solves the fact
 // that we want to populate HostSeries during init() time, before
 // we've potentially read that information from anywhere else
-// macOSXSeries maps from the Darwin Kernel Major Version to the Mac OSX
-// series.
-var macOSXSeries = map[int]string{
-	19: "catalina",
-	18: "mojave",
-	17: "highsierra",
-	16: "sierra",
-	15: "elcapitan",
-	14: "yosemite",
-	13: "mavericks",
-	12: "mountainlion",
-	11: "lion",
-	10: "snowleopard",
-	9:  "leopard",
-	8:  "tiger",
-	7:  "panther",
-	6:  "jaguar",
-	5:  "puma",
+var (
+	macOSXSeriesOnce sync.Once
+
+	// macOSXSeries maps from the Darwin Kernel Major Version to the Mac OSX
+	// series. It is built the first time it's needed.
+	macOSXSeries map[int]string
+)
+
+// macOSXSeriesTable returns macOSXSeries, building it if needed.
+func macOSXSeriesTable() map[int]string {
+	macOSXSeriesOnce.Do(func() {
+		macOSXSeries = map[int]string{
+			19: "catalina",
+			18: "mojave",
+			17: "highsierra",
+			16: "sierra",
+			15: "elcapitan",
+			14: "yosemite",
+			13: "mavericks",
+			12: "mountainlion",
+			11: "lion",
+			10: "snowleopard",
+			9:  "leopard",
+			8:  "tiger",
+			7:  "panther",
+			6:  "jaguar",
+			5:  "puma",
+		}
+	})
+	return macOSXSeries
 }
 
 func macOSXSeriesFromMajorVersion(majorVersion int) (string, error) {
-	series, ok := macOSXSeries[majorVersion]
+	series, ok := macOSXSeriesTable()[majorVersion]
 	if !ok {
 		return "unknown", errors.Errorf("unknown series version %d", majorVersion)
 	}
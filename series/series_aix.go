@@ -0,0 +1,43 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build aix
+
+package series
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// uname returns the output of uname with the given flag.
+func uname(flag string) (string, error) {
+	out, err := exec.Command("uname", flag).Output()
+	if err != nil {
+		return "", errors.Annotatef(err, "running uname %s", flag)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// readSeries returns the series of the AIX or IBM i host, from the
+// operating system name, version and release that uname reports.
+func readSeries() (string, error) {
+	var values [3]string
+	for i, flag := range []string{"-s", "-v", "-r"} {
+		value, err := uname(flag)
+		if err != nil {
+			return "unknown", errors.Trace(err)
+		}
+		values[i] = value
+	}
+	return aixSeriesFromUname(values[0], values[1], values[2])
+}
+
+// platformCapabilities holds the capabilities of this platform.
+// The release is read with uname.
+var platformCapabilities = PlatformCapabilities{
+	SeriesDetection: true,
+	ExecProbes:      true,
+}
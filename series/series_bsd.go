@@ -0,0 +1,31 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build openbsd netbsd
+
+package series
+
+import (
+	"syscall"
+
+	"github.com/juju/errors"
+)
+
+// readSeries returns the series of the BSD host, from the operating system
+// name and release that uname reports.
+func readSeries() (string, error) {
+	sysname, err := syscall.Sysctl("kern.ostype")
+	if err != nil {
+		return "unknown", errors.Trace(err)
+	}
+	release, err := syscall.Sysctl("kern.osrelease")
+	if err != nil {
+		return "unknown", errors.Trace(err)
+	}
+	return bsdSeriesFromUname(sysname, release)
+}
+
+// platformCapabilities holds the capabilities of this platform.
+var platformCapabilities = PlatformCapabilities{
+	SeriesDetection: true,
+}
@@ -15,3 +15,10 @@ func sysctlVersion() (string, error) {
 func readSeries() (string, error) {
 	return macOSXSeriesFromKernelVersion(sysctlVersion)
 }
+
+// platformCapabilities holds the capabilities of this platform.
+// The release name is read with sw_vers.
+var platformCapabilities = PlatformCapabilities{
+	SeriesDetection: true,
+	ExecProbes:      true,
+}
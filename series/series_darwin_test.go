@@ -22,7 +22,7 @@ func (*macOSXSeriesSuite) TestGetSysctlVersionPlatform(c *gc.C) {
 
 func (s *macOSXSeriesSuite) TestOSVersion(c *gc.C) {
 	knownSeries := make(set.Strings)
-	for _, series := range macOSXSeries {
+	for _, series := range macOSXSeriesTable() {
 		knownSeries.Add(series)
 	}
 	version, err := readSeries()
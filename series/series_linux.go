@@ -5,7 +5,10 @@ package series
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,8 +20,26 @@ var (
 	// osReleaseFile is the name of the file that is read in order to determine
 	// the linux type release version.
 	osReleaseFile = "/etc/os-release"
+
+	// centosReleaseFile names the CentOS release, which distinguishes
+	// CentOS Stream 8 from CentOS Linux 8 on older Stream installs whose
+	// os-release doesn't.
+	centosReleaseFile = "/etc/centos-release"
+
+	// androidBuildPropFile holds the Android system properties. It only
+	// exists on Android.
+	androidBuildPropFile = "/system/build.prop"
+	// getprop returns the output of "getprop name", which reads an
+	// Android system property even when the properties file is unreadable.
+	getprop = func(name string) ([]byte, error) {
+		return exec.Command("getprop", name).Output()
+	}
 )
 
+// androidReleaseProperty is the Android system property holding the
+// release, for example "14".
+const androidReleaseProperty = "ro.build.version.release"
+
 const (
 	// this is just for an approximation in an error case, when the eol
 	// date has a parse error.
@@ -27,29 +48,159 @@ const (
 )
 
 func readSeries() (string, error) {
+	if _, err := os.Stat(androidBuildPropFile); err == nil {
+		return readAndroidSeries()
+	}
 	values, err := jujuos.ReadOSRelease(osReleaseFile)
 	if err != nil {
 		return "unknown", err
 	}
+	return hostSeriesFromOSRelease(values)
+}
+
+// hostSeriesFromOSRelease returns the series of a host with the os-release
+// values, loading the series data first if it hasn't been.
+func hostSeriesFromOSRelease(values map[string]string) (string, error) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
 	updateSeriesVersionsOnce()
 	return seriesFromOSRelease(values)
 }
 
+// seriesFromOSRelease returns the series of a host with the os-release
+// values. The caller must hold seriesVersionsMutex.
 func seriesFromOSRelease(values map[string]string) (string, error) {
 	switch values["ID"] {
-	case strings.ToLower(jujuos.Ubuntu.String()):
+	case jujuos.FormatOSType(jujuos.Ubuntu):
 		return getValueFromSeriesVersion(ubuntuSeries, values["VERSION_ID"])
-	case strings.ToLower(jujuos.CentOS.String()):
+	case jujuos.FormatOSType(jujuos.CentOS):
 		codename := fmt.Sprintf("%s%s", values["ID"], values["VERSION_ID"])
+		if codename == "centos8" && isCentOSStream(values) {
+			codename = "centos8-stream"
+		}
 		return getValue(centosSeries, codename)
-	case strings.ToLower(jujuos.OpenSUSE.String()):
+	case jujuos.FormatOSType(jujuos.RHEL):
+		// Major versions without a series, such as RHEL 7, are generic
+		// linux.
+		codename := values["ID"] + strings.Split(values["VERSION_ID"], ".")[0]
+		if series, err := getValue(rhelSeries, codename); err == nil {
+			return series, nil
+		}
+	case jujuos.FormatOSType(jujuos.Rocky):
+		// Major versions without a series yet are generic linux.
+		codename := values["ID"] + strings.Split(values["VERSION_ID"], ".")[0]
+		if series, err := getValue(rockySeries, codename); err == nil {
+			return series, nil
+		}
+	case jujuos.FormatOSType(jujuos.AlmaLinux):
+		// Major versions without a series yet are generic linux.
+		codename := "alma" + strings.Split(values["VERSION_ID"], ".")[0]
+		if series, err := getValue(almaSeries, codename); err == nil {
+			return series, nil
+		}
+	case jujuos.FormatOSType(jujuos.OpenSUSE):
 		codename := fmt.Sprintf("%s%s",
 			values["ID"],
 			strings.Split(values["VERSION_ID"], ".")[0])
 		return getValue(opensuseSeries, codename)
-	default:
-		return genericLinuxSeries, nil
+	case "opensuse-leap":
+		// Leap 15 onwards has a series for each service pack. Service
+		// packs without a series are generic linux.
+		if series, err := getValue(opensuseSeries, "opensuse"+values["VERSION_ID"]); err == nil {
+			return series, nil
+		}
+	case jujuos.FormatOSType(jujuos.Debian):
+		// Testing and unstable have no VERSION_ID, so they, like releases
+		// without a series yet, are classified as generic linux.
+		if series, err := getValue(debianSeries, values["VERSION_ID"]); err == nil {
+			return series, nil
+		}
+	case jujuos.FormatOSType(jujuos.Fedora):
+		// Rawhide, and releases without a series yet, are generic linux.
+		if series, err := getValue(fedoraSeries, values["VERSION_ID"]); err == nil {
+			return series, nil
+		}
+	}
+	if series, ok := genericLinuxProfileSeries(values["ID"], values["VERSION_ID"]); ok {
+		return series, nil
+	}
+	return genericLinuxSeries, nil
+}
+
+// ExplainHostSeries explains how the series of the host was determined
+// from its os-release file, and in particular why it was classified as
+// genericlinux, for triaging misclassified hosts.
+func ExplainHostSeries() (HostClassification, error) {
+	if _, err := os.Stat(androidBuildPropFile); err == nil {
+		series, err := readAndroidSeries()
+		if err != nil {
+			return HostClassification{}, errors.Trace(err)
+		}
+		return HostClassification{
+			Series: series,
+			Signals: []ClassificationSignal{{
+				Name:    "Android system properties",
+				Value:   androidBuildPropFile,
+				Present: true,
+				Note:    "Android hosts are classified before os-release is read",
+			}},
+		}, nil
 	}
+	values, err := jujuos.ReadOSRelease(osReleaseFile)
+	if err != nil {
+		return HostClassification{}, errors.Trace(err)
+	}
+	return explainHostOSRelease(values), nil
+}
+
+// explainHostOSRelease explains the classification of a host with the
+// os-release values.
+func explainHostOSRelease(values map[string]string) HostClassification {
+	series, err := hostSeriesFromOSRelease(values)
+	if err != nil {
+		series = ""
+	}
+	return explainOSRelease(values, series)
+}
+
+// readAndroidSeries returns the series of an Android host, from the
+// release in the system properties.
+func readAndroidSeries() (string, error) {
+	var release string
+	if values, err := jujuos.ReadAndroidProperties(androidBuildPropFile); err == nil {
+		release = values[androidReleaseProperty]
+	}
+	if release == "" {
+		out, err := getprop(androidReleaseProperty)
+		if err != nil {
+			return "unknown", errors.Annotatef(err, "reading %s", androidReleaseProperty)
+		}
+		release = strings.TrimSpace(string(out))
+	}
+	return androidSeriesFromRelease(release)
+}
+
+// androidSeriesFromRelease returns the series for an Android release, for
+// example "14" or "8.1.0", which are named after the major version.
+func androidSeriesFromRelease(release string) (string, error) {
+	major := strings.SplitN(strings.TrimSpace(release), ".", 2)[0]
+	if _, err := strconv.Atoi(major); err != nil {
+		return "unknown", errors.NotValidf("android release %q", release)
+	}
+	return "android" + major, nil
+}
+
+// isCentOSStream reports whether the os-release values, or failing that the
+// CentOS release file, describe CentOS Stream rather than CentOS Linux.
+func isCentOSStream(values map[string]string) bool {
+	if strings.Contains(values["NAME"], "Stream") {
+		return true
+	}
+	release, err := ioutil.ReadFile(centosReleaseFile)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(string(release), "CentOS Stream")
 }
 
 func getValue(from map[string]string, val string) (string, error) {
@@ -89,37 +240,14 @@ func updateLocalSeriesVersions() error {
 		return errors.Trace(err)
 	}
 
-	now := time.Now().UTC()
-
-	for seriesName, version := range distroInfo.info {
-		var esm bool
-		if existing, ok := ubuntuSeries[seriesName]; ok {
-			esm = existing.ESMSupported
-		}
-
-		// The numeric version may contain a LTS moniker so strip that out.
-		trimmedVersion := strings.TrimSuffix(version.Version, " LTS")
-		seriesVersions[seriesName] = trimmedVersion
-
-		// If the series already exists inside of ubuntuSeries then don't
-		// overwrite that existing one, except to update the supported status.
-		supported := version.Supported(now)
-
-		if us, ok := ubuntuSeries[seriesName]; ok {
-			us.Supported = supported
-			ubuntuSeries[seriesName] = us
-			continue
-		}
-
-		ubuntuSeries[seriesName] = seriesVersion{
-			Version:                  version.Version,
-			Supported:                supported,
-			ESMSupported:             esm,
-			LTS:                      version.LTS(),
-			CreatedByLocalDistroInfo: true,
-		}
+	if len(distroInfo.info) > 0 {
+		setDataVersion(DataVersionInfo{
+			Source:    distroInfo.path,
+			Timestamp: distroInfo.modTime,
+		})
 	}
 
+	applyDistroInfo(distroInfo.info)
 	return nil
 }
 
@@ -134,3 +262,11 @@ func (defaultFileSystem) Exists(path string) bool {
 	_, err := os.Stat(path)
 	return !os.IsNotExist(err)
 }
+
+// platformCapabilities holds the capabilities of this platform.
+// Android and the host's init system and libc are probed with commands.
+var platformCapabilities = PlatformCapabilities{
+	SeriesDetection: true,
+	ExecProbes:      true,
+	DistroInfo:      true,
+}
@@ -4,13 +4,16 @@
 package series_test
 
 import (
+	"bytes"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	jujuos "github.com/juju/os"
 	"github.com/juju/os/series"
 )
 
@@ -83,6 +86,81 @@ func (s *linuxVersionSuite) TestOSVersion(c *gc.C) {
 	c.Assert(spock.Supported, jc.IsFalse)
 }
 
+func (s *linuxVersionSuite) TestDataVersionFromDistroInfo(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+
+	distroInfo := filepath.Join(c.MkDir(), "ubuntu.csv")
+	err := ioutil.WriteFile(distroInfo, []byte(distroInfoContents), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(series.UbuntuDistroInfoPath, distroInfo)
+	info, err := os.Stat(distroInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = series.UpdateSeriesVersions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(series.DataVersion(), jc.DeepEquals, series.DataVersionInfo{
+		Source:    distroInfo,
+		Timestamp: info.ModTime(),
+	})
+}
+
+func (s *linuxVersionSuite) TestDistroInfoDoesNotOverwriteDefinitions(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+
+	distroInfo := filepath.Join(c.MkDir(), "ubuntu.csv")
+	err := ioutil.WriteFile(distroInfo, []byte(distroInfoContents), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(series.UbuntuDistroInfoPath, distroInfo)
+
+	err = series.RegisterSeries(series.Definition{
+		Series:    "spock",
+		OS:        "ubuntu",
+		Version:   "99.10",
+		Supported: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.UpdateSeriesVersions()
+	c.Assert(err, jc.ErrorIsNil)
+
+	version, err := series.SeriesVersion("spock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "99.10")
+	c.Assert(series.UbuntuSupportedSeries()["spock"].Supported, jc.IsTrue)
+
+	source, err := series.SeriesSource("precise")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(source, gc.Equals, series.SourceDistroInfo)
+}
+
+func (s *linuxVersionSuite) TestWriteDistroInfoCSV(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+
+	distroInfo := filepath.Join(c.MkDir(), "ubuntu.csv")
+	err := ioutil.WriteFile(distroInfo, []byte(distroInfoContents), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(series.UbuntuDistroInfoPath, distroInfo)
+	err = series.UpdateSeriesVersions()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var buf bytes.Buffer
+	err = series.WriteDistroInfoCSV(&buf, jujuos.Ubuntu)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), jc.Contains, "12.04,Precise Pangolin,precise,2011-10-13,2012-04-26,2017-04-26\n")
+	c.Assert(buf.String(), jc.HasSuffix, "99.04,Star Trek,spock,2364-04-25,2364-10-17,2365-07-17\n")
+
+	// The output can be read back as distro-info.
+	err = ioutil.WriteFile(distroInfo, buf.Bytes(), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	info := series.NewDistroInfo(distroInfo)
+	c.Assert(info.Refresh(), jc.ErrorIsNil)
+	spock, ok := info.SeriesInfo("spock")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(spock.CodeName, gc.Equals, "Star Trek")
+}
+
 func (s *linuxVersionSuite) TestUseFastLXC(c *gc.C) {
 	for i, test := range []struct {
 		message        string
@@ -256,6 +334,97 @@ ID=opensuse
 VERSION_ID="42.3"`,
 	"opensuseleap",
 	"",
+}, {
+	`NAME="openSUSE Leap"
+ID="opensuse-leap"
+ID_LIKE="suse opensuse"
+VERSION_ID="15.5"`,
+	"opensuse15.5",
+	"",
+}, {
+	`NAME="openSUSE Leap"
+ID="opensuse-leap"
+VERSION_ID="15.1"`,
+	"genericlinux",
+	"",
+}, {
+	`PRETTY_NAME="Debian GNU/Linux 11 (bullseye)"
+NAME="Debian GNU/Linux"
+VERSION_ID="11"
+VERSION="11 (bullseye)"
+VERSION_CODENAME=bullseye
+ID=debian
+`,
+	"bullseye",
+	"",
+}, {
+	`PRETTY_NAME="Debian GNU/Linux trixie/sid"
+NAME="Debian GNU/Linux"
+ID=debian
+`,
+	"genericlinux",
+	"",
+}, {
+	`NAME="Fedora Linux"
+VERSION="39 (Cloud Edition)"
+ID=fedora
+VERSION_ID=39
+PRETTY_NAME="Fedora Linux 39 (Cloud Edition)"
+`,
+	"fedora39",
+	"",
+}, {
+	`NAME="Red Hat Enterprise Linux"
+VERSION="8.8 (Ootpa)"
+ID="rhel"
+ID_LIKE="fedora"
+VERSION_ID="8.8"
+`,
+	"rhel8",
+	"",
+}, {
+	`NAME="Red Hat Enterprise Linux Server"
+ID="rhel"
+VERSION_ID="7.9"
+`,
+	"genericlinux",
+	"",
+}, {
+	`NAME="Rocky Linux"
+VERSION="9.3 (Blue Onyx)"
+ID="rocky"
+ID_LIKE="rhel centos fedora"
+VERSION_ID="9.3"
+`,
+	"rocky9",
+	"",
+}, {
+	`NAME="Rocky Linux"
+VERSION="10.0 (Red Quartz)"
+ID="rocky"
+ID_LIKE="rhel centos fedora"
+VERSION_ID="10.0"
+`,
+	"genericlinux",
+	"",
+}, {
+	`NAME="AlmaLinux"
+VERSION="8.10 (Cerulean Leopard)"
+ID="almalinux"
+ID_LIKE="rhel centos fedora"
+VERSION_ID="8.10"
+`,
+	"alma8",
+	"",
+}, {
+	`NAME="AlmaLinux"
+VERSION="10.0 (Purple Lion)"
+ID="almalinux"
+ID_LIKE="rhel centos fedora"
+VERSION_ID="10.0"
+`,
+	"genericlinux",
+	"",
 },
 }
 
@@ -263,6 +432,7 @@ func (s *readSeriesSuite) TestReadSeries(c *gc.C) {
 	d := c.MkDir()
 	f := filepath.Join(d, "foo")
 	s.PatchValue(series.OSReleaseFile, f)
+	s.PatchValue(series.CentOSReleaseFile, filepath.Join(d, "centos-release"))
 	for i, t := range readSeriesTests {
 		c.Logf("test %d", i)
 		err := ioutil.WriteFile(f, []byte(t.contents), 0666)
@@ -277,3 +447,102 @@ func (s *readSeriesSuite) TestReadSeries(c *gc.C) {
 		c.Assert(series, gc.Equals, t.series)
 	}
 }
+
+func (s *readSeriesSuite) TestReadSeriesCentOS(c *gc.C) {
+	d := c.MkDir()
+	osRelease := filepath.Join(d, "os-release")
+	centosRelease := filepath.Join(d, "centos-release")
+	s.PatchValue(series.OSReleaseFile, osRelease)
+	s.PatchValue(series.CentOSReleaseFile, centosRelease)
+	for i, t := range []struct {
+		osRelease     string
+		centosRelease string
+		series        string
+	}{{
+		osRelease:     "NAME=\"CentOS Linux\"\nID=\"centos\"\nVERSION_ID=\"8\"\n",
+		centosRelease: "CentOS Linux release 8.5.2111\n",
+		series:        "centos8",
+	}, {
+		osRelease: "NAME=\"CentOS Stream\"\nID=\"centos\"\nVERSION_ID=\"8\"\n",
+		series:    "centos8-stream",
+	}, {
+		osRelease:     "NAME=\"CentOS Linux\"\nID=\"centos\"\nVERSION_ID=\"8\"\n",
+		centosRelease: "CentOS Stream release 8\n",
+		series:        "centos8-stream",
+	}, {
+		osRelease:     "NAME=\"CentOS Stream\"\nID=\"centos\"\nVERSION_ID=\"9\"\n",
+		centosRelease: "CentOS Stream release 9\n",
+		series:        "centos9",
+	}} {
+		c.Logf("test %d", i)
+		err := ioutil.WriteFile(osRelease, []byte(t.osRelease), 0666)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(centosRelease, []byte(t.centosRelease), 0666)
+		c.Assert(err, jc.ErrorIsNil)
+		series, err := series.ReadSeries()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(series, gc.Equals, t.series)
+	}
+}
+
+func (s *readSeriesSuite) TestReadSeriesAndroid(c *gc.C) {
+	buildProp := filepath.Join(c.MkDir(), "build.prop")
+	s.PatchValue(series.AndroidBuildPropFile, buildProp)
+	err := ioutil.WriteFile(buildProp, []byte("ro.build.version.release=8.1.0\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := series.ReadAndroidSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, gc.Equals, "android8")
+
+	// The properties file isn't readable by apps on recent releases, so
+	// getprop is used instead.
+	err = ioutil.WriteFile(buildProp, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(series.Getprop, func(name string) ([]byte, error) {
+		c.Check(name, gc.Equals, "ro.build.version.release")
+		return []byte("14\n"), nil
+	})
+	got, err = series.ReadAndroidSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, gc.Equals, "android14")
+
+	s.PatchValue(series.Getprop, func(string) ([]byte, error) {
+		return []byte("UpsideDownCake\n"), nil
+	})
+	_, err = series.ReadAndroidSeries()
+	c.Assert(err, gc.ErrorMatches, `android release "UpsideDownCake" not valid`)
+}
+
+func (s *readSeriesSuite) TestReadSeriesGenericLinuxProfile(c *gc.C) {
+	restore := series.HideGenericLinuxProfiles()
+	defer restore()
+
+	err := series.RegisterGenericLinuxProfile("fedora", "24", "fedora24")
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.RegisterGenericLinuxProfile("fedora", "", "fedora")
+	c.Assert(err, jc.ErrorIsNil)
+
+	f := filepath.Join(c.MkDir(), "os-release")
+	s.PatchValue(series.OSReleaseFile, f)
+	for i, t := range []struct {
+		contents string
+		series   string
+	}{{
+		"ID=fedora\nVERSION_ID=24\n",
+		"fedora24",
+	}, {
+		"ID=fedora\nVERSION_ID=25\n",
+		"fedora",
+	}, {
+		"ID=arch\n",
+		"genericlinux",
+	}} {
+		c.Logf("test %d", i)
+		err := ioutil.WriteFile(f, []byte(t.contents), 0666)
+		c.Assert(err, jc.ErrorIsNil)
+		series, err := series.ReadSeries()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(series, gc.Equals, t.series)
+	}
+}
@@ -5,7 +5,12 @@
 
 package series
 
-import "os"
+import (
+	"os"
+	"runtime"
+
+	"github.com/juju/errors"
+)
 
 // TODO(ericsnow) Refactor dependents so we can remove this for non-linux.
 
@@ -14,6 +19,12 @@ func ReleaseVersion() string {
 	return ""
 }
 
+// ExplainHostSeries explains how the series of a linux host was
+// determined, so it isn't supported on other platforms.
+func ExplainHostSeries() (HostClassification, error) {
+	return HostClassification{}, errors.NotSupportedf("explaining the series of %s hosts", runtime.GOOS)
+}
+
 func updateLocalSeriesVersions() error {
 	return nil
 }
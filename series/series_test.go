@@ -104,3 +104,40 @@ func (*kernelVersionSuite) TestMacOSXSeries(c *gc.C) {
 		c.Check(series, gc.Equals, test.series)
 	}
 }
+
+func (*kernelVersionSuite) TestBSDSeriesFromUname(c *gc.C) {
+	for _, test := range []struct {
+		sysname string
+		release string
+		series  string
+	}{
+		{"OpenBSD", "7.4", "openbsd7.4"},
+		{"OpenBSD", "7.5-current", "openbsd7.5"},
+		{"NetBSD", "10.0", "netbsd10"},
+		{"NetBSD", "9.3_STABLE", "netbsd9"},
+	} {
+		got, err := series.BSDSeriesFromUname(test.sysname, test.release)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(got, gc.Equals, test.series)
+	}
+
+	_, err := series.BSDSeriesFromUname("OpenBSD", "7")
+	c.Assert(err, gc.ErrorMatches, `OpenBSD release "7" not valid`)
+	_, err = series.BSDSeriesFromUname("FreeBSD", "14.0")
+	c.Assert(err, gc.ErrorMatches, `operating system "FreeBSD" not supported`)
+}
+
+func (*kernelVersionSuite) TestAIXSeriesFromUname(c *gc.C) {
+	got, err := series.AIXSeriesFromUname("AIX", "7", "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, gc.Equals, "aix7.3")
+
+	got, err = series.AIXSeriesFromUname("OS400", "7", "5")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, gc.Equals, "ibmi7.5")
+
+	_, err = series.AIXSeriesFromUname("AIX", "7", "TL1")
+	c.Assert(err, gc.ErrorMatches, `AIX version "7" release "TL1" not valid`)
+	_, err = series.AIXSeriesFromUname("HP-UX", "11", "31")
+	c.Assert(err, gc.ErrorMatches, `operating system "HP-UX" not supported`)
+}
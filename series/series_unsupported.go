@@ -0,0 +1,21 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !linux,!windows,!darwin,!openbsd,!netbsd,!aix
+
+package series
+
+import (
+	"runtime"
+
+	"github.com/juju/errors"
+)
+
+// readSeries reports that the series can't be detected on platforms,
+// such as freebsd, plan9 and js, that no series is defined for.
+func readSeries() (string, error) {
+	return "unknown", errors.NotSupportedf("series detection on %s", runtime.GOOS)
+}
+
+// platformCapabilities holds the capabilities of this platform.
+var platformCapabilities = PlatformCapabilities{}
@@ -0,0 +1,24 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// +build !linux,!windows,!darwin,!openbsd,!netbsd,!aix
+
+package series_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type unsupportedSuite struct{}
+
+var _ = gc.Suite(&unsupportedSuite{})
+
+func (*unsupportedSuite) TestReadSeries(c *gc.C) {
+	got, err := series.ReadSeries()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(got, gc.Equals, "unknown")
+}
@@ -6,7 +6,7 @@ package series
 
 import (
 	"os"
-	"strings"
+	"strconv"
 
 	"github.com/juju/errors"
 	"golang.org/x/sys/windows/registry"
@@ -36,27 +36,55 @@ func getVersionFromRegistry() (string, error) {
 	return s, nil
 }
 
+func getBuildFromRegistry() (windowsBuild, error) {
+	var build windowsBuild
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, currentVersionKey, registry.QUERY_VALUE)
+	if err != nil {
+		return build, errors.Trace(err)
+	}
+	defer k.Close()
+	number, _, err := k.GetStringValue("CurrentBuildNumber")
+	if err != nil {
+		return build, errors.Trace(err)
+	}
+	if build.Number, err = strconv.Atoi(number); err != nil {
+		return build, errors.Trace(err)
+	}
+	installationType, _, err := k.GetStringValue("InstallationType")
+	if err != nil && err != registry.ErrNotExist {
+		return build, errors.Trace(err)
+	}
+	editionID, _, err := k.GetStringValue("EditionID")
+	if err != nil && err != registry.ErrNotExist {
+		return build, errors.Trace(err)
+	}
+	build.Server = installationType != "" && installationType != "Client"
+	build.HyperV = editionID == "ServerHyperCore"
+	return build, nil
+}
+
 func readSeries() (string, error) {
 	ver, err := getVersionFromRegistry()
 	if err != nil {
 		return "unknown", errors.Trace(err)
 	}
 
-	var lookAt = windowsVersions
-
 	isNano, err := isWindowsNano()
 	if err != nil && os.IsNotExist(err) {
 		return "unknown", errors.Trace(err)
 	}
-	if isNano {
-		lookAt = windowsNanoVersions
+	if series, ok := windowsSeriesFromProductName(ver, isNano); ok {
+		return series, nil
 	}
 
-	for _, value := range windowsVersionMatchOrder {
-		if strings.HasPrefix(ver, value) {
-			if val, ok := lookAt[value]; ok {
-				return val, nil
-			}
+	// The product name can be localized in ways we don't recognise, so
+	// fall back to the build number, which isn't.
+	if !isNano {
+		build, err := getBuildFromRegistry()
+		if err != nil {
+			logger.Debugf("cannot read windows build: %v", err)
+		} else if series, ok := windowsSeriesFromBuild(build); ok {
+			return series, nil
 		}
 	}
 	return "unknown", errors.Errorf("unknown series %q", ver)
@@ -75,3 +103,9 @@ func isWindowsNano() (bool, error) {
 	}
 	return s == 1, nil
 }
+
+// platformCapabilities holds the capabilities of this platform.
+var platformCapabilities = PlatformCapabilities{
+	SeriesDetection: true,
+	RegistryAccess:  true,
+}
@@ -186,3 +186,18 @@ func RandomPassword() (string, error) {
 	}
 	return base64.StdEncoding.EncodeToString(b), nil
 }
+
+func (s *windowsSeriesSuite) TestDetectWine(c *gc.C) {
+	salt, err := RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(series.WineKey, fmt.Sprintf(`SOFTWARE\JUJU\%s`, salt))
+	c.Assert(series.DetectWine(), jc.IsFalse)
+
+	k, _, err := registry.CreateKey(registry.LOCAL_MACHINE, *series.WineKey, registry.ALL_ACCESS)
+	c.Assert(err, jc.ErrorIsNil)
+	err = k.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	defer registry.DeleteKey(registry.LOCAL_MACHINE, *series.WineKey)
+
+	c.Assert(series.DetectWine(), jc.IsTrue)
+}
@@ -0,0 +1,259 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// Series describes a known series.
+type Series struct {
+	Name Name
+	OS   os.OSType
+	// Version is the version of the series, without any " LTS" suffix.
+	Version string
+	LTS     bool
+	// CodeName is the full codename of an ubuntu series, for example
+	// "Focal Fossa". It is empty for other operating systems.
+	CodeName string
+	// Created is when development of the series started, as recorded in
+	// distro-info, if it is known.
+	Created time.Time
+	// Released and EOL are the release and end of life dates of the
+	// series, if they are known.
+	Released time.Time
+	EOL      time.Time
+	// Supported is whether Juju classifies the series as supported,
+	// which applies when its release and end of life dates aren't known,
+	// or when it's overridden with Tx.SetSupported.
+	Supported bool
+	// Resources holds the minimum resources recommended for the series,
+	// if there is a recommendation.
+	Resources *Resources
+	// Tags holds the tags attached to the series, sorted.
+	Tags []string
+
+	// supportOverridden is true if Supported takes precedence over the
+	// release and end of life dates.
+	supportOverridden bool
+}
+
+// DisplayName returns the name of the series for presenting to users, for
+// example "Ubuntu 20.04 LTS (Focal Fossa)" or "Windows Server 2019".
+func (s Series) DisplayName() string {
+	switch s.OS {
+	case os.Ubuntu:
+		name := "Ubuntu " + s.Version
+		if s.LTS {
+			name += " LTS"
+		}
+		if s.CodeName != "" {
+			name += " (" + s.CodeName + ")"
+		}
+		return name
+	case os.Windows:
+		if product, ok := msrcProducts[string(s.Name)]; ok {
+			return product
+		}
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, s.OS)
+}
+
+// GetSeries returns the description of a known series. The series may be
+// given in any of the forms accepted by CanonicalSeries, including by the
+// codename of an ubuntu series.
+func GetSeries(series string) (Series, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return Series{}, errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	record, ok := knownSeries()[string(name)]
+	if !ok {
+		return Series{}, errors.Trace(unknownSeries(series, knownSeries()))
+	}
+	return describeSeries(name, record), nil
+}
+
+// SupportedJujuSeriesInfo returns the descriptions of the series returned
+// by SupportedJujuSeries, in the same order, so that they needn't be
+// looked up one by one. The descriptions are consistent with the list,
+// even if the series data is updated meanwhile.
+func SupportedJujuSeriesInfo() []Series {
+	supported, described := describeSupportedSeries()
+	names := defaultRegistry.interceptList(LookupSupportedJujuWorkloadSeries, supported)
+	result := make([]Series, 0, len(names))
+	for _, name := range names {
+		info, ok := described[name]
+		if !ok {
+			// The series was rewritten by an interceptor.
+			var err error
+			if info, err = GetSeries(name); err != nil {
+				logger.Debugf("leaving %q out of supported series: %v", name, err)
+				continue
+			}
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+// describeSupportedSeries returns the supported workload series, before
+// they are passed to the interceptors, with their descriptions.
+func describeSupportedSeries() ([]string, map[string]Series) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	known := knownSeries()
+	var supported []string
+	described := make(map[string]Series)
+	for _, name := range supportedSeriesListsLocked().workload {
+		if record, ok := known[name]; ok {
+			supported = append(supported, name)
+			described[name] = describeSeries(Name(name), record)
+		}
+	}
+	return supported, described
+}
+
+// describeSeries returns the description of the series with the record.
+// The caller must hold seriesVersionsMutex.
+func describeSeries(name Name, record seriesRecord) Series {
+	result := Series{
+		Name:      name,
+		OS:        record.OS,
+		Version:   strings.TrimSuffix(record.Version, " LTS"),
+		LTS:       record.LTS,
+		Created:   record.Created,
+		Released:  record.Released,
+		EOL:       record.EOL,
+		Supported: record.Supported,
+
+		supportOverridden: record.SupportOverridden,
+	}
+	if record.OS == os.Ubuntu {
+		result.CodeName = ubuntuCodeName(string(name), record.seriesVersion)
+	}
+	if resources, ok := seriesResources[string(name)]; ok {
+		result.Resources = &resources
+	}
+	result.Tags = seriesTags[string(name)].SortedValues()
+	return result
+}
+
+// ubuntuCodeNames holds the full codenames of the ubuntu series, for use
+// when distro-info isn't available.
+var ubuntuCodeNames = map[string]string{
+	"precise": "Precise Pangolin",
+	"quantal": "Quantal Quetzal",
+	"raring":  "Raring Ringtail",
+	"saucy":   "Saucy Salamander",
+	"trusty":  "Trusty Tahr",
+	"utopic":  "Utopic Unicorn",
+	"vivid":   "Vivid Vervet",
+	"wily":    "Wily Werewolf",
+	"xenial":  "Xenial Xerus",
+	"yakkety": "Yakkety Yak",
+	"zesty":   "Zesty Zapus",
+	"artful":  "Artful Aardvark",
+	"bionic":  "Bionic Beaver",
+	"cosmic":  "Cosmic Cuttlefish",
+	"disco":   "Disco Dingo",
+	"eoan":    "Eoan Ermine",
+	"focal":   "Focal Fossa",
+	"groovy":  "Groovy Gorilla",
+	"hirsute": "Hirsute Hippo",
+	"jammy":   "Jammy Jellyfish",
+	"noble":   "Noble Numbat",
+}
+
+// ubuntuCodeName returns the full codename of the ubuntu series, preferring
+// the one from distro-info. The caller must hold seriesVersionsMutex.
+func ubuntuCodeName(series string, version seriesVersion) string {
+	if version.CodeName != "" {
+		return version.CodeName
+	}
+	return ubuntuCodeNames[series]
+}
+
+// seriesFromCodeName returns the ubuntu series whose full codename, or
+// either word of it, is codeName, ignoring case. For example "Focal
+// Fossa", "focal" and "fossa" all give "focal". The caller must hold
+// seriesVersionsMutex.
+func seriesFromCodeName(codeName string) (string, bool) {
+	codeName = strings.Join(strings.Fields(strings.ToLower(codeName)), " ")
+	if codeName == "" {
+		return "", false
+	}
+	for name, version := range ubuntuSeries {
+		full := strings.ToLower(ubuntuCodeName(name, version))
+		if full == "" {
+			continue
+		}
+		if full == codeName {
+			return name, true
+		}
+		for _, word := range strings.Fields(full) {
+			if word == codeName {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// DistroInfoSupported reports whether the series is supported by its vendor
+// today according to distro-info, judged only by the release and end of
+// life dates recorded there. Unlike the SupportedJuju* functions, no Juju
+// policy is applied, so together they distinguish series supported by
+// Ubuntu from series supported by Juju. A NotFound error is returned if
+// distro-info has no dates for the series.
+func DistroInfoSupported(series string) (bool, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	record := knownSeries()[string(name)]
+	if record.Released.IsZero() || record.EOL.IsZero() {
+		return false, errors.NotFoundf("distro-info dates for series %q", series)
+	}
+	today := defaultRegistry.today()
+	return today.After(record.Released) && today.Before(record.EOL), nil
+}
+
+// IsInDevelopmentWindow reports whether the series is under development at
+// t, that is, whether t is on or after the date distro-info records its
+// development as starting, but before its release. Image builds use this
+// to start building dailies ahead of release day. A NotFound error is
+// returned if distro-info has no created date for the series.
+func IsInDevelopmentWindow(series string, t time.Time) (bool, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	record := knownSeries()[string(name)]
+	if record.Created.IsZero() {
+		return false, errors.NotFoundf("distro-info created date for series %q", series)
+	}
+	if t.Before(record.Created) {
+		return false, nil
+	}
+	return record.Released.IsZero() || t.Before(record.Released), nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type seriesInfoSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&seriesInfoSuite{})
+
+func (s *seriesInfoSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *seriesInfoSuite) TestGetSeries(c *gc.C) {
+	info, err := series.GetSeries("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Name, gc.Equals, series.Name("focal"))
+	c.Assert(info.OS, gc.Equals, os.Ubuntu)
+	c.Assert(info.Version, gc.Equals, "20.04")
+	c.Assert(info.LTS, jc.IsTrue)
+	c.Assert(info.CodeName, gc.Equals, "Focal Fossa")
+	c.Assert(info.DisplayName(), gc.Equals, "Ubuntu 20.04 LTS (Focal Fossa)")
+}
+
+func (s *seriesInfoSuite) TestSupportedJujuSeriesInfo(c *gc.C) {
+	infos := series.SupportedJujuSeriesInfo()
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = string(info.Name)
+		expected, err := series.GetSeries(names[i])
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(info, jc.DeepEquals, expected)
+	}
+	c.Check(names, jc.DeepEquals, series.SupportedJujuSeries())
+}
+
+func (s *seriesInfoSuite) TestGetSeriesByCodeName(c *gc.C) {
+	for _, input := range []string{"Focal Fossa", "fossa", " FOCAL ", "focal  fossa"} {
+		info, err := series.GetSeries(input)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(info.Name, gc.Equals, series.Name("focal"), gc.Commentf("input %q", input))
+	}
+	name, err := series.CanonicalSeries("Beaver")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, series.Name("bionic"))
+
+	_, err = series.GetSeries("Fossa Focal")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *seriesInfoSuite) TestDisplayName(c *gc.C) {
+	info, err := series.GetSeries("win2019")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.CodeName, gc.Equals, "")
+	c.Assert(info.DisplayName(), gc.Equals, "Windows Server 2019")
+
+	info, err = series.GetSeries("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.DisplayName(), gc.Equals, "centos7 (CentOS)")
+}
+
+func (s *seriesInfoSuite) TestIsInDevelopmentWindow(c *gc.C) {
+	var buf bytes.Buffer
+	err := series.WriteBundle(&buf, series.Bundle{Files: []series.BundleFile{{
+		Kind: series.BundleDistroInfo,
+		Name: "ubuntu",
+		Data: []byte("version,codename,series,created,release,eol\n" +
+			"12.04 LTS,Precise Pangolin,precise,2011-10-13,2012-04-26,2017-04-26\n" +
+			"98.04 LTS,Picard Penguin,picard,2097-10-20,2098-04-20,2103-04-30\n"),
+	}}}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.LoadBundle(&buf, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	picard, err := series.GetSeries("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(picard.Created, gc.Equals, time.Date(2097, 10, 20, 0, 0, 0, 0, time.UTC))
+
+	for _, test := range []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2097, 10, 19, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2097, 10, 20, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2098, 4, 19, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2098, 4, 20, 0, 0, 0, 0, time.UTC), false},
+	} {
+		c.Logf("at %v", test.at)
+		got, err := series.IsInDevelopmentWindow("picard", test.at)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(got, gc.Equals, test.want)
+	}
+
+	_, err = series.IsInDevelopmentWindow("centos7", time.Now())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = series.IsInDevelopmentWindow("spock", time.Now())
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package seriesmetrics exposes the series data metrics as prometheus
+// metrics, so that operators can alert on stale series data. It is a
+// module of its own, so that only its users depend on the prometheus
+// client.
+package seriesmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/os/series"
+)
+
+const namespace = "juju_series"
+
+var (
+	refreshAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "data", "refresh_age_seconds"),
+		"Seconds since the series data was last refreshed, or since the embedded data was produced if it never was.",
+		nil, nil,
+	)
+	dataVersionDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "data", "version_info"),
+		"The source and version of the series data in use, always 1.",
+		[]string{"source", "version"}, nil,
+	)
+	unknownLookupsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "unknown_lookups_total"),
+		"Lookups of series that aren't known.",
+		nil, nil,
+	)
+	cacheHitsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "hits_total"),
+		"Uses of the cached series lists and snapshot.",
+		nil, nil,
+	)
+	cacheMissesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "misses_total"),
+		"Rebuilds of the cached series lists and snapshot.",
+		nil, nil,
+	)
+)
+
+// Collector is a prometheus.Collector reporting the series data metrics.
+type Collector struct{}
+
+// NewCollector returns a Collector reporting the series data metrics.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (*Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- refreshAgeDesc
+	ch <- dataVersionDesc
+	ch <- unknownLookupsDesc
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (*Collector) Collect(ch chan<- prometheus.Metric) {
+	metrics := series.CurrentMetrics()
+	refreshed := metrics.LastRefresh
+	if refreshed.IsZero() {
+		refreshed = metrics.DataVersion.Timestamp
+	}
+	if !refreshed.IsZero() {
+		age := series.DefaultRegistry().Now().Sub(refreshed).Seconds()
+		ch <- prometheus.MustNewConstMetric(refreshAgeDesc, prometheus.GaugeValue, age)
+	}
+	ch <- prometheus.MustNewConstMetric(dataVersionDesc, prometheus.GaugeValue, 1,
+		metrics.DataVersion.Source, metrics.DataVersion.Version)
+	ch <- prometheus.MustNewConstMetric(unknownLookupsDesc, prometheus.CounterValue, float64(metrics.UnknownLookups))
+	ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(metrics.CacheHits))
+	ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(metrics.CacheMisses))
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package seriesmetrics_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+	"github.com/juju/os/series/seriesmetrics"
+)
+
+type collectorSuite struct{}
+
+var _ = gc.Suite(&collectorSuite{})
+
+func (s *collectorSuite) TearDownTest(c *gc.C) {
+	series.DefaultRegistry().SetClock(nil)
+}
+
+func (s *collectorSuite) TestCollect(c *gc.C) {
+	refreshed := time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)
+	series.DefaultRegistry().SetClock(func() time.Time { return refreshed })
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "version": "2031.01", "series": []}`))
+	c.Assert(err, jc.ErrorIsNil)
+	series.DefaultRegistry().SetClock(func() time.Time { return refreshed.Add(time.Hour) })
+	_, err = series.GetOSFromSeries("sulu")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+
+	registry := prometheus.NewPedanticRegistry()
+	err = registry.Register(seriesmetrics.NewCollector())
+	c.Assert(err, jc.ErrorIsNil)
+	families, err := registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch {
+			case metric.GetGauge() != nil:
+				values[family.GetName()] = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				values[family.GetName()] = metric.GetCounter().GetValue()
+			}
+			for _, label := range metric.GetLabel() {
+				values[family.GetName()+"/"+label.GetName()+"="+label.GetValue()] = 1
+			}
+		}
+	}
+	c.Check(values["juju_series_data_refresh_age_seconds"], gc.Equals, float64(3600))
+	c.Check(values["juju_series_data_version_info/version=2031.01"], gc.Equals, float64(1))
+	c.Check(values["juju_series_data_version_info/source=definitions"], gc.Equals, float64(1))
+	c.Check(values["juju_series_unknown_lookups_total"] >= 1, jc.IsTrue)
+	_, ok := values["juju_series_cache_hits_total"]
+	c.Check(ok, jc.IsTrue)
+	_, ok = values["juju_series_cache_misses_total"]
+	c.Check(ok, jc.IsTrue)
+}
@@ -0,0 +1,12 @@
+module github.com/juju/os/series/seriesmetrics
+
+go 1.14
+
+require (
+	github.com/juju/os v0.0.0-00010101000000-000000000000
+	github.com/juju/testing v0.0.0-20180402130637-44801989f0f7
+	github.com/prometheus/client_golang v1.7.1
+	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15
+)
+
+replace github.com/juju/os => ../..
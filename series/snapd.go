@@ -0,0 +1,41 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// SnapdTag is the series tag marking series other than ubuntu on which
+// snapd is available, such as a registered series of a derivative.
+const SnapdTag = "snapd"
+
+// minSnapdUbuntuVersion is the first ubuntu release with snapd installed
+// by default.
+var minSnapdUbuntuVersion = []int{16, 4}
+
+// SnapdSupported reports whether snapd is available on a known series,
+// so that agents can be installed as snaps. It is available on ubuntu
+// from xenial onwards, and on other series tagged with SnapdTag. Hosts
+// can be probed with GetHostInfo instead.
+func SnapdSupported(series string) (bool, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	if seriesTags[string(name)].Contains(SnapdTag) {
+		return true, nil
+	}
+	version, ok := ubuntuSeries[string(name)]
+	if !ok {
+		return false, nil
+	}
+	parsed, ok := parseConstraintVersion(strings.TrimSuffix(version.Version, " LTS"))
+	return ok && compareVersions(parsed, minSnapdUbuntuVersion) >= 0, nil
+}
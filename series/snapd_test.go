@@ -0,0 +1,55 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type snapdSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&snapdSuite{})
+
+func (s *snapdSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *snapdSuite) TestSnapdSupported(c *gc.C) {
+	for _, test := range []struct {
+		series    string
+		supported bool
+	}{
+		{"xenial", true},
+		{"focal", true},
+		{"trusty", false},
+		{"centos8", false},
+		{"win2016nano", false},
+	} {
+		c.Logf("series %q", test.series)
+		supported, err := series.SnapdSupported(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(supported, gc.Equals, test.supported)
+	}
+}
+
+func (s *snapdSuite) TestSnapdSupportedByTag(c *gc.C) {
+	err := series.AddSeriesTags("centos8", series.SnapdTag)
+	c.Assert(err, jc.ErrorIsNil)
+	supported, err := series.SnapdSupported("centos8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsTrue)
+}
+
+func (s *snapdSuite) TestSnapdSupportedUnknownSeries(c *gc.C) {
+	_, err := series.SnapdSupported("sulu")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
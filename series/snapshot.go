@@ -0,0 +1,159 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// Snapshot is a read-only view of the known series, taken at a single point
+// in time. Unlike the package level functions, every query made against a
+// Snapshot gives answers consistent with each other, even if the series data
+// is updated in the meantime. A Snapshot is cheap to copy and safe to share
+// between goroutines. The zero Snapshot knows no series.
+type Snapshot struct {
+	data *snapshotData
+}
+
+// snapshotData holds the state captured by a Snapshot, which is never
+// modified once built.
+type snapshotData struct {
+	series         map[string]seriesRecord
+	seriesVersions map[string]string
+	versionSeries  map[string][]string
+	supported      *supportedSeriesLists
+	dataVersion    DataVersionInfo
+}
+
+// currentSnapshot caches the snapshot of the current series data. It is
+// discarded by updateVersionSeries, and when the supported series lists
+// change as series are released or reach their end of life.
+var currentSnapshot *snapshotData
+
+// CurrentSnapshot returns a Snapshot of the currently known series.
+func CurrentSnapshot() Snapshot {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+
+	cached := currentSnapshot != nil && currentSnapshot.supported.validAt(defaultRegistry.today())
+	countCache(cached)
+	if !cached {
+		data := &snapshotData{
+			series:         knownSeries(),
+			seriesVersions: make(map[string]string, len(seriesVersions)+len(genericLinuxProfileVersions)),
+			versionSeries:  make(map[string][]string, len(versionSeries)),
+			supported:      supportedSeriesListsLocked(),
+			dataVersion:    dataVersion,
+		}
+		for name, version := range seriesVersions {
+			data.seriesVersions[name] = version
+		}
+		for name, version := range genericLinuxProfileVersions {
+			data.seriesVersions[name] = version
+		}
+		for version, names := range versionSeries {
+			data.versionSeries[version] = names
+		}
+		currentSnapshot = data
+	}
+	return Snapshot{data: currentSnapshot}
+}
+
+func (s Snapshot) get() *snapshotData {
+	if s.data == nil {
+		return &snapshotData{supported: &supportedSeriesLists{}}
+	}
+	return s.data
+}
+
+// Series returns the names of all the series in the snapshot, sorted.
+func (s Snapshot) Series() []Name {
+	known := s.get().series
+	names := make([]Name, 0, len(known))
+	for name := range known {
+		names = append(names, Name(name))
+	}
+	sortNames(names)
+	return names
+}
+
+// GetOSFromSeries returns the operating system of the series, as
+// GetOSFromSeries does.
+func (s Snapshot) GetOSFromSeries(series string) (os.OSType, error) {
+	name := FormatSeries(series)
+	if name == "" {
+		return os.Unknown, errors.Trace(EmptyInputError{Input: "series"})
+	}
+	if record, ok := s.get().series[name]; ok && record.OS != os.Unknown {
+		return record.OS, nil
+	}
+	return os.Unknown, errors.Trace(unknownSeries(series, s.get().series))
+}
+
+// SeriesVersion returns the version of the series, as SeriesVersion does.
+func (s Snapshot) SeriesVersion(series string) (string, error) {
+	name := FormatSeries(series)
+	if name == "" {
+		return "", errors.Trace(EmptyInputError{Input: "series"})
+	}
+	if version, ok := s.get().seriesVersions[name]; ok {
+		return version, nil
+	}
+	return "", errors.Trace(unknownSeriesVersionError(series))
+}
+
+// VersionSeries returns the series for the version, as VersionSeries does.
+func (s Snapshot) VersionSeries(version string) (string, error) {
+	all, err := s.VersionSeriesAll(version)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(all) > 1 {
+		return "", errors.Trace(AmbiguousVersionError{
+			Version: strings.TrimSpace(version),
+			Series:  all,
+		})
+	}
+	return all[0], nil
+}
+
+// VersionSeriesAll returns all the series with the version, as
+// VersionSeriesAll does.
+func (s Snapshot) VersionSeriesAll(version string) ([]string, error) {
+	trimmed := strings.TrimSpace(version)
+	if trimmed == "" {
+		return nil, errors.Trace(EmptyInputError{Input: "version"})
+	}
+	if all, ok := s.get().versionSeries[trimmed]; ok {
+		return copyStrings(all), nil
+	}
+	return nil, errors.Trace(unknownVersionSeriesError(version))
+}
+
+// SupportedJujuControllerSeries returns the series that Juju supports for
+// controllers, as SupportedJujuControllerSeries does.
+func (s Snapshot) SupportedJujuControllerSeries() []string {
+	return copyStrings(s.get().supported.controller)
+}
+
+// SupportedJujuWorkloadSeries returns the series that Juju supports for
+// workloads, as SupportedJujuWorkloadSeries does.
+func (s Snapshot) SupportedJujuWorkloadSeries() []string {
+	return copyStrings(s.get().supported.workload)
+}
+
+// ESMSupportedJujuSeries returns the ubuntu series with extended security
+// maintenance, as ESMSupportedJujuSeries does.
+func (s Snapshot) ESMSupportedJujuSeries() []string {
+	return copyStrings(s.get().supported.esm)
+}
+
+// DataVersion returns the provenance of the series data in the snapshot.
+func (s Snapshot) DataVersion() DataVersionInfo {
+	return s.get().dataVersion
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type snapshotSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+func (s *snapshotSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	cleanup := series.SetSeriesVersions(make(map[string]string))
+	s.AddCleanup(func(*gc.C) { cleanup() })
+}
+
+func (s *snapshotSuite) TestSnapshotIsConsistent(c *gc.C) {
+	snapshot := series.CurrentSnapshot()
+
+	err := series.RegisterSeries(series.Definition{
+		Series:    "picard",
+		OS:        "ubuntu",
+		Version:   "99.04",
+		Supported: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The earlier snapshot doesn't see the new series.
+	_, err = snapshot.GetOSFromSeries("picard")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+	_, err = snapshot.SeriesVersion("picard")
+	c.Assert(err, jc.Satisfies, series.IsUnknownSeriesVersionError)
+	c.Assert(set.NewStrings(snapshot.SupportedJujuWorkloadSeries()...).Contains("picard"), jc.IsFalse)
+
+	// A new one does.
+	snapshot = series.CurrentSnapshot()
+	osType, err := snapshot.GetOSFromSeries("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.Ubuntu)
+	version, err := snapshot.SeriesVersion("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "99.04")
+	name, err := snapshot.VersionSeries("99.04")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "picard")
+	c.Assert(set.NewStrings(snapshot.SupportedJujuWorkloadSeries()...).Contains("picard"), jc.IsTrue)
+	var found bool
+	for _, name := range snapshot.Series() {
+		found = found || name == "picard"
+	}
+	c.Assert(found, jc.IsTrue)
+}
+
+func (s *snapshotSuite) TestSnapshotMatchesPackageFunctions(c *gc.C) {
+	snapshot := series.CurrentSnapshot()
+	c.Assert(snapshot.SupportedJujuControllerSeries(), jc.DeepEquals, series.SupportedJujuControllerSeries())
+	c.Assert(snapshot.SupportedJujuWorkloadSeries(), jc.DeepEquals, series.SupportedJujuWorkloadSeries())
+	c.Assert(snapshot.DataVersion(), jc.DeepEquals, series.DataVersion())
+	osType, err := snapshot.GetOSFromSeries("win2019")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.Windows)
+}
+
+func (s *snapshotSuite) TestZeroSnapshot(c *gc.C) {
+	var snapshot series.Snapshot
+	c.Assert(snapshot.Series(), gc.HasLen, 0)
+	c.Assert(snapshot.SupportedJujuWorkloadSeries(), gc.HasLen, 0)
+	_, err := snapshot.GetOSFromSeries("bionic")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *snapshotSuite) TestDiff(c *gc.C) {
+	err := series.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.RegisterSeries(series.Definition{Series: "riker", OS: "ubuntu", Version: "97.10"})
+	c.Assert(err, jc.ErrorIsNil)
+	old := series.CurrentSnapshot()
+
+	err = series.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04", LTS: true, Supported: true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.RegisterSeries(series.Definition{Series: "troi", OS: "ubuntu", Version: "98.04"})
+	c.Assert(err, jc.ErrorIsNil)
+	changes := series.Diff(old, series.CurrentSnapshot())
+	c.Assert(changes, jc.DeepEquals, series.ChangeSet{
+		Added:   []series.Name{"troi"},
+		Updated: []series.Name{"picard"},
+		Fields: []series.FieldChange{
+			{Series: "picard", Field: "LTS", Old: "false", New: "true"},
+			{Series: "picard", Field: "Supported", Old: "false", New: "true"},
+		},
+	})
+
+	c.Assert(series.Diff(old, old).Empty(), jc.IsTrue)
+	removed := series.Diff(old, series.Snapshot{})
+	c.Assert(removed.Removed, gc.HasLen, len(old.Series()))
+}
+
+func (s *snapshotSuite) TestSupportedSeriesFollowClock(c *gc.C) {
+	// The supported series are recomputed as series are released and
+	// reach their end of life, without any change to the series data.
+	now := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	series.DefaultRegistry().SetClock(func() time.Time { return now })
+
+	workload := set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+	c.Check(workload.Contains("fedora39"), jc.IsTrue)
+	c.Check(workload.Contains("fedora41"), jc.IsFalse)
+
+	// fedora41 was released on 2024-10-29, and fedora39 reached its end
+	// of life on 2024-11-26.
+	now = time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	workload = set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+	c.Check(workload.Contains("fedora39"), jc.IsFalse)
+	c.Check(workload.Contains("fedora41"), jc.IsTrue)
+
+	matrix := make(map[series.Name]bool)
+	for _, entry := range series.CurrentSnapshot().SupportMatrix() {
+		matrix[entry.Series] = entry.Workload
+	}
+	c.Check(matrix["fedora39"], jc.IsFalse)
+	c.Check(matrix["fedora41"], jc.IsTrue)
+}
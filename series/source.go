@@ -0,0 +1,83 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+)
+
+// Source identifies where the data for a series came from. Sources are
+// ordered by precedence: data from a source never overwrites data from a
+// source that sorts after it.
+type Source int
+
+const (
+	// SourceEmbedded is the series data compiled into this package.
+	SourceEmbedded Source = iota
+	// SourceDistroInfo is the local distro-info CSV file.
+	SourceDistroInfo
+	// SourceDefinitions is a series definitions file, either loaded
+	// locally or fetched from a remote source.
+	SourceDefinitions
+	// SourceRuntime is a series registered by the application at runtime.
+	SourceRuntime
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceEmbedded:
+		return "embedded"
+	case SourceDistroInfo:
+		return "distro-info"
+	case SourceDefinitions:
+		return "definitions"
+	case SourceRuntime:
+		return "runtime"
+	}
+	return "unknown"
+}
+
+// seriesSources records the source of every series that didn't come from
+// the embedded data.
+var seriesSources = map[string]Source{}
+
+// canOverwrite reports whether data for the series from source may replace
+// the data that's already known. The caller must hold seriesVersionsMutex.
+func canOverwrite(series string, source Source) bool {
+	return seriesSources[series] <= source
+}
+
+// SeriesSource returns the source that provided the data in use for the
+// series.
+func SeriesSource(series string) (Source, error) {
+	name := FormatSeries(series)
+	if name == "" {
+		return SourceEmbedded, errors.Trace(EmptyInputError{Input: "series"})
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+
+	updateSeriesVersionsOnce()
+	if _, err := getOSFromSeries(name); err != nil {
+		return SourceEmbedded, errors.Trace(unknownSeries(series, knownSeries()))
+	}
+	return seriesSources[name], nil
+}
+
+// RegisterSeries adds or replaces a series at runtime. Registered series
+// take precedence over all other sources, so later definitions files or
+// distro-info updates won't alter them.
+func RegisterSeries(def Definition) error {
+	if problems := validateDefinition(0, def); len(problems) > 0 {
+		return errors.Trace(problems)
+	}
+
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+
+	updateSeriesVersionsOnce()
+	defer publishChanges(knownSeries())
+	return errors.Trace(applyDefinitions([]Definition{def}, SourceRuntime))
+}
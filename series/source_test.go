@@ -0,0 +1,83 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type sourceSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&sourceSuite{})
+
+func (s *sourceSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	cleanup := series.SetSeriesVersions(map[string]string{"focal": "20.04"})
+	s.AddCleanup(func(*gc.C) { cleanup() })
+}
+
+func (s *sourceSuite) TestSourceString(c *gc.C) {
+	c.Check(series.SourceEmbedded.String(), gc.Equals, "embedded")
+	c.Check(series.SourceDistroInfo.String(), gc.Equals, "distro-info")
+	c.Check(series.SourceDefinitions.String(), gc.Equals, "definitions")
+	c.Check(series.SourceRuntime.String(), gc.Equals, "runtime")
+}
+
+func (s *sourceSuite) TestSeriesSourceEmbedded(c *gc.C) {
+	source, err := series.SeriesSource("win2019")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(source, gc.Equals, series.SourceEmbedded)
+
+	_, err = series.SeriesSource("firewolf")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *sourceSuite) TestRuntimeRegistrationWins(c *gc.C) {
+	err := series.RegisterSeries(series.Definition{
+		Series:  "spock",
+		OS:      "ubuntu",
+		Version: "99.10",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = series.LoadDefinitions(strings.NewReader(definitionsData))
+	c.Assert(err, jc.ErrorIsNil)
+
+	version, err := series.SeriesVersion("spock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "99.10")
+	source, err := series.SeriesSource("spock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(source, gc.Equals, series.SourceRuntime)
+
+	source, err = series.SeriesSource("centos9")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(source, gc.Equals, series.SourceDefinitions)
+}
+
+func (s *sourceSuite) TestRuntimeRegistrationOverridesDefinitions(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(definitionsData))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = series.RegisterSeries(series.Definition{
+		Series:  "centos9",
+		OS:      "centos",
+		Version: "centos9-stream",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	version, err := series.SeriesVersion("centos9")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "centos9-stream")
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// DefaultStalenessThreshold is the age beyond which CheckStaleness
+// considers series data too old to base support decisions on, when no
+// other threshold is given.
+const DefaultStalenessThreshold = 365 * 24 * time.Hour
+
+// DataStaleness describes how old the active series data is.
+type DataStaleness struct {
+	// DataVersion describes the active series data.
+	DataVersion DataVersionInfo
+	// Since is when the active data was last known to be current: the
+	// build date of the embedded data, the modification time of a
+	// distro-info file or the timestamp of a definitions file, or when
+	// remote data was fetched. It is zero if that isn't known.
+	Since time.Time
+	// Age is the time elapsed since then, according to the registry clock.
+	// It is zero if Since is.
+	Age time.Duration
+}
+
+// Staleness returns how old the active series data is, so applications can
+// refuse to make support decisions on data that is out of date.
+func Staleness() DataStaleness {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+
+	result := DataStaleness{DataVersion: dataVersion}
+	if isRemoteSource(dataVersion.Source) {
+		// Remote data is as current as the server had it when fetched,
+		// however long ago it was produced.
+		result.Since = lastRefresh
+	} else {
+		result.Since = dataVersion.Timestamp
+	}
+	if !result.Since.IsZero() {
+		result.Age = defaultRegistry.today().Sub(result.Since)
+	}
+	return result
+}
+
+// isRemoteSource returns true if a DataVersionInfo source is the URL of
+// remote data.
+func isRemoteSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// staleDataError is returned by CheckStaleness when the series data is
+// older than allowed.
+type staleDataError struct {
+	staleness DataStaleness
+	threshold time.Duration
+}
+
+func (e staleDataError) Error() string {
+	if e.staleness.Since.IsZero() {
+		return fmt.Sprintf("series data from %s has unknown age", e.staleness.DataVersion.Source)
+	}
+	return fmt.Sprintf("series data from %s is %s old, more than %s",
+		e.staleness.DataVersion.Source,
+		e.staleness.Age.Truncate(time.Hour),
+		e.threshold,
+	)
+}
+
+// IsStaleDataError returns true if err is returned by CheckStaleness for
+// data that is too old.
+func IsStaleDataError(err error) bool {
+	_, ok := errors.Cause(err).(staleDataError)
+	return ok
+}
+
+// CheckStaleness returns an error satisfying IsStaleDataError if the
+// active series data is older than threshold, or its age isn't known. A
+// threshold of zero means DefaultStalenessThreshold.
+func CheckStaleness(threshold time.Duration) error {
+	if threshold < 0 {
+		return errors.NotValidf("negative staleness threshold %s", threshold)
+	}
+	if threshold == 0 {
+		threshold = DefaultStalenessThreshold
+	}
+	staleness := Staleness()
+	if staleness.Since.IsZero() || staleness.Age > threshold {
+		return errors.Trace(staleDataError{staleness: staleness, threshold: threshold})
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type stalenessSuite struct {
+	testing.CleanupSuite
+	now time.Time
+}
+
+var _ = gc.Suite(&stalenessSuite{})
+
+func (s *stalenessSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	s.now = time.Date(2031, 6, 1, 0, 0, 0, 0, time.UTC)
+	series.DefaultRegistry().SetClock(func() time.Time { return s.now })
+}
+
+func (s *stalenessSuite) TestDefinitionsTimestamp(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "version": "2031.01", "timestamp": "2031-05-01T00:00:00Z", "series": []}`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	staleness := series.Staleness()
+	c.Assert(staleness.DataVersion.Version, gc.Equals, "2031.01")
+	c.Assert(staleness.Since, gc.Equals, time.Date(2031, 5, 1, 0, 0, 0, 0, time.UTC))
+	c.Assert(staleness.Age, gc.Equals, 31*24*time.Hour)
+
+	c.Assert(series.CheckStaleness(0), jc.ErrorIsNil)
+	err = series.CheckStaleness(7 * 24 * time.Hour)
+	c.Assert(err, jc.Satisfies, series.IsStaleDataError)
+	c.Assert(err, gc.ErrorMatches, `series data from definitions is 744h0m0s old, more than 168h0m0s`)
+}
+
+func (s *stalenessSuite) TestRemoteFetchTime(c *gc.C) {
+	fetched := s.now
+	err := series.LoadDefinitionsFrom(strings.NewReader(`{"schema": 1, "timestamp": "2030-01-01T00:00:00Z", "series": []}`), "https://example.com/series.json")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.now = s.now.Add(48 * time.Hour)
+	staleness := series.Staleness()
+	c.Assert(staleness.Since, gc.Equals, fetched)
+	c.Assert(staleness.Age, gc.Equals, 48*time.Hour)
+}
+
+func (s *stalenessSuite) TestUnknownAge(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": []}`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	staleness := series.Staleness()
+	c.Assert(staleness.Since.IsZero(), jc.IsTrue)
+	c.Assert(staleness.Age, gc.Equals, time.Duration(0))
+	err = series.CheckStaleness(time.Hour)
+	c.Assert(err, gc.ErrorMatches, `series data from definitions has unknown age`)
+}
+
+func (s *stalenessSuite) TestCheckStalenessInvalid(c *gc.C) {
+	err := series.CheckStaleness(-time.Hour)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
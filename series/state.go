@@ -0,0 +1,160 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/os"
+)
+
+// seriesState holds a copy of the mutable series state, so that changes
+// can be undone.
+type seriesState struct {
+	ubuntu          map[string]seriesVersion
+	nonUbuntu       map[string]seriesVersion
+	versions        map[string]string
+	definedOS       map[string]os.OSType
+	profiles        map[genericLinuxProfile]string
+	profileVersions map[string]string
+	pointReleases   map[string]string
+	sources         map[string]Source
+	resources       map[string]Resources
+	tags            map[string]set.Strings
+	ids             map[string]SeriesID
+	latestLts       string
+	updated         bool
+	dataVersion     DataVersionInfo
+	lastRefresh     time.Time
+	now             func() time.Time
+	rejectRetired   bool
+	futurePolicy    FuturePolicy
+	interceptors    []registeredInterceptor
+	changelogPath   string
+	requireDistro   bool
+	compatibility   compatibilityRules
+}
+
+// backupSeriesState copies the series state. The caller must hold
+// seriesVersionsMutex.
+func backupSeriesState() *seriesState {
+	return &seriesState{
+		ubuntu:          copySeriesVersionMap(ubuntuSeries),
+		nonUbuntu:       copySeriesVersionMap(nonUbuntuSeries),
+		versions:        copyStringMap(seriesVersions),
+		definedOS:       copyOSTypeMap(definedSeriesOS),
+		profiles:        copyProfileMap(genericLinuxProfiles),
+		profileVersions: copyStringMap(genericLinuxProfileVersions),
+		pointReleases:   copyStringMap(ubuntuPointReleases),
+		sources:         copySourceMap(seriesSources),
+		resources:       copyResourcesMap(seriesResources),
+		tags:            copyTagsMap(seriesTags),
+		ids:             copySeriesIDMap(registeredSeriesIDs),
+		latestLts:       latestLtsSeries,
+		updated:         updatedseriesVersions,
+		dataVersion:     dataVersion,
+		lastRefresh:     lastRefresh,
+		now:             defaultRegistry.now,
+		rejectRetired:   defaultRegistry.rejectRetired,
+		futurePolicy:    defaultRegistry.futurePolicy,
+		interceptors:    defaultRegistry.interceptors,
+		changelogPath:   defaultRegistry.changelogPath,
+		requireDistro:   defaultRegistry.requireDistroInfo,
+		compatibility:   defaultRegistry.compatibility,
+	}
+}
+
+// restore puts the copied series state back. The caller must hold
+// seriesVersionsMutex.
+func (s *seriesState) restore() {
+	ubuntuSeries = copySeriesVersionMap(s.ubuntu)
+	nonUbuntuSeries = copySeriesVersionMap(s.nonUbuntu)
+	seriesVersions = copyStringMap(s.versions)
+	definedSeriesOS = copyOSTypeMap(s.definedOS)
+	genericLinuxProfiles = copyProfileMap(s.profiles)
+	genericLinuxProfileVersions = copyStringMap(s.profileVersions)
+	ubuntuPointReleases = copyStringMap(s.pointReleases)
+	seriesSources = copySourceMap(s.sources)
+	seriesResources = copyResourcesMap(s.resources)
+	seriesTags = copyTagsMap(s.tags)
+	registeredSeriesIDs = copySeriesIDMap(s.ids)
+	registeredSeriesNames = reverseSeriesIDs(s.ids)
+	updateVersionSeries()
+	latestLtsSeries = s.latestLts
+	updatedseriesVersions = s.updated
+	dataVersion = s.dataVersion
+	lastRefresh = s.lastRefresh
+	defaultRegistry.now = s.now
+	defaultRegistry.rejectRetired = s.rejectRetired
+	defaultRegistry.futurePolicy = s.futurePolicy
+	defaultRegistry.interceptors = s.interceptors
+	defaultRegistry.changelogPath = s.changelogPath
+	defaultRegistry.requireDistroInfo = s.requireDistro
+	defaultRegistry.compatibility = s.compatibility
+}
+
+func copyResourcesMap(m map[string]Resources) map[string]Resources {
+	result := make(map[string]Resources, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func copySeriesIDMap(m map[string]SeriesID) map[string]SeriesID {
+	result := make(map[string]SeriesID, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func copyTagsMap(m map[string]set.Strings) map[string]set.Strings {
+	result := make(map[string]set.Strings, len(m))
+	for k, v := range m {
+		result[k] = set.NewStrings(v.Values()...)
+	}
+	return result
+}
+
+func copySeriesVersionMap(m map[string]seriesVersion) map[string]seriesVersion {
+	result := make(map[string]seriesVersion, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func copyOSTypeMap(m map[string]os.OSType) map[string]os.OSType {
+	result := make(map[string]os.OSType, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func copyProfileMap(m map[genericLinuxProfile]string) map[genericLinuxProfile]string {
+	result := make(map[genericLinuxProfile]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func copySourceMap(m map[string]Source) map[string]Source {
+	result := make(map[string]Source, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
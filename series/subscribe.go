@@ -0,0 +1,236 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/juju/os"
+)
+
+// ChangeSet describes how the known series changed after the series data
+// was updated.
+type ChangeSet struct {
+	Added   []Name `json:"added,omitempty"`
+	Removed []Name `json:"removed,omitempty"`
+	Updated []Name `json:"updated,omitempty"`
+	// Fields holds the changed fields of the updated series, ordered by
+	// series and then field.
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange describes the change to a single field of an updated series.
+// The values are formatted as text, with dates formatted as YYYY-MM-DD.
+type FieldChange struct {
+	Series Name   `json:"series"`
+	Field  string `json:"field"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// Diff returns the changes between the series in two snapshots, for
+// showing what a refresh of the series data changed.
+func Diff(old, new Snapshot) ChangeSet {
+	return diffKnownSeries(old.get().series, new.get().series)
+}
+
+// Empty returns true if nothing changed.
+func (c ChangeSet) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Updated) == 0
+}
+
+// Subscribe registers fn to be called whenever series are added, removed
+// or updated, whether by a distro-info refresh, a registration or loading
+// definitions. Calls are made in order on a separate goroutine, so fn may
+// call back into this package. The returned function unsubscribes fn.
+func Subscribe(fn func(ChangeSet)) func() {
+	return notifier.subscribe(fn)
+}
+
+var notifier = &changeNotifier{
+	subscribers: make(map[int]func(ChangeSet)),
+}
+
+// changeNotifier delivers change sets to subscribers without holding
+// seriesVersionsMutex.
+type changeNotifier struct {
+	mu          sync.Mutex
+	subscribers map[int]func(ChangeSet)
+	nextID      int
+	queue       []ChangeSet
+	running     bool
+}
+
+func (n *changeNotifier) subscribe(fn func(ChangeSet)) func() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	id := n.nextID
+	n.nextID++
+	n.subscribers[id] = fn
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.subscribers, id)
+	}
+}
+
+func (n *changeNotifier) publish(changes ChangeSet) {
+	if changes.Empty() {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.subscribers) == 0 {
+		return
+	}
+	n.queue = append(n.queue, changes)
+	if !n.running {
+		n.running = true
+		go n.run()
+	}
+}
+
+func (n *changeNotifier) run() {
+	for {
+		n.mu.Lock()
+		if len(n.queue) == 0 {
+			n.running = false
+			n.mu.Unlock()
+			return
+		}
+		changes := n.queue[0]
+		n.queue = n.queue[1:]
+		ids := make([]int, 0, len(n.subscribers))
+		for id := range n.subscribers {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		subscribers := make([]func(ChangeSet), len(ids))
+		for i, id := range ids {
+			subscribers[i] = n.subscribers[id]
+		}
+		n.mu.Unlock()
+
+		for _, fn := range subscribers {
+			fn(changes)
+		}
+	}
+}
+
+// seriesRecord holds everything known about a single series.
+type seriesRecord struct {
+	OS os.OSType
+	seriesVersion
+}
+
+// knownSeries returns a record of every known series. The caller must hold
+// seriesVersionsMutex.
+func knownSeries() map[string]seriesRecord {
+	known := make(map[string]seriesRecord)
+	for name, version := range ubuntuSeries {
+		known[name] = seriesRecord{OS: os.Ubuntu, seriesVersion: version}
+	}
+	for name, version := range nonUbuntuSeries {
+		osType, _ := getOSFromSeries(name)
+		known[name] = seriesRecord{OS: osType, seriesVersion: version}
+	}
+	for name, version := range seriesVersions {
+		if _, ok := known[name]; ok {
+			continue
+		}
+		osType, _ := getOSFromSeries(name)
+		known[name] = seriesRecord{OS: osType, seriesVersion: seriesVersion{Version: version}}
+	}
+	for name, version := range genericLinuxProfileVersions {
+		known[name] = seriesRecord{OS: os.GenericLinux, seriesVersion: seriesVersion{Version: version}}
+	}
+	for _, name := range macOSXSeriesTable() {
+		if _, ok := known[name]; ok {
+			continue
+		}
+		known[name] = seriesRecord{OS: os.MacOS, seriesVersion: seriesVersion{Version: name}}
+	}
+	return known
+}
+
+// recordField is the name and formatted value of a seriesRecord field.
+type recordField struct {
+	name  string
+	value string
+}
+
+// fields returns the fields of the record in the order they're reported
+// in a ChangeSet.
+func (r seriesRecord) fields() []recordField {
+	return []recordField{
+		{"OS", r.OS.String()},
+		{"Version", r.Version},
+		{"LTS", strconv.FormatBool(r.LTS)},
+		{"Supported", strconv.FormatBool(r.Supported)},
+		{"ESMSupported", strconv.FormatBool(r.ESMSupported)},
+		{"WarningInfo", strings.Join(r.WarningInfo, "; ")},
+		{"CreatedByLocalDistroInfo", strconv.FormatBool(r.CreatedByLocalDistroInfo)},
+		{"CodeName", r.CodeName},
+		{"Created", formatDistroInfoDate(r.Created)},
+		{"Released", formatDistroInfoDate(r.Released)},
+		{"EOL", formatDistroInfoDate(r.EOL)},
+	}
+}
+
+// diffKnownSeries returns the changes between two results of knownSeries.
+func diffKnownSeries(before, after map[string]seriesRecord) ChangeSet {
+	var changes ChangeSet
+	for name, record := range after {
+		old, ok := before[name]
+		if !ok {
+			changes.Added = append(changes.Added, Name(name))
+			continue
+		}
+		oldFields, newFields := old.fields(), record.fields()
+		updated := false
+		for i, field := range newFields {
+			if oldFields[i].value != field.value {
+				updated = true
+				changes.Fields = append(changes.Fields, FieldChange{
+					Series: Name(name),
+					Field:  field.name,
+					Old:    oldFields[i].value,
+					New:    field.value,
+				})
+			}
+		}
+		if updated {
+			changes.Updated = append(changes.Updated, Name(name))
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changes.Removed = append(changes.Removed, Name(name))
+		}
+	}
+	sortNames(changes.Added)
+	sortNames(changes.Removed)
+	sortNames(changes.Updated)
+	// Fields are appended in field order, so a stable sort by series keeps
+	// them in that order within each series.
+	sort.SliceStable(changes.Fields, func(i, j int) bool {
+		return changes.Fields[i].Series < changes.Fields[j].Series
+	})
+	return changes
+}
+
+// publishChanges notifies subscribers of the differences between before
+// and the current series, and records them in the changelog. It is
+// intended to be deferred with the state captured on entry, while
+// seriesVersionsMutex is held:
+//
+//	defer publishChanges(knownSeries())
+func publishChanges(before map[string]seriesRecord) {
+	changes := diffKnownSeries(before, knownSeries())
+	defaultRegistry.writeChangelog(changes)
+	notifier.publish(changes)
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type subscribeSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&subscribeSuite{})
+
+func (s *subscribeSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	cleanup := series.SetSeriesVersions(map[string]string{"focal": "20.04"})
+	s.AddCleanup(func(*gc.C) { cleanup() })
+}
+
+func (s *subscribeSuite) subscribe(c *gc.C) <-chan series.ChangeSet {
+	ch := make(chan series.ChangeSet, 10)
+	unsubscribe := series.Subscribe(func(changes series.ChangeSet) {
+		ch <- changes
+	})
+	s.AddCleanup(func(*gc.C) { unsubscribe() })
+	return ch
+}
+
+func nextChangeSet(c *gc.C, ch <-chan series.ChangeSet) series.ChangeSet {
+	select {
+	case changes := <-ch:
+		return changes
+	case <-time.After(10 * time.Second):
+		c.Fatalf("timed out waiting for change set")
+	}
+	panic("unreachable")
+}
+
+func (s *subscribeSuite) TestSubscribeRegistration(c *gc.C) {
+	ch := s.subscribe(c)
+
+	def := series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"}
+	err := series.RegisterSeries(def)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nextChangeSet(c, ch), jc.DeepEquals, series.ChangeSet{
+		Added: []series.Name{"picard"},
+	})
+
+	def.Supported = true
+	err = series.RegisterSeries(def)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nextChangeSet(c, ch), jc.DeepEquals, series.ChangeSet{
+		Updated: []series.Name{"picard"},
+		Fields: []series.FieldChange{
+			{Series: "picard", Field: "Supported", Old: "false", New: "true"},
+		},
+	})
+}
+
+func (s *subscribeSuite) TestSubscribeDefinitions(c *gc.C) {
+	ch := s.subscribe(c)
+
+	err := series.LoadDefinitions(strings.NewReader(`{
+		"schema": 1,
+		"series": [
+			{"series": "picard", "os": "ubuntu", "version": "97.04"},
+			{"series": "riker", "os": "ubuntu", "version": "97.10"},
+			{"series": "win2019", "os": "windows", "version": "win2019"}
+		]
+	}`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nextChangeSet(c, ch), jc.DeepEquals, series.ChangeSet{
+		Added:   []series.Name{"picard", "riker"},
+		Updated: []series.Name{"win2019"},
+		Fields: []series.FieldChange{
+			{Series: "win2019", Field: "Supported", Old: "true", New: "false"},
+		},
+	})
+}
+
+func (s *subscribeSuite) TestUnsubscribe(c *gc.C) {
+	ch := make(chan series.ChangeSet, 10)
+	unsubscribe := series.Subscribe(func(changes series.ChangeSet) {
+		ch <- changes
+	})
+	unsubscribe()
+
+	err := series.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Wait for a later subscriber to be told, then check the first wasn't.
+	later := s.subscribe(c)
+	err = series.RegisterSeries(series.Definition{Series: "riker", OS: "ubuntu", Version: "97.10"})
+	c.Assert(err, jc.ErrorIsNil)
+	nextChangeSet(c, later)
+	c.Assert(ch, gc.HasLen, 0)
+}
+
+func (s *subscribeSuite) TestChangeSetEmpty(c *gc.C) {
+	c.Assert(series.ChangeSet{}.Empty(), jc.IsTrue)
+	c.Assert(series.ChangeSet{Removed: []series.Name{"spock"}}.Empty(), jc.IsFalse)
+}
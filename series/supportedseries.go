@@ -4,11 +4,14 @@
 package series
 
 import (
+	"fmt"
 	"math"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -20,10 +23,41 @@ var (
 	logger = loggo.GetLogger("juju.juju.series")
 )
 
-type unknownOSForSeriesError string
+// unknownOSForSeriesError is returned when a series isn't known. It holds
+// the known series with names close to the one requested, if any.
+type unknownOSForSeriesError struct {
+	series      string
+	suggestions []string
+}
+
+// unknownSeries returns an unknownOSForSeriesError for series, suggesting
+// the series in known with names close to it.
+func unknownSeries(series string, known map[string]seriesRecord) error {
+	atomic.AddUint64(&counters.unknownLookups, 1)
+	names := make([]string, 0, len(known))
+	for name := range known {
+		names = append(names, name)
+	}
+	return unknownOSForSeriesError{
+		series:      series,
+		suggestions: suggestSeries(FormatSeries(series), names),
+	}
+}
 
 func (e unknownOSForSeriesError) Error() string {
-	return `unknown OS for series: "` + string(e) + `"`
+	msg := `unknown OS for series: "` + e.series + `"`
+	if len(e.suggestions) == 0 {
+		return msg
+	}
+	quoted := make([]string, len(e.suggestions))
+	for i, name := range e.suggestions {
+		quoted[i] = `"` + name + `"`
+	}
+	last := len(quoted) - 1
+	if last == 0 {
+		return msg + " (did you mean " + quoted[0] + "?)"
+	}
+	return msg + " (did you mean " + strings.Join(quoted[:last], ", ") + " or " + quoted[last] + "?)"
 }
 
 // IsUnknownOSForSeriesError returns true if err is of type unknownOSForSeriesError.
@@ -32,6 +66,34 @@ func IsUnknownOSForSeriesError(err error) bool {
 	return ok
 }
 
+// SeriesSuggestions returns the known series with names close to the one
+// that caused err, closest first, if err satisfies
+// IsUnknownOSForSeriesError. For example, looking up "bioinc" suggests
+// "bionic".
+func SeriesSuggestions(err error) []string {
+	if e, ok := errors.Cause(err).(unknownOSForSeriesError); ok {
+		return append([]string(nil), e.suggestions...)
+	}
+	return nil
+}
+
+// EmptyInputError is returned when a series or version argument is empty,
+// or holds only whitespace.
+type EmptyInputError struct {
+	// Input names the empty argument, "series" or "version".
+	Input string
+}
+
+func (e EmptyInputError) Error() string {
+	return "empty " + e.Input
+}
+
+// IsEmptyInputError returns true if err is of type EmptyInputError.
+func IsEmptyInputError(err error) bool {
+	_, ok := errors.Cause(err).(EmptyInputError)
+	return ok
+}
+
 type unknownSeriesVersionError string
 
 func (e unknownSeriesVersionError) Error() string {
@@ -56,6 +118,26 @@ func IsUnknownVersionSeriesError(err error) bool {
 	return ok
 }
 
+// AmbiguousVersionError is returned by VersionSeries when several series
+// have the version, as can happen with registered series.
+type AmbiguousVersionError struct {
+	// Version is the version looked up.
+	Version string
+	// Series holds the series with the version, sorted.
+	Series []string
+}
+
+func (e AmbiguousVersionError) Error() string {
+	return fmt.Sprintf("version %q is ambiguous, it is the version of series %s", e.Version, strings.Join(e.Series, ", "))
+}
+
+// IsAmbiguousVersionError returns true if err is of type
+// AmbiguousVersionError.
+func IsAmbiguousVersionError(err error) bool {
+	_, ok := errors.Cause(err).(AmbiguousVersionError)
+	return ok
+}
+
 // seriesVersions provides a mapping between series names and versions.
 // The values here are current as of the time of writing. On Ubuntu systems, we update
 // these values from /usr/share/distro-info/ubuntu.csv to ensure we have the latest values.
@@ -81,6 +163,8 @@ var seriesVersions = map[string]string{
 	"focal":            "20.04",
 	"groovy":           "20.10",
 	"hirsute":          "21.04",
+	"jammy":            "22.04",
+	"noble":            "24.04",
 	"win2008r2":        "win2008r2",
 	"win2012hvr2":      "win2012hvr2",
 	"win2012hv":        "win2012hv",
@@ -90,26 +174,150 @@ var seriesVersions = map[string]string{
 	"win2016hv":        "win2016hv",
 	"win2016nano":      "win2016nano",
 	"win2019":          "win2019",
+	"win2022":          "win2022",
+	"win23h2":          "win23h2",
 	"win7":             "win7",
 	"win8":             "win8",
 	"win81":            "win81",
 	"win10":            "win10",
+	"win11":            "win11",
 	"centos7":          "centos7",
 	"centos8":          "centos8",
+	"centos8-stream":   "centos8-stream",
+	"centos9":          "centos9",
 	"opensuseleap":     "opensuse42",
+	"opensuse15.4":     "opensuse15.4",
+	"opensuse15.5":     "opensuse15.5",
+	"opensuse15.6":     "opensuse15.6",
+	"openbsd7.3":       "openbsd7.3",
+	"openbsd7.4":       "openbsd7.4",
+	"openbsd7.5":       "openbsd7.5",
+	"netbsd9":          "netbsd9",
+	"netbsd10":         "netbsd10",
+	"aix7.2":           "aix7.2",
+	"aix7.3":           "aix7.3",
+	"ibmi7.4":          "ibmi7.4",
+	"ibmi7.5":          "ibmi7.5",
+	"android11":        "android11",
+	"android12":        "android12",
+	"android13":        "android13",
+	"android14":        "android14",
+	"stretch":          "9",
+	"buster":           "10",
+	"bullseye":         "11",
+	"bookworm":         "12",
+	"trixie":           "13",
+	"fedora38":         "38",
+	"fedora39":         "39",
+	"fedora40":         "40",
+	"fedora41":         "41",
+	"rhel8":            "rhel8",
+	"rhel9":            "rhel9",
+	"rocky8":           "rocky8",
+	"rocky9":           "rocky9",
+	"alma8":            "alma8",
+	"alma9":            "alma9",
 	genericLinuxSeries: genericLinuxVersion,
 }
 
-// versionSeries provides a mapping between versions and series names.
-var versionSeries = reverseSeriesVersion()
+// versionSeries provides a mapping between versions and the series with
+// them, sorted. Usually there is one series per version, but registered
+// series may share a version. It is built by updateVersionSeries when the
+// series data is first used.
+var versionSeries map[string][]string
 
+// centosSeries holds the CentOS series. CentOS Linux 8 and CentOS Stream 8
+// share a major version but not an end of life, so they are separate
+// series; CentOS 9 was only released as CentOS Stream.
 var centosSeries = map[string]string{
-	"centos7": "centos7",
-	"centos8": "centos8",
+	"centos7":        "centos7",
+	"centos8":        "centos8",
+	"centos8-stream": "centos8-stream",
+	"centos9":        "centos9",
+}
+
+// rhelSeries holds the Red Hat Enterprise Linux series, which are named
+// after the major version, as the minor releases of RHEL are updates of
+// the same series.
+var rhelSeries = map[string]string{
+	"rhel8": "rhel8",
+	"rhel9": "rhel9",
 }
 
+// rockySeries holds the Rocky Linux series, which like RHEL are named
+// after the major version.
+var rockySeries = map[string]string{
+	"rocky8": "rocky8",
+	"rocky9": "rocky9",
+}
+
+// almaSeries holds the AlmaLinux series, which are named after the major
+// version, although os-release identifies the distribution as
+// "almalinux".
+var almaSeries = map[string]string{
+	"alma8": "alma8",
+	"alma9": "alma9",
+}
+
+// opensuseSeries holds the openSUSE Leap series. Leap 15 series are named
+// after their service pack; "opensuseleap" is Leap 42.
 var opensuseSeries = map[string]string{
 	"opensuseleap": "opensuse42",
+	"opensuse15.4": "opensuse15.4",
+	"opensuse15.5": "opensuse15.5",
+	"opensuse15.6": "opensuse15.6",
+}
+
+// openbsdSeries holds the OpenBSD series, which are named after the full
+// release version as every OpenBSD release is a major one.
+var openbsdSeries = map[string]string{
+	"openbsd7.3": "openbsd7.3",
+	"openbsd7.4": "openbsd7.4",
+	"openbsd7.5": "openbsd7.5",
+}
+
+// netbsdSeries holds the NetBSD series, which are named after the major
+// version.
+var netbsdSeries = map[string]string{
+	"netbsd9":  "netbsd9",
+	"netbsd10": "netbsd10",
+}
+
+// aixSeries holds the AIX series. IBM i runs Go programs in PASE, its AIX
+// runtime environment, so IBM i releases are AIX series too.
+var aixSeries = map[string]string{
+	"aix7.2":  "aix7.2",
+	"aix7.3":  "aix7.3",
+	"ibmi7.4": "ibmi7.4",
+	"ibmi7.5": "ibmi7.5",
+}
+
+// androidSeries holds the Android series, which are named after the major
+// version of the release.
+var androidSeries = map[string]string{
+	"android11": "android11",
+	"android12": "android12",
+	"android13": "android13",
+	"android14": "android14",
+}
+
+// debianSeries holds the Debian series, which are named after the
+// codename of the release.
+var debianSeries = map[string]string{
+	"stretch":  "9",
+	"buster":   "10",
+	"bullseye": "11",
+	"bookworm": "12",
+	"trixie":   "13",
+}
+
+// fedoraSeries holds the Fedora series, which are named after the release
+// version.
+var fedoraSeries = map[string]string{
+	"fedora38": "38",
+	"fedora39": "39",
+	"fedora40": "40",
+	"fedora41": "41",
 }
 
 var kubernetesSeries = map[string]string{
@@ -126,6 +334,9 @@ type seriesVersion struct {
 	LTS bool
 	// Supported defines if Juju classifies the series as officially supported.
 	Supported bool
+	// SupportOverridden is true if Supported was set with Tx.SetSupported,
+	// in which case it applies whatever the release and end of life dates.
+	SupportOverridden bool
 	// Extended security maintenance for customers, extends the supported bool
 	// for how Juju classifies the series.
 	ESMSupported bool
@@ -136,6 +347,12 @@ type seriesVersion struct {
 	// by the local distro-info information on the system.
 	// This is useful to understand why a version appears yet is not supported.
 	CreatedByLocalDistroInfo bool
+	// CodeName, Created, Released and EOL hold the release information
+	// found in distro-info, if there is any.
+	CodeName string
+	Created  time.Time
+	Released time.Time
+	EOL      time.Time
 }
 
 var ubuntuSeries = map[string]seriesVersion{
@@ -209,6 +426,16 @@ var ubuntuSeries = map[string]seriesVersion{
 		Version:   "21.04",
 		Supported: false,
 	},
+	"jammy": {
+		Version:   "22.04",
+		LTS:       true,
+		Supported: true,
+	},
+	"noble": {
+		Version:   "24.04",
+		LTS:       true,
+		Supported: true,
+	},
 }
 
 var nonUbuntuSeries = map[string]seriesVersion{
@@ -248,6 +475,16 @@ var nonUbuntuSeries = map[string]seriesVersion{
 		Version:   "win2019",
 		Supported: true,
 	},
+	"win2022": {
+		Version:   "win2022",
+		Supported: true,
+	},
+	"win23h2": {
+		Version:   "win23h2",
+		Supported: true,
+		Released:  time.Date(2023, 10, 24, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2025, 10, 24, 0, 0, 0, 0, time.UTC),
+	},
 	"win7": {
 		Version:   "win7",
 		Supported: true,
@@ -264,18 +501,140 @@ var nonUbuntuSeries = map[string]seriesVersion{
 		Version:   "win10",
 		Supported: true,
 	},
+	"win11": {
+		Version:   "win11",
+		Supported: true,
+	},
 	"centos7": {
 		Version:   "centos7",
 		Supported: true,
+		Released:  time.Date(2014, 7, 7, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
 	},
 	"centos8": {
 		Version:   "centos8",
 		Supported: true,
+		Released:  time.Date(2019, 9, 24, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC),
+	},
+	"centos8-stream": {
+		Version:   "centos8-stream",
+		Supported: true,
+		Released:  time.Date(2019, 9, 24, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+	},
+	"centos9": {
+		Version:   "centos9",
+		Supported: true,
+		Released:  time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2027, 5, 31, 0, 0, 0, 0, time.UTC),
+	},
+	"rhel8": {
+		Version:   "rhel8",
+		Supported: true,
+		Released:  time.Date(2019, 5, 7, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2029, 5, 31, 0, 0, 0, 0, time.UTC),
+	},
+	"rhel9": {
+		Version:   "rhel9",
+		Supported: true,
+		Released:  time.Date(2022, 5, 17, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2032, 5, 31, 0, 0, 0, 0, time.UTC),
+	},
+	"rocky8": {
+		Version:   "rocky8",
+		Supported: true,
+		Released:  time.Date(2021, 6, 21, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2029, 5, 31, 0, 0, 0, 0, time.UTC),
+	},
+	"rocky9": {
+		Version:   "rocky9",
+		Supported: true,
+		Released:  time.Date(2022, 7, 14, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2032, 5, 31, 0, 0, 0, 0, time.UTC),
+	},
+	"alma8": {
+		Version:   "alma8",
+		Supported: true,
+		Released:  time.Date(2021, 3, 30, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2029, 3, 1, 0, 0, 0, 0, time.UTC),
+	},
+	"alma9": {
+		Version:   "alma9",
+		Supported: true,
+		Released:  time.Date(2022, 5, 26, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2032, 5, 31, 0, 0, 0, 0, time.UTC),
 	},
 	"opensuseleap": {
 		Version:   "opensuse42",
 		Supported: true,
 	},
+	"opensuse15.4": {
+		Version:   "opensuse15.4",
+		Supported: true,
+	},
+	"opensuse15.5": {
+		Version:   "opensuse15.5",
+		Supported: true,
+	},
+	"opensuse15.6": {
+		Version:   "opensuse15.6",
+		Supported: true,
+	},
+	"stretch": {
+		Version:   "9",
+		Supported: true,
+		Released:  time.Date(2017, 6, 17, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC),
+	},
+	"buster": {
+		Version:   "10",
+		Supported: true,
+		Released:  time.Date(2019, 7, 6, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+	},
+	"bullseye": {
+		Version:   "11",
+		Supported: true,
+		Released:  time.Date(2021, 8, 14, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC),
+	},
+	"bookworm": {
+		Version:   "12",
+		Supported: true,
+		Released:  time.Date(2023, 6, 10, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2028, 6, 30, 0, 0, 0, 0, time.UTC),
+	},
+	"trixie": {
+		Version:   "13",
+		Supported: true,
+		Released:  time.Date(2025, 8, 9, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+	},
+	"fedora38": {
+		Version:   "38",
+		Supported: true,
+		Released:  time.Date(2023, 4, 18, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2024, 5, 21, 0, 0, 0, 0, time.UTC),
+	},
+	"fedora39": {
+		Version:   "39",
+		Supported: true,
+		Released:  time.Date(2023, 11, 7, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2024, 11, 26, 0, 0, 0, 0, time.UTC),
+	},
+	"fedora40": {
+		Version:   "40",
+		Supported: true,
+		Released:  time.Date(2024, 4, 23, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2025, 5, 13, 0, 0, 0, 0, time.UTC),
+	},
+	"fedora41": {
+		Version:   "41",
+		Supported: true,
+		Released:  time.Date(2024, 10, 29, 0, 0, 0, 0, time.UTC),
+		EOL:       time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC),
+	},
 	genericLinuxSeries: {
 		Version:   genericLinuxVersion,
 		Supported: true,
@@ -300,6 +659,8 @@ var windowsVersionMatchOrder = []string{
 	"Hyper-V Server 2016",
 	"Windows Server 2016",
 	"Windows Server 2019",
+	"Windows Server 2022",
+	"Windows Server, version 23H2",
 	"Windows Storage Server 2012 R2",
 	"Windows Storage Server 2012",
 	"Windows Storage Server 2016",
@@ -307,57 +668,113 @@ var windowsVersionMatchOrder = []string{
 	"Windows 8.1",
 	"Windows 8",
 	"Windows 10",
+	"Windows 11",
+}
+
+var (
+	windowsTablesOnce sync.Once
+
+	// windowsVersions is a mapping consisting of the output from
+	// the following WMI query: (gwmi Win32_OperatingSystem).Name
+	windowsVersions map[string]string
+
+	// windowsNanoVersions is a mapping from the product name
+	// stored in registry to a juju defined nano-series
+	// On the nano version so far the product name actually
+	// is identical to the correspondent main windows version
+	// and the information about it being nano is stored in
+	// a different place.
+	windowsNanoVersions map[string]string
+)
+
+// loadWindowsTables builds the windows lookup tables the first time they're
+// needed, so that programs that never deal with windows series don't
+// allocate them.
+func loadWindowsTables() {
+	windowsTablesOnce.Do(func() {
+		windowsVersions = embeddedWindowsVersions()
+		windowsNanoVersions = embeddedWindowsNanoVersions()
+	})
 }
 
-// windowsVersions is a mapping consisting of the output from
-// the following WMI query: (gwmi Win32_OperatingSystem).Name
-var windowsVersions = map[string]string{
-	"Hyper-V Server 2012 R2":         "win2012hvr2",
-	"Hyper-V Server 2012":            "win2012hv",
-	"Windows Server 2008 R2":         "win2008r2",
-	"Windows Server 2012 R2":         "win2012r2",
-	"Windows Server 2012":            "win2012",
-	"Hyper-V Server 2016":            "win2016hv",
-	"Windows Server 2016":            "win2016",
-	"Windows Server 2019":            "win2019",
-	"Windows Storage Server 2012 R2": "win2012r2",
-	"Windows Storage Server 2012":    "win2012",
-	"Windows Storage Server 2016":    "win2016",
-	"Windows Storage Server 2019":    "win2019",
-	"Windows 7":                      "win7",
-	"Windows 8.1":                    "win81",
-	"Windows 8":                      "win8",
-	"Windows 10":                     "win10",
-}
-
-// windowsNanoVersions is a mapping from the product name
-// stored in registry to a juju defined nano-series
-// On the nano version so far the product name actually
-// is identical to the correspondent main windows version
-// and the information about it being nano is stored in
-// a different place.
-var windowsNanoVersions = map[string]string{
-	"Windows Server 2016": "win2016nano",
+// windowsVersionTable returns windowsVersions, building it if needed.
+func windowsVersionTable() map[string]string {
+	loadWindowsTables()
+	return windowsVersions
+}
+
+// windowsNanoVersionTable returns windowsNanoVersions, building it if
+// needed.
+func windowsNanoVersionTable() map[string]string {
+	loadWindowsTables()
+	return windowsNanoVersions
+}
+
+func embeddedWindowsVersions() map[string]string {
+	return map[string]string{
+		"Hyper-V Server 2012 R2":         "win2012hvr2",
+		"Hyper-V Server 2012":            "win2012hv",
+		"Windows Server 2008 R2":         "win2008r2",
+		"Windows Server 2012 R2":         "win2012r2",
+		"Windows Server 2012":            "win2012",
+		"Hyper-V Server 2016":            "win2016hv",
+		"Windows Server 2016":            "win2016",
+		"Windows Server 2019":            "win2019",
+		"Windows Server 2022":            "win2022",
+		"Windows Server, version 23H2":   "win23h2",
+		"Windows Storage Server 2012 R2": "win2012r2",
+		"Windows Storage Server 2012":    "win2012",
+		"Windows Storage Server 2016":    "win2016",
+		"Windows Storage Server 2019":    "win2019",
+		"Windows 7":                      "win7",
+		"Windows 8.1":                    "win81",
+		"Windows 8":                      "win8",
+		"Windows 10":                     "win10",
+		"Windows 11":                     "win11",
+	}
+}
+
+func embeddedWindowsNanoVersions() map[string]string {
+	return map[string]string{
+		"Windows Server 2016": "win2016nano",
+	}
 }
 
 // WindowsVersions returns all windows versions as a map
 // If we have nan and windows version in common, nano takes precedence
 func WindowsVersions() map[string]string {
 	save := make(map[string]string)
-	for i, val := range windowsVersions {
+	for i, val := range windowsVersionTable() {
 		save[i] = val
 	}
 
-	for i, val := range windowsNanoVersions {
+	for i, val := range windowsNanoVersionTable() {
 		save[i] = val
 	}
 	return save
 }
 
+// WindowsSeriesVersions returns the known windows series, including nano
+// series, mapped to their versions. The map is a copy, so callers may
+// modify it.
+func WindowsSeriesVersions() map[string]string {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+
+	result := make(map[string]string)
+	for name, record := range knownSeries() {
+		if record.OS == os.Windows {
+			result[name] = record.Version
+		}
+	}
+	return result
+}
+
 func OverwrittenWindowsVersions() []string {
 	var overwrittenValues []string
-	for i, _ := range windowsNanoVersions {
-		if overwritten, ok := windowsVersions[i]; ok {
+	for i, _ := range windowsNanoVersionTable() {
+		if overwritten, ok := windowsVersionTable()[i]; ok {
 			overwrittenValues = append(overwrittenValues, overwritten)
 		}
 	}
@@ -371,7 +788,8 @@ func OverwrittenWindowsVersions() []string {
 // because we might want to take decisions dependant on
 // whether we have a nano series or not in more general code.
 func IsWindowsNano(series string) bool {
-	for _, val := range windowsNanoVersions {
+	series = FormatSeries(series)
+	for _, val := range windowsNanoVersionTable() {
 		if val == series {
 			return true
 		}
@@ -380,23 +798,32 @@ func IsWindowsNano(series string) bool {
 }
 
 // GetOSFromSeries will return the operating system based
-// on the series that is passed to it
+// on the series that is passed to it. The series is matched ignoring case
+// and surrounding whitespace.
 func GetOSFromSeries(series string) (os.OSType, error) {
-	if series == "" {
-		return os.Unknown, errors.NotValidf("series %q", series)
-	}
-	osType, err := getOSFromSeries(series)
-	if err == nil {
-		return osType, nil
+	name := FormatSeries(series)
+	if name == "" {
+		return os.Unknown, errors.Trace(EmptyInputError{Input: "series"})
 	}
 
 	seriesVersionsMutex.Lock()
 	defer seriesVersionsMutex.Unlock()
 
-	updateSeriesVersionsOnce()
-	return getOSFromSeries(series)
+	osType, err := getOSFromSeries(name)
+	if err != nil {
+		updateSeriesVersionsOnce()
+		if osType, err = getOSFromSeries(name); err != nil {
+			return os.Unknown, errors.Trace(unknownSeries(series, knownSeries()))
+		}
+	}
+	if err := defaultRegistry.checkRetiredLocked(name); err != nil {
+		return os.Unknown, errors.Trace(err)
+	}
+	return osType, nil
 }
 
+// getOSFromSeries returns the operating system of the normalized series.
+// The caller must hold seriesVersionsMutex.
 func getOSFromSeries(series string) (os.OSType, error) {
 	if _, ok := ubuntuSeries[series]; ok {
 		return os.Ubuntu, nil
@@ -404,50 +831,137 @@ func getOSFromSeries(series string) (os.OSType, error) {
 	if _, ok := centosSeries[series]; ok {
 		return os.CentOS, nil
 	}
+	if _, ok := rhelSeries[series]; ok {
+		return os.RHEL, nil
+	}
+	if _, ok := rockySeries[series]; ok {
+		return os.Rocky, nil
+	}
+	if _, ok := almaSeries[series]; ok {
+		return os.AlmaLinux, nil
+	}
 	if _, ok := opensuseSeries[series]; ok {
 		return os.OpenSUSE, nil
 	}
 	if _, ok := kubernetesSeries[series]; ok {
 		return os.Kubernetes, nil
 	}
+	if _, ok := openbsdSeries[series]; ok {
+		return os.OpenBSD, nil
+	}
+	if _, ok := netbsdSeries[series]; ok {
+		return os.NetBSD, nil
+	}
+	if _, ok := aixSeries[series]; ok {
+		return os.AIX, nil
+	}
+	if _, ok := androidSeries[series]; ok {
+		return os.Android, nil
+	}
+	if _, ok := debianSeries[series]; ok {
+		return os.Debian, nil
+	}
+	if _, ok := fedoraSeries[series]; ok {
+		return os.Fedora, nil
+	}
 	if series == genericLinuxSeries {
 		return os.GenericLinux, nil
 	}
-	for _, val := range windowsVersions {
+	if _, ok := genericLinuxProfileVersions[series]; ok {
+		return os.GenericLinux, nil
+	}
+	if osType, ok := definedSeriesOS[series]; ok {
+		return osType, nil
+	}
+	for _, val := range windowsVersionTable() {
 		if val == series {
 			return os.Windows, nil
 		}
 	}
-	for _, val := range windowsNanoVersions {
+	for _, val := range windowsNanoVersionTable() {
 		if val == series {
 			return os.Windows, nil
 		}
 	}
-	for _, val := range macOSXSeries {
+	for _, val := range macOSXSeriesTable() {
 		if val == series {
-			return os.OSX, nil
+			return os.MacOS, nil
 		}
 	}
 
-	return os.Unknown, errors.Trace(unknownOSForSeriesError(series))
+	return os.Unknown, errors.Trace(unknownOSForSeriesError{series: series})
 }
 
 var (
 	seriesVersionsMutex sync.Mutex
 )
 
+// FormatSeries returns the series in the lowercase form used for
+// lookups, without surrounding whitespace, as series names often come from
+// user input.
+func FormatSeries(series string) string {
+	return strings.ToLower(strings.TrimSpace(series))
+}
+
+// CanonicalSeries returns the canonical name of a known series, which is
+// matched ignoring case and surrounding whitespace. For example, "Bionic "
+// gives "bionic". Ubuntu series may also be given by their full codename,
+// or either word of it, so "Bionic Beaver" and "beaver" give "bionic" too.
+func CanonicalSeries(series string) (Name, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	resolved, err := defaultRegistry.intercept(LookupCanonicalSeries, series, string(name))
+	return Name(resolved), errors.Trace(err)
+}
+
+// canonicalSeries returns the canonical name of a known series, before it
+// is passed to the interceptors. Lookups of the package's own series data
+// use it, so that interceptors only affect the names given to callers.
+func canonicalSeries(series string) (Name, error) {
+	name := FormatSeries(series)
+	if name == "" {
+		return "", errors.Trace(EmptyInputError{Input: "series"})
+	}
+	_, err := GetOSFromSeries(name)
+	if IsRetiredSeriesError(err) {
+		return "", errors.Trace(err)
+	}
+	if err != nil {
+		seriesVersionsMutex.Lock()
+		fromCodeName, ok := seriesFromCodeName(name)
+		if !ok {
+			err = unknownSeries(series, knownSeries())
+		}
+		seriesVersionsMutex.Unlock()
+		if !ok {
+			return "", errors.Trace(err)
+		}
+		name = fromCodeName
+	}
+	return Name(name), nil
+}
+
 // SeriesVersion returns the version for the specified series.
 func SeriesVersion(series string) (string, error) {
-	if series == "" {
-		return "", errors.Trace(unknownSeriesVersionError(""))
+	name := FormatSeries(series)
+	if name == "" {
+		return "", errors.Trace(EmptyInputError{Input: "series"})
 	}
 	seriesVersionsMutex.Lock()
 	defer seriesVersionsMutex.Unlock()
-	if vers, ok := seriesVersions[series]; ok {
+	if err := defaultRegistry.checkRetiredLocked(name); err != nil {
+		return "", errors.Trace(err)
+	}
+	if vers, ok := seriesVersions[name]; ok {
+		return vers, nil
+	}
+	if vers, ok := genericLinuxProfileVersions[name]; ok {
 		return vers, nil
 	}
 	updateSeriesVersionsOnce()
-	if vers, ok := seriesVersions[series]; ok {
+	if vers, ok := seriesVersions[name]; ok {
 		return vers, nil
 	}
 
@@ -456,16 +970,17 @@ func SeriesVersion(series string) (string, error) {
 
 // UbuntuSeriesVersion returns the ubuntu version for the specified series.
 func UbuntuSeriesVersion(series string) (string, error) {
-	if series == "" {
-		return "", errors.Trace(unknownSeriesVersionError(""))
+	name := FormatSeries(series)
+	if name == "" {
+		return "", errors.Trace(EmptyInputError{Input: "series"})
 	}
 	seriesVersionsMutex.Lock()
 	defer seriesVersionsMutex.Unlock()
-	if vers, ok := ubuntuSeries[series]; ok {
+	if vers, ok := ubuntuSeries[name]; ok {
 		return vers.Version, nil
 	}
 	updateSeriesVersionsOnce()
-	if vers, ok := ubuntuSeries[series]; ok {
+	if vers, ok := ubuntuSeries[name]; ok {
 		return vers.Version, nil
 	}
 
@@ -473,32 +988,94 @@ func UbuntuSeriesVersion(series string) (string, error) {
 }
 
 // VersionSeries returns the series (e.g.trusty) for the specified version (e.g. 14.04).
+// If several series have the version, an AmbiguousVersionError is
+// returned; VersionSeriesAll returns them all.
 func VersionSeries(version string) (string, error) {
-	if version == "" {
-		return "", errors.Trace(unknownVersionSeriesError(""))
+	series, err := resolveVersionSeries(version)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return defaultRegistry.intercept(LookupVersionSeries, version, series)
+}
+
+// VersionSeriesAll returns all the series with the version, sorted. An
+// error satisfying IsUnknownVersionSeriesError is returned if there are
+// none.
+func VersionSeriesAll(version string) ([]string, error) {
+	all, err := resolveVersionSeriesAll(version)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for i, series := range all {
+		if all[i], err = defaultRegistry.intercept(LookupVersionSeries, version, series); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return all, nil
+}
+
+// resolveVersionSeries returns the series for the version, before it is
+// passed to the interceptors.
+func resolveVersionSeries(version string) (string, error) {
+	all, err := resolveVersionSeriesAll(version)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(all) > 1 {
+		return "", errors.Trace(AmbiguousVersionError{
+			Version: strings.TrimSpace(version),
+			Series:  all,
+		})
+	}
+	return all[0], nil
+}
+
+// resolveVersionSeriesAll returns the series with the version, leaving out
+// retired series if they are rejected. An error is returned if no series
+// are left.
+func resolveVersionSeriesAll(version string) ([]string, error) {
+	trimmed := strings.TrimSpace(version)
+	if trimmed == "" {
+		return nil, errors.Trace(EmptyInputError{Input: "version"})
 	}
 	seriesVersionsMutex.Lock()
 	defer seriesVersionsMutex.Unlock()
-	if series, ok := versionSeries[version]; ok {
-		return series, nil
+	all, ok := versionSeries[trimmed]
+	if !ok {
+		updateSeriesVersionsOnce()
+		all, ok = versionSeries[trimmed]
 	}
-	updateSeriesVersionsOnce()
-	if series, ok := versionSeries[version]; ok {
-		return series, nil
+	if !ok {
+		return nil, errors.Trace(unknownVersionSeriesError(version))
+	}
+	var result []string
+	var retiredErr error
+	for _, series := range all {
+		if err := defaultRegistry.checkRetiredLocked(series); err != nil {
+			retiredErr = err
+			continue
+		}
+		result = append(result, series)
+	}
+	if len(result) == 0 {
+		return nil, errors.Trace(retiredErr)
 	}
-	return "", errors.Trace(unknownVersionSeriesError(version))
+	return result, nil
 }
 
 // WindowsVersionSeries returns the series (eg: win2012r2) for the specified version
-// (eg: Windows Server 2012 R2 Standard)
+// (eg: Windows Server 2012 R2 Standard). Localized product names, such as
+// "Microsoft Windows 10 专业版", are recognised.
 func WindowsVersionSeries(version string) (string, error) {
+	version = strings.TrimSpace(version)
 	if version == "" {
-		return "", errors.Trace(unknownVersionSeriesError(""))
+		return "", errors.Trace(EmptyInputError{Input: "version"})
 	}
-	for _, val := range windowsVersionMatchOrder {
-		if strings.HasPrefix(version, val) {
-			return windowsVersions[val], nil
+	if series, ok := windowsSeriesFromProductName(version, false); ok {
+		if err := defaultRegistry.checkRetired(series); err != nil {
+			return "", errors.Trace(err)
 		}
+		return defaultRegistry.intercept(LookupWindowsVersionSeries, version, series)
 	}
 	return "", errors.Trace(unknownVersionSeriesError(""))
 }
@@ -506,11 +1083,12 @@ func WindowsVersionSeries(version string) (string, error) {
 // CentOSVersionSeries validates that the supplied series (eg: centos7)
 // is supported.
 func CentOSVersionSeries(version string) (string, error) {
+	version = FormatSeries(version)
 	if version == "" {
-		return "", errors.Trace(unknownVersionSeriesError(""))
+		return "", errors.Trace(EmptyInputError{Input: "version"})
 	}
 	if series, ok := centosSeries[version]; ok {
-		return series, nil
+		return defaultRegistry.intercept(LookupCentOSVersionSeries, version, series)
 	}
 	return "", errors.Trace(unknownVersionSeriesError(""))
 
@@ -521,18 +1099,40 @@ func SupportedLts() []string {
 	seriesVersionsMutex.Lock()
 	defer seriesVersionsMutex.Unlock()
 	updateSeriesVersionsOnce()
+	return supportedLtsAt(defaultRegistry.today())
+}
 
-	versions := []string{}
-	for _, version := range ubuntuSeries {
-		if !version.LTS || !version.Supported {
+// SupportedLtsAt returns the LTS series that were supported at t, in
+// ascending order.
+func SupportedLtsAt(t time.Time) []string {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	return supportedLtsAt(t.UTC())
+}
+
+// supportedLtsAt returns the LTS series supported at t. The caller must hold
+// seriesVersionsMutex.
+func supportedLtsAt(t time.Time) []string {
+	var versions []namedSeriesVersion
+	for name, version := range ubuntuSeries {
+		if !version.LTS || !version.supportedAt(t) {
 			continue
 		}
-		versions = append(versions, version.Version)
+		versions = append(versions, namedSeriesVersion{Name: name, SeriesVersion: version})
 	}
-	sort.Strings(versions)
+	// Series from distro-info have " LTS" in their version.
+	sort.Slice(versions, func(i, j int) bool {
+		vi := strings.TrimSuffix(versions[i].SeriesVersion.Version, " LTS")
+		vj := strings.TrimSuffix(versions[j].SeriesVersion.Version, " LTS")
+		if vi != vj {
+			return vi < vj
+		}
+		return versions[i].Name < versions[j].Name
+	})
 	sorted := []string{}
 	for _, v := range versions {
-		sorted = append(sorted, versionSeries[v])
+		sorted = append(sorted, v.Name)
 	}
 	return sorted
 }
@@ -551,9 +1151,10 @@ func LatestLts() string {
 	defer seriesVersionsMutex.Unlock()
 	updateSeriesVersionsOnce()
 
+	today := defaultRegistry.today()
 	var latest string
 	for k, version := range ubuntuSeries {
-		if !version.LTS || !version.Supported {
+		if !version.LTS || !version.supportedAt(today) {
 			continue
 		}
 		if version.Version > ubuntuSeries[latest].Version {
@@ -565,6 +1166,41 @@ func LatestLts() string {
 	return latest
 }
 
+// DefaultSupportedLTS returns the latest supported LTS series. If available
+// isn't nil, only series that appear in it are candidates, so that callers
+// can pass the series of the image streams they can use and never get a
+// series that has no image yet. The entries in available may be series or
+// versions, such as "focal" or "20.04". A NotFound error is returned if no
+// supported LTS series is available.
+func DefaultSupportedLTS(available []string) (string, error) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+
+	candidates := supportedLtsAt(defaultRegistry.today())
+	if available != nil {
+		allowed := make(map[string]bool)
+		for _, entry := range available {
+			entry = FormatSeries(entry)
+			allowed[entry] = true
+			for _, series := range versionSeries[entry] {
+				allowed[series] = true
+			}
+		}
+		var filtered []string
+		for _, series := range candidates {
+			if allowed[series] {
+				filtered = append(filtered, series)
+			}
+		}
+		candidates = filtered
+	}
+	if len(candidates) == 0 {
+		return "", errors.NotFoundf("supported LTS series with an available image")
+	}
+	return candidates[len(candidates)-1], nil
+}
+
 // SetLatestLtsForTesting is provided to allow tests to override the lts series
 // used and decouple the tests from the host by avoiding calling out to
 // distro-info.  It returns the previous setting so that it may be set back to
@@ -575,16 +1211,23 @@ func SetLatestLtsForTesting(series string) string {
 	return old
 }
 
+// updateVersionSeries rebuilds the state derived from the series tables.
+// It must be called, with seriesVersionsMutex held, whenever they change.
 func updateVersionSeries() {
 	versionSeries = reverseSeriesVersion()
+	supportedJujuSeries = nil
+	currentSnapshot = nil
 }
 
 // reverseSeriesVersion returns reverse of seriesVersion map,
-// keyed on versions with series as values.
-func reverseSeriesVersion() map[string]string {
-	reverse := make(map[string]string, len(seriesVersions))
+// keyed on versions with the sorted series as values.
+func reverseSeriesVersion() map[string][]string {
+	reverse := make(map[string][]string, len(seriesVersions))
 	for k, v := range seriesVersions {
-		reverse[v] = k
+		reverse[v] = append(reverse[v], k)
+	}
+	for _, names := range reverse {
+		sort.Strings(names)
 	}
 	return reverse
 }
@@ -607,11 +1250,9 @@ type namedSeriesVersion struct {
 	Version       float64
 }
 
+// ubuntuSeriesSortedByVersion returns the ubuntu series, newest first. The
+// caller must hold seriesVersionsMutex.
 func ubuntuSeriesSortedByVersion() []namedSeriesVersion {
-	seriesVersionsMutex.Lock()
-	defer seriesVersionsMutex.Unlock()
-	updateSeriesVersionsOnce()
-
 	s := make([]namedSeriesVersion, 0, len(ubuntuSeries))
 	for name, series := range ubuntuSeries {
 		ver, err := strconv.ParseFloat(series.Version, 10)
@@ -639,6 +1280,120 @@ func ubuntuSeriesSortedByVersion() []namedSeriesVersion {
 	return s
 }
 
+// supportedSeriesLists holds the results of the SupportedJuju* functions.
+type supportedSeriesLists struct {
+	controller []string
+	workload   []string
+	esm        []string
+	// future holds the series that aren't released yet.
+	future []string
+	// expires is the next release or end of life date of a series, when
+	// the lists change. It is zero if they never do.
+	expires time.Time
+}
+
+// validAt reports whether the lists computed earlier still hold at t.
+func (l *supportedSeriesLists) validAt(t time.Time) bool {
+	return l.expires.IsZero() || t.Before(l.expires)
+}
+
+// expireAt records that the lists computed at t change at the date, if
+// it's the earliest such date yet.
+func (l *supportedSeriesLists) expireAt(t, date time.Time) {
+	if date.IsZero() || date.Before(t) {
+		return
+	}
+	if l.expires.IsZero() || date.Before(l.expires) {
+		l.expires = date
+	}
+}
+
+// supportedJujuSeries caches the supported series lists, which are computed
+// together on first use. They are discarded by updateVersionSeries, and
+// recomputed once a series is released or reaches its end of life.
+var supportedJujuSeries *supportedSeriesLists
+
+// getSupportedSeriesLists returns the supported series lists, computing them
+// if they aren't cached. The returned lists must not be modified.
+func getSupportedSeriesLists() *supportedSeriesLists {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	return supportedSeriesListsLocked()
+}
+
+// supportedSeriesListsLocked is getSupportedSeriesLists for callers that
+// hold seriesVersionsMutex.
+func supportedSeriesListsLocked() *supportedSeriesLists {
+	today := defaultRegistry.today()
+	cached := supportedJujuSeries != nil && supportedJujuSeries.validAt(today)
+	countCache(cached)
+	if !cached {
+		supportedJujuSeries = supportedSeriesListsAt(today)
+	}
+	return supportedJujuSeries
+}
+
+// supportedSeriesListsAt computes the series lists as they were at t. The
+// caller must hold seriesVersionsMutex.
+func supportedSeriesListsAt(t time.Time) *supportedSeriesLists {
+	return supportedSeriesListsWithPolicy(t, defaultRegistry.futurePolicy)
+}
+
+// supportedSeriesListsWithPolicy computes the series lists as they were at
+// t under the future policy. The caller must hold seriesVersionsMutex.
+func supportedSeriesListsWithPolicy(t time.Time, policy FuturePolicy) *supportedSeriesLists {
+	lists := &supportedSeriesLists{}
+	for _, version := range ubuntuSeriesSortedByVersion() {
+		if listedWithPolicy(version.Name, version.SeriesVersion, t, policy) {
+			lists.controller = append(lists.controller, version.Name)
+		}
+		if version.SeriesVersion.futureAt(t) {
+			lists.future = append(lists.future, version.Name)
+		}
+		if version.SeriesVersion.ESMSupported {
+			lists.esm = append(lists.esm, version.Name)
+		}
+		lists.expireAt(t, version.SeriesVersion.Released)
+		lists.expireAt(t, version.SeriesVersion.EOL)
+	}
+
+	var other, otherFuture []string
+	for s, version := range nonUbuntuSeries {
+		if listedWithPolicy(s, version, t, policy) {
+			other = append(other, s)
+		}
+		if version.futureAt(t) {
+			otherFuture = append(otherFuture, s)
+		}
+		lists.expireAt(t, version.Released)
+		lists.expireAt(t, version.EOL)
+	}
+	sort.Strings(other)
+	sort.Strings(otherFuture)
+	lists.future = append(lists.future, otherFuture...)
+	lists.workload = make([]string, 0, len(lists.controller)+len(other))
+	lists.workload = append(lists.workload, lists.controller...)
+	lists.workload = append(lists.workload, other...)
+	return lists
+}
+
+// historicSeriesLists returns the series lists as they were at t.
+func historicSeriesLists(t time.Time) *supportedSeriesLists {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	return supportedSeriesListsAt(t.UTC())
+}
+
+// copyStrings returns a copy of s, so callers can't modify cached lists.
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
+
 // SupportedJujuControllerSeries returns a slice of juju supported series that
 // target a controller (bootstrapping).
 //
@@ -648,17 +1403,81 @@ func ubuntuSeriesSortedByVersion() []namedSeriesVersion {
 //   - xenial (16.04)
 //
 // Anything not supported is left out.
+//
+// The list can't report missing distro-info data, so callers that set the
+// registry to require it should use SupportedJujuControllerSeriesChecked.
 func SupportedJujuControllerSeries() []string {
-	s := ubuntuSeriesSortedByVersion()
+	return defaultRegistry.interceptList(LookupSupportedJujuControllerSeries, copyStrings(getSupportedSeriesLists().controller))
+}
 
-	var series []string
-	for _, version := range s {
-		if !version.SeriesVersion.Supported {
-			continue
+// SupportedJujuControllerSeriesChecked returns the series that
+// SupportedJujuControllerSeries returns, or an error satisfying
+// IsNoDistroInfoError if the registry requires distro-info data and there
+// is none.
+func SupportedJujuControllerSeriesChecked() ([]string, error) {
+	if err := CheckDistroInfo(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return SupportedJujuControllerSeries(), nil
+}
+
+// SupportedJujuWorkloadSeriesChecked returns the series that
+// SupportedJujuWorkloadSeries returns, or an error satisfying
+// IsNoDistroInfoError if the registry requires distro-info data and there
+// is none.
+func SupportedJujuWorkloadSeriesChecked() ([]string, error) {
+	if err := CheckDistroInfo(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return SupportedJujuWorkloadSeries(), nil
+}
+
+// CheckDistroInfo returns an error satisfying IsNoDistroInfoError if the
+// registry is set to require distro-info data, with SetRequireDistroInfo,
+// and none could be loaded.
+func CheckDistroInfo() error {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	return errors.Trace(defaultRegistry.checkDistroInfoLocked())
+}
+
+// SupportedJujuControllerSeriesAtLeast returns the series that
+// SupportedJujuControllerSeries returns, leaving out those with a version
+// older than minVersion, for example "18.04". This allows Juju versions
+// with different minimum controller series to share the same series data.
+// An error satisfying IsNoDistroInfoError is returned if the registry
+// requires distro-info data and there is none.
+func SupportedJujuControllerSeriesAtLeast(minVersion string) ([]string, error) {
+	min, ok := parseConstraintVersion(strings.TrimSpace(minVersion))
+	if !ok {
+		return nil, errors.NotValidf("minimum ubuntu version %q", minVersion)
+	}
+	result, err := controllerSeriesAtLeast(min)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return defaultRegistry.interceptList(LookupSupportedJujuControllerSeries, result), nil
+}
+
+// controllerSeriesAtLeast returns the supported controller series with a
+// version of at least min.
+func controllerSeriesAtLeast(min []int) ([]string, error) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	if err := defaultRegistry.checkDistroInfoLocked(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var result []string
+	for _, name := range supportedSeriesListsLocked().controller {
+		version, _ := parseConstraintVersion(seriesVersions[name])
+		if version != nil && compareVersions(version, min) >= 0 {
+			result = append(result, name)
 		}
-		series = append(series, version.Name)
 	}
-	return series
+	return result, nil
 }
 
 // SupportedJujuWorkloadSeries returns a slice of juju supported series that
@@ -679,24 +1498,7 @@ func SupportedJujuControllerSeries() []string {
 //
 // Anything not supported is left out.
 func SupportedJujuWorkloadSeries() []string {
-	var result []string
-	// Ensure that ubuntu series are first!
-	result = append(result, SupportedJujuControllerSeries()...)
-
-	// Then perform the surgery on the non ubuntu series.
-	seriesVersionsMutex.Lock()
-	defer seriesVersionsMutex.Unlock()
-	updateSeriesVersionsOnce()
-
-	var series []string
-	for s, version := range nonUbuntuSeries {
-		if !version.Supported {
-			continue
-		}
-		series = append(series, s)
-	}
-	sort.Strings(series)
-	return append(result, series...)
+	return defaultRegistry.interceptList(LookupSupportedJujuWorkloadSeries, copyStrings(getSupportedSeriesLists().workload))
 }
 
 // SupportedJujuSeries returns a slice of juju supported series that also
@@ -714,16 +1516,64 @@ func SupportedJujuSeries() []string {
 //
 // Anything not supported is left out.
 func ESMSupportedJujuSeries() []string {
-	s := ubuntuSeriesSortedByVersion()
+	return defaultRegistry.interceptList(LookupESMSupportedJujuSeries, copyStrings(getSupportedSeriesLists().esm))
+}
 
-	var series []string
-	for _, version := range s {
-		if !version.SeriesVersion.ESMSupported {
-			continue
-		}
-		series = append(series, version.Name)
+// SupportedSeriesOptions modifies the lists returned by
+// SupportedJujuControllerSeriesWithOptions and
+// SupportedJujuWorkloadSeriesWithOptions.
+type SupportedSeriesOptions struct {
+	// IncludeBeta adds the beta series, those only available in the daily
+	// image stream, whatever the future policy, for pre-release testing
+	// environments.
+	IncludeBeta bool
+}
+
+// supportedSeriesListsWithOptions returns the supported series lists
+// modified by the options. The returned lists must not be modified.
+func supportedSeriesListsWithOptions(opts SupportedSeriesOptions) *supportedSeriesLists {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	if !opts.IncludeBeta {
+		return supportedSeriesListsLocked()
 	}
-	return series
+	return supportedSeriesListsWithPolicy(defaultRegistry.today(), FutureInclude)
+}
+
+// SupportedJujuControllerSeriesWithOptions returns the series that
+// SupportedJujuControllerSeries returns, modified by the options.
+func SupportedJujuControllerSeriesWithOptions(opts SupportedSeriesOptions) []string {
+	return defaultRegistry.interceptList(LookupSupportedJujuControllerSeries, copyStrings(supportedSeriesListsWithOptions(opts).controller))
+}
+
+// SupportedJujuWorkloadSeriesWithOptions returns the series that
+// SupportedJujuWorkloadSeries returns, modified by the options.
+func SupportedJujuWorkloadSeriesWithOptions(opts SupportedSeriesOptions) []string {
+	return defaultRegistry.interceptList(LookupSupportedJujuWorkloadSeries, copyStrings(supportedSeriesListsWithOptions(opts).workload))
+}
+
+// SupportedJujuControllerSeriesAt returns the series that
+// SupportedJujuControllerSeries would have returned at t, so that audits
+// can reconstruct what was allowed when a model was deployed. Series with
+// distro-info release dates are judged by those dates; the support of
+// other series can't be dated, so their current status is used.
+func SupportedJujuControllerSeriesAt(t time.Time) []string {
+	return defaultRegistry.interceptList(LookupSupportedJujuControllerSeries, historicSeriesLists(t).controller)
+}
+
+// SupportedJujuWorkloadSeriesAt returns the series that
+// SupportedJujuWorkloadSeries would have returned at t, with the same
+// caveats as SupportedJujuControllerSeriesAt.
+func SupportedJujuWorkloadSeriesAt(t time.Time) []string {
+	return defaultRegistry.interceptList(LookupSupportedJujuWorkloadSeries, historicSeriesLists(t).workload)
+}
+
+// SupportedJujuSeriesAt returns the series that SupportedJujuSeries would
+// have returned at t, with the same caveats as
+// SupportedJujuControllerSeriesAt.
+func SupportedJujuSeriesAt(t time.Time) []string {
+	return SupportedJujuWorkloadSeriesAt(t)
 }
 
 // OSSupportedSeries returns the series of the specified OS on which we
@@ -745,6 +1595,7 @@ func OSSupportedSeries(os os.OSType) []string {
 func UpdateSeriesVersions() error {
 	seriesVersionsMutex.Lock()
 	defer seriesVersionsMutex.Unlock()
+	defer publishChanges(knownSeries())
 
 	err := updateLocalSeriesVersions()
 	if err != nil {
@@ -757,8 +1608,11 @@ func UpdateSeriesVersions() error {
 
 var updatedseriesVersions bool
 
+// updateSeriesVersionsOnce loads the local distro-info data, the first
+// time it's called. The caller must hold seriesVersionsMutex.
 func updateSeriesVersionsOnce() {
 	if !updatedseriesVersions {
+		defer publishChanges(knownSeries())
 		if err := updateLocalSeriesVersions(); err != nil {
 			logger.Warningf("failed to update distro info: %v", err)
 		}
@@ -4,6 +4,11 @@
 package series_test
 
 import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/juju/collections/set"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -39,7 +44,7 @@ var getOSFromSeriesTests = []struct {
 	want:   os.Windows,
 }, {
 	series: "mountainlion",
-	want:   os.OSX,
+	want:   os.MacOS,
 }, {
 	series: "centos7",
 	want:   os.CentOS,
@@ -49,12 +54,42 @@ var getOSFromSeriesTests = []struct {
 }, {
 	series: "kubernetes",
 	want:   os.Kubernetes,
+}, {
+	series: "openbsd7.4",
+	want:   os.OpenBSD,
+}, {
+	series: "netbsd10",
+	want:   os.NetBSD,
+}, {
+	series: "aix7.3",
+	want:   os.AIX,
+}, {
+	series: "ibmi7.5",
+	want:   os.AIX,
+}, {
+	series: "android14",
+	want:   os.Android,
+}, {
+	series: "bullseye",
+	want:   os.Debian,
+}, {
+	series: "fedora40",
+	want:   os.Fedora,
+}, {
+	series: "rhel9",
+	want:   os.RHEL,
+}, {
+	series: "rocky8",
+	want:   os.Rocky,
+}, {
+	series: "alma9",
+	want:   os.AlmaLinux,
 }, {
 	series: "genericlinux",
 	want:   os.GenericLinux,
 }, {
 	series: "",
-	err:    "series \"\" not valid",
+	err:    "empty series",
 },
 }
 
@@ -74,6 +109,59 @@ func (s *supportedSeriesSuite) TestUnknownOSFromSeries(c *gc.C) {
 	_, err := series.GetOSFromSeries("Xuanhuaceratops")
 	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
 	c.Assert(err, gc.ErrorMatches, `unknown OS for series: "Xuanhuaceratops"`)
+	c.Assert(series.SeriesSuggestions(err), gc.HasLen, 0)
+}
+
+func (s *supportedSeriesSuite) TestUnknownOSFromSeriesSuggestions(c *gc.C) {
+	_, err := series.GetOSFromSeries("bioinc")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+	c.Assert(err, gc.ErrorMatches, `unknown OS for series: "bioinc" \(did you mean "bionic"\?\)`)
+	c.Assert(series.SeriesSuggestions(err), jc.DeepEquals, []string{"bionic"})
+
+	_, err = series.CanonicalSeries("win201")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+	c.Assert(err, gc.ErrorMatches, `unknown OS for series: "win201" \(did you mean "win2012", "win2016", "win2019", "win10", "win11", "win2022" or "win81"\?\)`)
+}
+
+func (s *supportedSeriesSuite) TestSetSeriesVersionsRestoresConcurrently(c *gc.C) {
+	restore := series.SetSeriesVersions(map[string]string{"picard": "98.04"})
+	err := series.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "98.04"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The readers are running before the state is restored, so that the
+	// race detector sees them read concurrently with the restore.
+	var started, stopped sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		started.Add(1)
+		stopped.Add(1)
+		go func() {
+			defer stopped.Done()
+			first := true
+			for {
+				_, _ = series.GetOSFromSeries("picard")
+				_ = series.SupportedJujuSeries()
+				if first {
+					started.Done()
+					first = false
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+	started.Wait()
+	restore()
+	// Keep reading the restored state for a while.
+	time.Sleep(10 * time.Millisecond)
+	close(done)
+	stopped.Wait()
+
+	_, err = series.GetOSFromSeries("picard")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
 }
 
 func setSeriesTestData() {
@@ -110,27 +198,99 @@ func (s *supportedSeriesSuite) TestVersionSeriesValid(c *gc.C) {
 	c.Assert("trusty", gc.DeepEquals, seriesResult)
 }
 
-func (s *supportedSeriesSuite) TestVersionSeriesEmpty(c *gc.C) {
-	setSeriesTestData()
-	_, err := series.VersionSeries("")
-	c.Assert(err, gc.ErrorMatches, `.*unknown series for version: "".*`)
-}
-
 func (s *supportedSeriesSuite) TestVersionSeriesInvalid(c *gc.C) {
 	setSeriesTestData()
 	_, err := series.VersionSeries("73655")
 	c.Assert(err, gc.ErrorMatches, `.*unknown series for version: "73655".*`)
 }
 
-func (s *supportedSeriesSuite) TestSeriesVersionEmpty(c *gc.C) {
+func (s *supportedSeriesSuite) TestVersionSeriesAmbiguous(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+	for _, name := range []string{"riker", "picard"} {
+		err := series.RegisterSeries(series.Definition{Series: series.Name(name), OS: "genericlinux", Version: "99.1"})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	all, err := series.VersionSeriesAll("99.1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, []string{"picard", "riker"})
+
+	_, err = series.VersionSeries("99.1")
+	c.Assert(err, jc.Satisfies, series.IsAmbiguousVersionError)
+	c.Assert(err, gc.ErrorMatches, `version "99.1" is ambiguous, it is the version of series picard, riker`)
+
+	snapshot := series.CurrentSnapshot()
+	all, err = snapshot.VersionSeriesAll("99.1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, []string{"picard", "riker"})
+	_, err = snapshot.VersionSeries("99.1")
+	c.Assert(err, jc.Satisfies, series.IsAmbiguousVersionError)
+}
+
+func (s *supportedSeriesSuite) TestVersionSeriesAll(c *gc.C) {
 	setSeriesTestData()
-	_, err := series.SeriesVersion("")
-	c.Assert(err, gc.ErrorMatches, `.*unknown version for series: "".*`)
+	all, err := series.VersionSeriesAll("14.04")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, []string{"trusty"})
+
+	_, err = series.VersionSeriesAll("73655")
+	c.Assert(err, jc.Satisfies, series.IsUnknownVersionSeriesError)
 }
 
-func (s *supportedSeriesSuite) TestUbuntuSeriesVersionEmpty(c *gc.C) {
-	_, err := series.UbuntuSeriesVersion("")
-	c.Assert(err, gc.ErrorMatches, `.*unknown version for series: "".*`)
+func (s *supportedSeriesSuite) TestEmptyInput(c *gc.C) {
+	setSeriesTestData()
+	getOS := func(input string) (string, error) {
+		osType, err := series.GetOSFromSeries(input)
+		return osType.String(), err
+	}
+	canonical := func(input string) (string, error) {
+		name, err := series.CanonicalSeries(input)
+		return string(name), err
+	}
+	getSeries := func(input string) (string, error) {
+		info, err := series.GetSeries(input)
+		return string(info.Name), err
+	}
+	encode := func(input string) (string, error) {
+		_, err := series.EncodeSeries(input)
+		return "", err
+	}
+	source := func(input string) (string, error) {
+		_, err := series.SeriesSource(input)
+		return "", err
+	}
+	snapshot := series.CurrentSnapshot()
+	snapshotGetOS := func(input string) (string, error) {
+		osType, err := snapshot.GetOSFromSeries(input)
+		return osType.String(), err
+	}
+	for i, test := range []struct {
+		about string
+		f     func(string) (string, error)
+		err   string
+	}{
+		{"GetOSFromSeries", getOS, "empty series"},
+		{"SeriesVersion", series.SeriesVersion, "empty series"},
+		{"UbuntuSeriesVersion", series.UbuntuSeriesVersion, "empty series"},
+		{"VersionSeries", series.VersionSeries, "empty version"},
+		{"WindowsVersionSeries", series.WindowsVersionSeries, "empty version"},
+		{"CentOSVersionSeries", series.CentOSVersionSeries, "empty version"},
+		{"CanonicalSeries", canonical, "empty series"},
+		{"GetSeries", getSeries, "empty series"},
+		{"SeriesSource", source, "empty series"},
+		{"EncodeSeries", encode, "empty series"},
+		{"Snapshot.GetOSFromSeries", snapshotGetOS, "empty series"},
+		{"Snapshot.SeriesVersion", snapshot.SeriesVersion, "empty series"},
+		{"Snapshot.VersionSeries", snapshot.VersionSeries, "empty version"},
+	} {
+		for _, input := range []string{"", " \t\n"} {
+			c.Logf("test %d: %s(%q)", i, test.about, input)
+			_, err := test.f(input)
+			c.Check(err, jc.Satisfies, series.IsEmptyInputError)
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
 }
 
 func (s *supportedSeriesSuite) TestUbuntuSeriesVersion(c *gc.C) {
@@ -155,3 +315,98 @@ func (s *supportedSeriesSuite) TestUbuntuInvalidSeriesVersion(c *gc.C) {
 	_, err := series.UbuntuSeriesVersion("firewolf")
 	c.Assert(err, gc.ErrorMatches, `.*unknown version for series: "firewolf".*`)
 }
+
+func (s *supportedSeriesSuite) TestWriteDistroInfoCSV(c *gc.C) {
+	var buf bytes.Buffer
+	err := series.WriteDistroInfoCSV(&buf, os.CentOS)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `version,codename,series,created,release,eol
+centos7,Centos7,centos7,,2014-07-07,2024-06-30
+centos8,Centos8,centos8,,2019-09-24,2021-12-31
+centos8-stream,Centos8-Stream,centos8-stream,,2019-09-24,2024-05-31
+centos9,Centos9,centos9,,2021-12-03,2027-05-31
+`)
+}
+
+func (s *supportedSeriesSuite) TestSupportedJujuSeriesInvalidated(c *gc.C) {
+	restore := series.BackupSeriesState()
+	defer restore()
+
+	workload := series.SupportedJujuWorkloadSeries()
+	c.Assert(set.NewStrings(workload...).Contains("picard"), jc.IsFalse)
+	// Modifying the result doesn't affect later calls.
+	workload[0] = "picard"
+	c.Assert(set.NewStrings(series.SupportedJujuWorkloadSeries()...).Contains("picard"), jc.IsFalse)
+
+	err := series.RegisterSeries(series.Definition{
+		Series:       "picard",
+		OS:           "ubuntu",
+		Version:      "99.04",
+		Supported:    true,
+		ESMSupported: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(set.NewStrings(series.SupportedJujuControllerSeries()...).Contains("picard"), jc.IsTrue)
+	c.Assert(set.NewStrings(series.SupportedJujuWorkloadSeries()...).Contains("picard"), jc.IsTrue)
+	c.Assert(set.NewStrings(series.ESMSupportedJujuSeries()...).Contains("picard"), jc.IsTrue)
+}
+
+func (s *supportedSeriesSuite) TestLookupIgnoresCaseAndWhitespace(c *gc.C) {
+	osType, err := series.GetOSFromSeries("Bionic ")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(osType, gc.Equals, os.Ubuntu)
+
+	version, err := series.SeriesVersion(" BIONIC")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "18.04")
+
+	name, err := series.VersionSeries(" 18.04\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "bionic")
+
+	c.Assert(series.IsWindowsNano("Win2016Nano"), jc.IsTrue)
+
+	_, err = series.GetOSFromSeries("  ")
+	c.Assert(err, jc.Satisfies, series.IsEmptyInputError)
+}
+
+func (s *supportedSeriesSuite) TestCanonicalSeries(c *gc.C) {
+	name, err := series.CanonicalSeries("\tCentOS7 ")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, series.Name("centos7"))
+
+	_, err = series.CanonicalSeries("Firewolf")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *supportedSeriesSuite) TestFormatSeries(c *gc.C) {
+	c.Assert(series.FormatSeries(" Focal\n"), gc.Equals, "focal")
+	c.Assert(series.FormatSeries("centos8-stream"), gc.Equals, "centos8-stream")
+	c.Assert(series.FormatSeries(""), gc.Equals, "")
+}
+
+func (s *supportedSeriesSuite) TestMustVariants(c *gc.C) {
+	c.Assert(series.MustOSFromSeries("bionic"), gc.Equals, os.Ubuntu)
+	c.Assert(series.MustSeriesVersion("bionic"), gc.Equals, "18.04")
+	c.Assert(series.MustVersionSeries("18.04"), gc.Equals, "bionic")
+
+	c.Assert(func() { series.MustOSFromSeries("firewolf") }, gc.PanicMatches, `osVersion reported an error: unknown OS for series: "firewolf"`)
+	c.Assert(func() { series.MustSeriesVersion("firewolf") }, gc.PanicMatches, `SeriesVersion reported an error: unknown version for series: "firewolf"`)
+	c.Assert(func() { series.MustVersionSeries("0.0") }, gc.PanicMatches, `VersionSeries reported an error: unknown series for version: "0.0"`)
+}
+
+func (s *supportedSeriesSuite) TestWindowsSeriesVersions(c *gc.C) {
+	versions := series.WindowsSeriesVersions()
+	c.Assert(versions["win2019"], gc.Equals, "win2019")
+	c.Assert(versions["win2016nano"], gc.Equals, "win2016nano")
+	c.Assert(versions["win2012hvr2"], gc.Equals, "win2012hvr2")
+	for name := range versions {
+		osType, err := series.GetOSFromSeries(name)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(osType, gc.Equals, os.Windows)
+	}
+
+	// The result is a copy.
+	delete(versions, "win2019")
+	c.Assert(series.WindowsSeriesVersions()["win2019"], gc.Equals, "win2019")
+}
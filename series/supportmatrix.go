@@ -0,0 +1,85 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// SupportMatrixEntry describes how Juju supports a single series. The JSON
+// encoding is intended for generating documentation, so that published
+// support tables are produced from the same data the code uses.
+type SupportMatrixEntry struct {
+	Series Name `json:"series"`
+	// OS is the lowercase operating system name, as used in definitions.
+	OS      string `json:"os"`
+	Version string `json:"version"`
+	LTS     bool   `json:"lts"`
+	// Controller, Workload and ESM report whether the series is in
+	// SupportedJujuControllerSeries, SupportedJujuWorkloadSeries and
+	// ESMSupportedJujuSeries respectively.
+	Controller bool `json:"controller"`
+	Workload   bool `json:"workload"`
+	ESM        bool `json:"esm"`
+	// EOL is the end of life date of the series formatted as YYYY-MM-DD,
+	// or empty if it isn't known.
+	EOL string `json:"eol,omitempty"`
+}
+
+// SupportMatrix returns the support matrix of every currently known series.
+func SupportMatrix() []SupportMatrixEntry {
+	return CurrentSnapshot().SupportMatrix()
+}
+
+// WriteSupportMatrixJSON writes the support matrix of every known series to
+// w as a JSON array.
+func WriteSupportMatrixJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.Trace(encoder.Encode(SupportMatrix()))
+}
+
+// SupportMatrix returns the support matrix of every series in the snapshot,
+// ordered by operating system, then version, then series name.
+func (s Snapshot) SupportMatrix() []SupportMatrixEntry {
+	data := s.get()
+	controller := set.NewStrings(data.supported.controller...)
+	workload := set.NewStrings(data.supported.workload...)
+	esm := set.NewStrings(data.supported.esm...)
+
+	entries := make([]SupportMatrixEntry, 0, len(data.series))
+	for name, record := range data.series {
+		if record.OS == os.Unknown {
+			continue
+		}
+		entries = append(entries, SupportMatrixEntry{
+			Series:     Name(name),
+			OS:         os.FormatOSType(record.OS),
+			Version:    strings.TrimSuffix(record.Version, " LTS"),
+			LTS:        record.LTS,
+			Controller: controller.Contains(name),
+			Workload:   workload.Contains(name),
+			ESM:        esm.Contains(name),
+			EOL:        formatDistroInfoDate(record.EOL),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.OS != b.OS {
+			return a.OS < b.OS
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Series < b.Series
+	})
+	return entries
+}
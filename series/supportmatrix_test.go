@@ -0,0 +1,147 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type supportMatrixSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&supportMatrixSuite{})
+
+func (s *supportMatrixSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+	cleanup := series.SetSeriesVersions(make(map[string]string))
+	s.AddCleanup(func(*gc.C) { cleanup() })
+}
+
+func (s *supportMatrixSuite) matrixEntry(c *gc.C, name series.Name) series.SupportMatrixEntry {
+	for _, entry := range series.SupportMatrix() {
+		if entry.Series == name {
+			return entry
+		}
+	}
+	c.Fatalf("series %q not in support matrix", name)
+	return series.SupportMatrixEntry{}
+}
+
+func (s *supportMatrixSuite) TestSupportMatrix(c *gc.C) {
+	err := series.RegisterSeries(series.Definition{
+		Series:       "picard",
+		OS:           "ubuntu",
+		Version:      "99.04",
+		LTS:          true,
+		Supported:    true,
+		ESMSupported: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.RegisterSeries(series.Definition{
+		Series:    "riker",
+		OS:        "centos",
+		Version:   "centos99",
+		Supported: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.matrixEntry(c, "picard"), jc.DeepEquals, series.SupportMatrixEntry{
+		Series:     "picard",
+		OS:         "ubuntu",
+		Version:    "99.04",
+		LTS:        true,
+		Controller: true,
+		Workload:   true,
+		ESM:        true,
+	})
+	c.Assert(s.matrixEntry(c, "riker"), jc.DeepEquals, series.SupportMatrixEntry{
+		Series:   "riker",
+		OS:       "centos",
+		Version:  "centos99",
+		Workload: true,
+	})
+}
+
+func (s *supportMatrixSuite) TestSupportMatrixMatchesSupportedSeries(c *gc.C) {
+	var controller, workload []string
+	for _, entry := range series.SupportMatrix() {
+		if entry.Controller {
+			controller = append(controller, entry.Series.String())
+		}
+		if entry.Workload {
+			workload = append(workload, entry.Series.String())
+		}
+	}
+	c.Assert(controller, jc.SameContents, series.SupportedJujuControllerSeries())
+	c.Assert(workload, jc.SameContents, series.SupportedJujuWorkloadSeries())
+}
+
+func (s *supportMatrixSuite) TestWriteSupportMatrixJSON(c *gc.C) {
+	var buf bytes.Buffer
+	err := series.WriteSupportMatrixJSON(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var entries []series.SupportMatrixEntry
+	err = json.Unmarshal(buf.Bytes(), &entries)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, jc.DeepEquals, series.SupportMatrix())
+}
+
+func (s *supportMatrixSuite) TestZeroSnapshotSupportMatrix(c *gc.C) {
+	var snapshot series.Snapshot
+	c.Assert(snapshot.SupportMatrix(), gc.HasLen, 0)
+}
+
+func (s *supportMatrixSuite) TestByTier(c *gc.C) {
+	for _, def := range []series.Definition{{
+		Series:       "picard",
+		OS:           "ubuntu",
+		Version:      "99.04",
+		Supported:    true,
+		ESMSupported: true,
+	}, {
+		Series:       "riker",
+		OS:           "ubuntu",
+		Version:      "98.04",
+		ESMSupported: true,
+	}, {
+		Series:    "worf",
+		OS:        "centos",
+		Version:   "worf",
+		Supported: true,
+	}, {
+		Series:  "troi",
+		OS:      "centos",
+		Version: "troi",
+	}, {
+		Series:  "data",
+		OS:      "ubuntu",
+		Version: "97.04",
+	}} {
+		c.Assert(series.RegisterSeries(def), jc.ErrorIsNil)
+	}
+
+	tiers := series.ByTier()
+	contains := func(tier []string, name string) bool {
+		return set.NewStrings(tier...).Contains(name)
+	}
+	c.Check(contains(tiers.Controller, "picard"), jc.IsTrue)
+	c.Check(contains(tiers.ESM, "picard"), jc.IsFalse)
+	c.Check(contains(tiers.ESM, "riker"), jc.IsTrue)
+	c.Check(contains(tiers.WorkloadOnly, "worf"), jc.IsTrue)
+	c.Check(contains(tiers.Deprecated, "troi"), jc.IsTrue)
+	c.Check(contains(tiers.UnknownToDistroInfo, "data"), jc.IsTrue)
+	c.Check(tiers.Controller[0], gc.Equals, "picard")
+}
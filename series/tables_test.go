@@ -0,0 +1,46 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+)
+
+// tablesSuite benchmarks building the embedded series tables. Run with
+// -check.b -check.bmem to see their memory cost.
+type tablesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&tablesSuite{})
+
+func (s *tablesSuite) TestWindowsTablesLazy(c *gc.C) {
+	c.Assert(windowsVersionTable()["Windows Server 2019"], gc.Equals, "win2019")
+	c.Assert(windowsNanoVersionTable()["Windows Server 2016"], gc.Equals, "win2016nano")
+	c.Assert(macOSXSeriesTable()[19], gc.Equals, "catalina")
+}
+
+func (s *tablesSuite) BenchmarkWindowsTables(c *gc.C) {
+	for i := 0; i < c.N; i++ {
+		_ = embeddedWindowsVersions()
+		_ = embeddedWindowsNanoVersions()
+	}
+}
+
+func (s *tablesSuite) BenchmarkVersionSeries(c *gc.C) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	for i := 0; i < c.N; i++ {
+		_ = reverseSeriesVersion()
+	}
+}
+
+func (s *tablesSuite) BenchmarkKnownSeries(c *gc.C) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	for i := 0; i < c.N; i++ {
+		_ = knownSeries()
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+)
+
+// validTag matches the tags that can be attached to series, for example
+// "fips-capable".
+var validTag = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*$`)
+
+// seriesTags holds the tags attached to each series, starting with the
+// image tags of the built in series. It is guarded by seriesVersionsMutex.
+var seriesTags = defaultSeriesTags()
+
+// AddSeriesTags attaches tags, such as "fips-capable" or "minimal-image",
+// to a known series, so that policies can select series by tag rather
+// than by listing them.
+func AddSeriesTags(series string, tags ...string) error {
+	if err := validateTags(tags); err != nil {
+		return errors.Trace(err)
+	}
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	existing := seriesTags[string(name)]
+	setSeriesTags(string(name), existing.Union(set.NewStrings(tags...)).Values())
+	return nil
+}
+
+// RemoveSeriesTags detaches tags from a known series. Tags the series
+// doesn't have are ignored.
+func RemoveSeriesTags(series string, tags ...string) error {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	existing := seriesTags[string(name)]
+	setSeriesTags(string(name), existing.Difference(set.NewStrings(tags...)).Values())
+	return nil
+}
+
+// SeriesTags returns the tags attached to a known series, sorted.
+func SeriesTags(series string) ([]string, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	return seriesTags[string(name)].SortedValues(), nil
+}
+
+// SeriesWithTags returns the series that have all of the tags, sorted.
+func SeriesWithTags(tags ...string) []string {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+	updateSeriesVersionsOnce()
+	var result []string
+	for series, attached := range seriesTags {
+		if set.NewStrings(tags...).Difference(attached).IsEmpty() {
+			result = append(result, series)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// validateTags returns an error if any of the tags isn't valid.
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if !validTag.MatchString(tag) {
+			return errors.NotValidf("series tag %q", tag)
+		}
+	}
+	return nil
+}
+
+// setSeriesTags replaces the tags attached to the series. The caller must
+// hold seriesVersionsMutex.
+func setSeriesTags(series string, tags []string) {
+	if len(tags) == 0 {
+		delete(seriesTags, series)
+		return
+	}
+	seriesTags[series] = set.NewStrings(tags...)
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type tagsSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&tagsSuite{})
+
+func (s *tagsSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *tagsSuite) TestAddSeriesTags(c *gc.C) {
+	err := series.AddSeriesTags("focal", "fips-capable", "minimal-image")
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.AddSeriesTags("Jammy", "minimal-image")
+	c.Assert(err, jc.ErrorIsNil)
+
+	imageTags := []string{"images-amd64", "images-arm64", "images-ppc64el", "images-s390x"}
+	tags, err := series.SeriesTags("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, append([]string{"fips-capable"}, append(imageTags, "minimal-image")...))
+	c.Assert(series.SeriesWithTags("minimal-image"), jc.DeepEquals, []string{"focal", "jammy"})
+	c.Assert(series.SeriesWithTags("minimal-image", "fips-capable"), jc.DeepEquals, []string{"focal"})
+	c.Assert(series.SeriesWithTags("arm64-available"), gc.HasLen, 0)
+
+	focal, err := series.GetSeries("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(focal.Tags, jc.DeepEquals, tags)
+}
+
+func (s *tagsSuite) TestRemoveSeriesTags(c *gc.C) {
+	err := series.AddSeriesTags("focal", "fips-capable", "minimal-image")
+	c.Assert(err, jc.ErrorIsNil)
+	err = series.RemoveSeriesTags("focal", "fips-capable", "arm64-available")
+	c.Assert(err, jc.ErrorIsNil)
+
+	tags, err := series.SeriesTags("focal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, []string{"images-amd64", "images-arm64", "images-ppc64el", "images-s390x", "minimal-image"})
+	c.Assert(series.SeriesWithTags("fips-capable"), gc.HasLen, 0)
+}
+
+func (s *tagsSuite) TestAddSeriesTagsInvalid(c *gc.C) {
+	err := series.AddSeriesTags("focal", "FIPS capable")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = series.AddSeriesTags("sulu", "fips-capable")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *tagsSuite) TestDefinitionTags(c *gc.C) {
+	err := series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04", "tags": ["arm64-available"]}
+	]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(series.SeriesWithTags("arm64-available"), jc.DeepEquals, []string{"picard"})
+
+	err = series.LoadDefinitions(strings.NewReader(`{"schema": 1, "series": [
+		{"series": "picard", "os": "ubuntu", "version": "97.04", "tags": ["Bad Tag"]}
+	]}`))
+	c.Assert(err, gc.ErrorMatches, `series\[0\] \("picard"\): tags "Bad Tag" must match .*`)
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/os"
+)
+
+// SupportTiers groups the known series by how Juju supports them. Unlike
+// the lists returned by SupportedJujuControllerSeries,
+// SupportedJujuWorkloadSeries and ESMSupportedJujuSeries, every series is
+// in exactly one tier: the first of Future, Controller, WorkloadOnly, ESM,
+// Deprecated and UnknownToDistroInfo that applies to it.
+//
+// Within each tier, ubuntu series are listed first, newest release first,
+// followed by the other series sorted by name.
+type SupportTiers struct {
+	// Future holds the series that aren't released yet, which are only
+	// in the supported series lists under the FutureInclude policy.
+	Future []string
+	// Controller holds the series supported for both controllers and
+	// workloads.
+	Controller []string
+	// WorkloadOnly holds the series supported for workloads but not for
+	// controllers.
+	WorkloadOnly []string
+	// ESM holds the ubuntu series that are only covered by extended
+	// security maintenance.
+	ESM []string
+	// Deprecated holds the series that were once supported and no longer
+	// are.
+	Deprecated []string
+	// UnknownToDistroInfo holds the unsupported ubuntu series that have no
+	// release information from distro-info, so their support can't be
+	// determined.
+	UnknownToDistroInfo []string
+}
+
+// ByTier returns the currently known series grouped by support tier.
+func ByTier() SupportTiers {
+	return CurrentSnapshot().ByTier()
+}
+
+// ByTier returns the series in the snapshot grouped by support tier.
+func (s Snapshot) ByTier() SupportTiers {
+	data := s.get()
+	controller := set.NewStrings(data.supported.controller...)
+	workload := set.NewStrings(data.supported.workload...)
+	esm := set.NewStrings(data.supported.esm...)
+	future := set.NewStrings(data.supported.future...)
+
+	var tiers SupportTiers
+	for name, record := range data.series {
+		switch {
+		case record.OS == os.Unknown:
+		case future.Contains(name):
+			tiers.Future = append(tiers.Future, name)
+		case controller.Contains(name):
+			tiers.Controller = append(tiers.Controller, name)
+		case workload.Contains(name):
+			tiers.WorkloadOnly = append(tiers.WorkloadOnly, name)
+		case esm.Contains(name):
+			tiers.ESM = append(tiers.ESM, name)
+		case record.OS == os.Ubuntu && record.Released.IsZero():
+			tiers.UnknownToDistroInfo = append(tiers.UnknownToDistroInfo, name)
+		default:
+			tiers.Deprecated = append(tiers.Deprecated, name)
+		}
+	}
+	for _, tier := range [][]string{
+		tiers.Future,
+		tiers.Controller,
+		tiers.WorkloadOnly,
+		tiers.ESM,
+		tiers.Deprecated,
+		tiers.UnknownToDistroInfo,
+	} {
+		sortTier(tier, data.series)
+	}
+	return tiers
+}
+
+// sortTier sorts the series of a tier, putting ubuntu series first, newest
+// release first, followed by the other series sorted by name.
+func sortTier(tier []string, known map[string]seriesRecord) {
+	sort.Slice(tier, func(i, j int) bool {
+		a, b := known[tier[i]], known[tier[j]]
+		if (a.OS == os.Ubuntu) != (b.OS == os.Ubuntu) {
+			return a.OS == os.Ubuntu
+		}
+		if a.OS == os.Ubuntu {
+			va, _ := parseConstraintVersion(strings.TrimSuffix(a.Version, " LTS"))
+			vb, _ := parseConstraintVersion(strings.TrimSuffix(b.Version, " LTS"))
+			if cmp := compareVersions(va, vb); cmp != 0 {
+				return cmp > 0
+			}
+		}
+		return tier[i] < tier[j]
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+)
+
+// Tx changes the series data within a call to Registry.Update. A Tx must
+// not be used once Update returns.
+type Tx struct {
+	done bool
+}
+
+// Update calls fn to make a sequence of changes to the series data, which
+// are applied atomically: readers see either the data from before the
+// update or the data with every change made, never a mix. If fn returns an
+// error or panics, all of its changes are undone. Subscribers are notified
+// once, of the combined changes, when fn succeeds.
+//
+// fn must make its changes through the Tx, and must not call other
+// functions in this package, as the series data is locked while it runs.
+func (r *Registry) Update(fn func(tx *Tx) error) error {
+	return errors.Trace(update(func() error {
+		tx := &Tx{}
+		defer func() { tx.done = true }()
+		return fn(tx)
+	}))
+}
+
+// update calls fn with seriesVersionsMutex held, undoing any changes it
+// made to the series state if it fails, and notifying subscribers if it
+// succeeds.
+func update(fn func() error) (err error) {
+	seriesVersionsMutex.Lock()
+	defer seriesVersionsMutex.Unlock()
+
+	updateSeriesVersionsOnce()
+	before := knownSeries()
+	backup := backupSeriesState()
+	committed := false
+	defer func() {
+		if !committed {
+			backup.restore()
+			return
+		}
+		publishChanges(before)
+	}()
+	if err := fn(); err != nil {
+		return errors.Trace(err)
+	}
+	committed = true
+	return nil
+}
+
+// check returns an error if the Tx is used after its update has finished.
+func (tx *Tx) check() error {
+	if tx.done {
+		return errors.New("series transaction already finished")
+	}
+	return nil
+}
+
+// RegisterSeries adds or replaces a series, as the package level
+// RegisterSeries does.
+func (tx *Tx) RegisterSeries(def Definition) error {
+	if err := tx.check(); err != nil {
+		return errors.Trace(err)
+	}
+	if problems := validateDefinition(0, def); len(problems) > 0 {
+		return errors.Trace(problems)
+	}
+	return errors.Trace(applyDefinitions([]Definition{def}, SourceRuntime))
+}
+
+// RemoveSeries removes a series that was loaded from distro-info, a
+// definitions file or registered at runtime, along with its resources and
+// tags. Ubuntu series are removable whatever their source. Other series
+// compiled into the package can't be removed, and an error satisfying
+// errors.IsNotSupported is returned for them.
+func (tx *Tx) RemoveSeries(series string) error {
+	if err := tx.check(); err != nil {
+		return errors.Trace(err)
+	}
+	name := FormatSeries(series)
+	_, isUbuntu := ubuntuSeries[name]
+	_, isDefined := definedSeriesOS[name]
+	if !isUbuntu && !isDefined {
+		osType, err := getOSFromSeries(name)
+		if err != nil {
+			return errors.Trace(unknownSeries(series, knownSeries()))
+		}
+		return errors.NotSupportedf("removing the built in %s series %q", osType, name)
+	}
+	delete(ubuntuSeries, name)
+	delete(nonUbuntuSeries, name)
+	delete(seriesVersions, name)
+	delete(definedSeriesOS, name)
+	delete(seriesSources, name)
+	delete(seriesResources, name)
+	delete(seriesTags, name)
+	updateVersionSeries()
+	latestLtsSeries = ""
+	return nil
+}
+
+// SetSupported overrides whether a known series is supported. The
+// override takes precedence over the release and end of life dates of the
+// series, and over later definitions files and distro-info updates, as a
+// registered series does.
+func (tx *Tx) SetSupported(series string, supported bool) error {
+	if err := tx.check(); err != nil {
+		return errors.Trace(err)
+	}
+	name := FormatSeries(series)
+	if version, ok := ubuntuSeries[name]; ok {
+		version.Supported = supported
+		version.SupportOverridden = true
+		ubuntuSeries[name] = version
+	} else if version, ok := nonUbuntuSeries[name]; ok {
+		version.Supported = supported
+		version.SupportOverridden = true
+		nonUbuntuSeries[name] = version
+	} else {
+		return errors.Trace(unknownSeries(series, knownSeries()))
+	}
+	seriesSources[name] = SourceRuntime
+	updateVersionSeries()
+	latestLtsSeries = ""
+	return nil
+}
+
+// SetResources sets the recommended resources of a known series, as
+// RegisterSeriesResources does.
+func (tx *Tx) SetResources(series string, resources Resources) error {
+	if err := tx.check(); err != nil {
+		return errors.Trace(err)
+	}
+	name := FormatSeries(series)
+	if _, err := getOSFromSeries(name); err != nil {
+		return errors.Trace(unknownSeries(series, knownSeries()))
+	}
+	setSeriesResources(name, resources)
+	return nil
+}
+
+// SetTags replaces the tags attached to a known series.
+func (tx *Tx) SetTags(series string, tags ...string) error {
+	if err := tx.check(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateTags(tags); err != nil {
+		return errors.Trace(err)
+	}
+	name := FormatSeries(series)
+	if _, err := getOSFromSeries(name); err != nil {
+		return errors.Trace(unknownSeries(series, knownSeries()))
+	}
+	setSeriesTags(name, tags)
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type txSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&txSuite{})
+
+func (s *txSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *txSuite) TestUpdate(c *gc.C) {
+	// Load the series data first, so its changes aren't reported.
+	series.CurrentSnapshot()
+	ch := make(chan series.ChangeSet, 10)
+	unsubscribe := series.Subscribe(func(changes series.ChangeSet) { ch <- changes })
+	defer unsubscribe()
+
+	err := series.DefaultRegistry().Update(func(tx *series.Tx) error {
+		if err := tx.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"}); err != nil {
+			return err
+		}
+		if err := tx.RegisterSeries(series.Definition{Series: "riker", OS: "ubuntu", Version: "97.10"}); err != nil {
+			return err
+		}
+		if err := tx.SetSupported("picard", true); err != nil {
+			return err
+		}
+		if err := tx.SetTags("picard", "fips-capable"); err != nil {
+			return err
+		}
+		return tx.RemoveSeries("riker")
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(set.NewStrings(series.SupportedSeries()...).Contains("picard"), jc.IsTrue)
+	_, err = series.GetOSFromSeries("riker")
+	c.Assert(err, gc.NotNil)
+	tags, err := series.SeriesTags("picard")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, []string{"fips-capable"})
+
+	select {
+	case changes := <-ch:
+		c.Assert(changes, jc.DeepEquals, series.ChangeSet{Added: []series.Name{"picard"}})
+	case <-time.After(10 * time.Second):
+		c.Fatalf("timed out waiting for change set")
+	}
+}
+
+func (s *txSuite) TestUpdateRollsBack(c *gc.C) {
+	before := series.CurrentSnapshot().Series()
+
+	err := series.DefaultRegistry().Update(func(tx *series.Tx) error {
+		if err := tx.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"}); err != nil {
+			return err
+		}
+		if err := tx.RemoveSeries("bionic"); err != nil {
+			return err
+		}
+		return errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	c.Assert(series.CurrentSnapshot().Series(), jc.DeepEquals, before)
+	_, err = series.GetOSFromSeries("picard")
+	c.Assert(err, gc.NotNil)
+	_, err = series.GetOSFromSeries("bionic")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *txSuite) TestUpdateRollsBackOnPanic(c *gc.C) {
+	c.Assert(func() {
+		series.DefaultRegistry().Update(func(tx *series.Tx) error {
+			tx.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"})
+			panic("boom")
+		})
+	}, gc.PanicMatches, "boom")
+
+	_, err := series.GetOSFromSeries("picard")
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *txSuite) TestRemoveBuiltInSeries(c *gc.C) {
+	err := series.DefaultRegistry().Update(func(tx *series.Tx) error {
+		return tx.RemoveSeries("centos7")
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	err = series.DefaultRegistry().Update(func(tx *series.Tx) error {
+		return tx.RemoveSeries("sulu")
+	})
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *txSuite) TestUpdateWithConcurrentReaders(c *gc.C) {
+	// Readers of known series must not race with updates that register
+	// and remove series, which the race detector checks.
+	var started, stopped sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		started.Add(1)
+		stopped.Add(1)
+		go func() {
+			defer stopped.Done()
+			first := true
+			for {
+				_, _ = series.GetOSFromSeries("focal")
+				_, _ = series.GetOSFromSeries("picard")
+				if first {
+					started.Done()
+					first = false
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+	started.Wait()
+	for i := 0; i < 20; i++ {
+		err := series.DefaultRegistry().Update(func(tx *series.Tx) error {
+			return tx.RegisterSeries(series.Definition{Series: "picard", OS: "ubuntu", Version: "97.04"})
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = series.DefaultRegistry().Update(func(tx *series.Tx) error {
+			return tx.RemoveSeries("picard")
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	close(done)
+	stopped.Wait()
+}
+
+func (s *txSuite) TestSetSupportedOverridesDates(c *gc.C) {
+	series.DefaultRegistry().SetClock(func() time.Time {
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+	c.Assert(set.NewStrings(series.SupportedJujuWorkloadSeries()...).Contains("rhel9"), jc.IsTrue)
+
+	err := series.DefaultRegistry().Update(func(tx *series.Tx) error {
+		if err := tx.SetSupported("rhel9", false); err != nil {
+			return err
+		}
+		// stretch reached its end of life in 2022.
+		return tx.SetSupported("stretch", true)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	workload := set.NewStrings(series.SupportedJujuWorkloadSeries()...)
+	c.Check(workload.Contains("rhel9"), jc.IsFalse)
+	c.Check(workload.Contains("stretch"), jc.IsTrue)
+	supported := set.NewStrings()
+	for _, s := range series.Filter(series.SupportedAt(series.DefaultRegistry().Now())) {
+		supported.Add(s.Name.String())
+	}
+	c.Check(supported.Contains("rhel9"), jc.IsFalse)
+	c.Check(supported.Contains("stretch"), jc.IsTrue)
+}
+
+func (s *txSuite) TestTxUnusableAfterUpdate(c *gc.C) {
+	var saved *series.Tx
+	err := series.DefaultRegistry().Update(func(tx *series.Tx) error {
+		saved = tx
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = saved.SetSupported("bionic", false)
+	c.Assert(err, gc.ErrorMatches, "series transaction already finished")
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/juju/errors"
+)
+
+// Verifier checks that fetched series data is authentic before it is
+// applied, using a detached signature fetched alongside the data.
+type Verifier interface {
+	// Verify returns an error if signature isn't valid for data.
+	Verify(data, signature []byte) error
+}
+
+// SignatureVerifier verifies detached ed25519 signatures, in either raw or
+// base64 encoded form, against a set of trusted public keys.
+type SignatureVerifier struct {
+	Keys []ed25519.PublicKey
+}
+
+// Verify is part of the Verifier interface.
+func (v SignatureVerifier) Verify(data, signature []byte) error {
+	sig := signature
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig)))
+		if err != nil {
+			return errors.NotValidf("signature encoding")
+		}
+		sig = decoded
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return errors.NotValidf("signature of %d bytes", len(sig))
+	}
+	for _, key := range v.Keys {
+		if len(key) == ed25519.PublicKeySize && ed25519.Verify(key, data, sig) {
+			return nil
+		}
+	}
+	return errors.Unauthorizedf("signature does not match any trusted key")
+}
+
+// HMACVerifier verifies hex encoded HMAC-SHA256 checksums against a set of
+// trusted shared keys.
+type HMACVerifier struct {
+	Keys [][]byte
+}
+
+// Verify is part of the Verifier interface.
+func (v HMACVerifier) Verify(data, signature []byte) error {
+	sum, err := hex.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return errors.NotValidf("checksum encoding")
+	}
+	for _, key := range v.Keys {
+		mac := hmac.New(sha256.New, key)
+		_, _ = mac.Write(data)
+		if hmac.Equal(sum, mac.Sum(nil)) {
+			return nil
+		}
+	}
+	return errors.Unauthorizedf("checksum does not match any trusted key")
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os/series"
+)
+
+type verifySuite struct{}
+
+var _ = gc.Suite(&verifySuite{})
+
+func (*verifySuite) TestSignatureVerifier(c *gc.C) {
+	public, private, err := ed25519.GenerateKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	otherPublic, _, err := ed25519.GenerateKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := []byte(definitionsData)
+	sig := ed25519.Sign(private, data)
+
+	verifier := series.SignatureVerifier{Keys: []ed25519.PublicKey{otherPublic, public}}
+	c.Check(verifier.Verify(data, sig), jc.ErrorIsNil)
+	c.Check(verifier.Verify(data, []byte(base64.StdEncoding.EncodeToString(sig)+"\n")), jc.ErrorIsNil)
+	c.Check(verifier.Verify([]byte("tampered"), sig), gc.ErrorMatches, "signature does not match any trusted key")
+	c.Check(verifier.Verify(data, []byte("!!")), gc.ErrorMatches, "signature encoding not valid")
+
+	verifier = series.SignatureVerifier{Keys: []ed25519.PublicKey{otherPublic}}
+	c.Check(verifier.Verify(data, sig), gc.ErrorMatches, "signature does not match any trusted key")
+}
+
+func (*verifySuite) TestHMACVerifier(c *gc.C) {
+	key := []byte("secret")
+	data := []byte(definitionsData)
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(data)
+	sum := hex.EncodeToString(mac.Sum(nil))
+
+	verifier := series.HMACVerifier{Keys: [][]byte{[]byte("other"), key}}
+	c.Check(verifier.Verify(data, []byte(sum+"\n")), jc.ErrorIsNil)
+	c.Check(verifier.Verify([]byte("tampered"), []byte(sum)), gc.ErrorMatches, "checksum does not match any trusted key")
+	c.Check(verifier.Verify(data, []byte("zz")), gc.ErrorMatches, "checksum encoding not valid")
+}
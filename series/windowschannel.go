@@ -0,0 +1,68 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/os"
+)
+
+// WindowsChannel identifies the servicing channel of a Windows series,
+// which determines its lifecycle.
+type WindowsChannel string
+
+const (
+	// WindowsLTSC is the Long-Term Servicing Channel of Windows Server,
+	// whose releases are named after their year, such as Windows Server
+	// 2019, and are supported for ten years.
+	WindowsLTSC WindowsChannel = "ltsc"
+	// WindowsAnnualChannel is the Annual Channel of Windows Server for
+	// container hosts, whose releases are named by version, such as
+	// Windows Server, version 23H2. They are only installed as Server
+	// Core container hosts, and are supported for two years.
+	WindowsAnnualChannel WindowsChannel = "annual"
+	// WindowsClient is the channel of the desktop releases of Windows.
+	WindowsClient WindowsChannel = "client"
+)
+
+// windowsAnnualChannelSeries maps the Annual Channel releases of Windows
+// Server, by version, to their series.
+var windowsAnnualChannelSeries = map[string]string{
+	"23h2": "win23h2",
+}
+
+// windowsClientSeries holds the series of the desktop releases of Windows.
+var windowsClientSeries = map[string]bool{
+	"win7":  true,
+	"win8":  true,
+	"win81": true,
+	"win10": true,
+	"win11": true,
+}
+
+// WindowsServicingChannel returns the servicing channel of a known
+// Windows series. An error satisfying errors.IsNotSupported is returned
+// for series of other operating systems.
+func WindowsServicingChannel(series string) (WindowsChannel, error) {
+	name, err := canonicalSeries(series)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	osType, err := GetOSFromSeries(string(name))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if osType != os.Windows {
+		return "", errors.NotSupportedf("servicing channel of %s series %q", osType, name)
+	}
+	if windowsClientSeries[string(name)] {
+		return WindowsClient, nil
+	}
+	for _, annual := range windowsAnnualChannelSeries {
+		if annual == string(name) {
+			return WindowsAnnualChannel, nil
+		}
+	}
+	return WindowsLTSC, nil
+}
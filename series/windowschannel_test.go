@@ -0,0 +1,71 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series_test
+
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+)
+
+type windowsChannelSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&windowsChannelSuite{})
+
+func (s *windowsChannelSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	restore := series.BackupSeriesState()
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *windowsChannelSuite) TestWindowsServicingChannel(c *gc.C) {
+	for _, test := range []struct {
+		series  string
+		channel series.WindowsChannel
+	}{
+		{"win2019", series.WindowsLTSC},
+		{"win2012hvr2", series.WindowsLTSC},
+		{"win23h2", series.WindowsAnnualChannel},
+		{"win10", series.WindowsClient},
+	} {
+		channel, err := series.WindowsServicingChannel(test.series)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(channel, gc.Equals, test.channel, gc.Commentf("series %q", test.series))
+	}
+
+	_, err := series.WindowsServicingChannel("focal")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = series.WindowsServicingChannel("firewolf")
+	c.Assert(err, jc.Satisfies, series.IsUnknownOSForSeriesError)
+}
+
+func (s *windowsChannelSuite) TestAnnualChannelSeries(c *gc.C) {
+	info, err := series.GetSeries("win23h2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.OS, gc.Equals, os.Windows)
+	c.Assert(info.DisplayName(), gc.Equals, "Windows Server, version 23H2")
+
+	arches, err := series.ImageArchitectures("win23h2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arches, jc.DeepEquals, []string{"amd64"})
+}
+
+func (s *windowsChannelSuite) TestAnnualChannelLifecycle(c *gc.C) {
+	supportedAt := func(t time.Time) bool {
+		series.DefaultRegistry().SetClock(func() time.Time { return t })
+		return set.NewStrings(series.SupportedJujuWorkloadSeries()...).Contains("win23h2")
+	}
+	c.Assert(supportedAt(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)), jc.IsTrue)
+	c.Assert(supportedAt(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), jc.IsFalse)
+	c.Assert(supportedAt(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)), jc.IsFalse)
+}
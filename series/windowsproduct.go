@@ -0,0 +1,262 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/juju/errors"
+)
+
+// windowsVendorPrefixes holds the vendor names that localized installs put
+// in front of the product name, in the product name shown by WMI.
+var windowsVendorPrefixes = []string{
+	"Microsoft ",
+	"Майкрософт ",
+	"微软 ",
+}
+
+// windowsProductNameMarks holds trademark marks which some releases and
+// locales include in their product names.
+var windowsProductNameReplacer = strings.NewReplacer(
+	"®", "",
+	"™", "",
+	"(R)", "",
+	"(TM)", "",
+	" ", " ",
+	"　", " ",
+)
+
+// normalizeWindowsProductName reduces a possibly localized Windows product
+// name to the English form matched by windowsVersionMatchOrder. Trademark
+// marks, vendor prefixes and full-width characters, as found on some Asian
+// language installs, are removed or converted, and runs of whitespace are
+// collapsed. Edition names, which are often translated, follow the
+// product name and are left alone, as only the prefix is matched.
+func normalizeWindowsProductName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		// Map full-width ASCII variants to ASCII.
+		if r >= '！' && r <= '～' {
+			return r - 0xfee0
+		}
+		return r
+	}, name)
+	name = windowsProductNameReplacer.Replace(name)
+	name = strings.Join(strings.FieldsFunc(name, unicode.IsSpace), " ")
+	for _, prefix := range windowsVendorPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			name = strings.TrimPrefix(name, prefix)
+			break
+		}
+	}
+	return name
+}
+
+// windowsSeriesFromProductName returns the series for a Windows product
+// name, looking in the nano table if nano is true.
+func windowsSeriesFromProductName(name string, nano bool) (string, bool) {
+	name = normalizeWindowsProductName(name)
+	lookAt := windowsVersionTable()
+	if nano {
+		lookAt = windowsNanoVersionTable()
+	}
+	for _, value := range windowsVersionMatchOrder {
+		if strings.HasPrefix(name, value) {
+			if series, ok := lookAt[value]; ok {
+				return series, true
+			}
+		}
+	}
+	return "", false
+}
+
+// windowsBuild describes a Windows install by the numeric and invariant
+// registry values that don't depend on the display language.
+type windowsBuild struct {
+	// Number is the CurrentBuildNumber.
+	Number int
+	// Server is true if the InstallationType isn't "Client".
+	Server bool
+	// HyperV is true for Hyper-V Server, whose EditionID is
+	// "ServerHyperCore".
+	HyperV bool
+}
+
+// windowsSeriesFromBuild returns the series for a Windows build, for use
+// when the product name can't be matched.
+func windowsSeriesFromBuild(build windowsBuild) (string, bool) {
+	if !build.Server {
+		switch {
+		case build.Number == 7600, build.Number == 7601:
+			return "win7", true
+		case build.Number == 9200:
+			return "win8", true
+		case build.Number == 9600:
+			return "win81", true
+		case build.Number >= 22000:
+			return "win11", true
+		case build.Number >= 10240:
+			return "win10", true
+		}
+		return "", false
+	}
+	var series string
+	switch build.Number {
+	case 7600, 7601:
+		series = "win2008r2"
+	case 9200:
+		series = "win2012"
+	case 9600:
+		series = "win2012r2"
+	case 14393:
+		series = "win2016"
+	case 17763:
+		series = "win2019"
+	case 20348:
+		series = "win2022"
+	case 25398:
+		series = "win23h2"
+	default:
+		return "", false
+	}
+	if build.HyperV {
+		switch series {
+		case "win2012":
+			return "win2012hv", true
+		case "win2012r2":
+			return "win2012hvr2", true
+		case "win2016":
+			return "win2016hv", true
+		}
+	}
+	return series, true
+}
+
+// ParseWindows returns the Windows series named by input, which may be a
+// series name or one of the spellings found in inventory systems, such as
+// "Windows Server 2019 Datacenter", "windows2019" or "Win 2019", which all
+// give "win2019". Edition names like "Datacenter" and "Pro" are ignored.
+// Annual Channel releases are named by version, as in "Windows Server,
+// version 23H2" or "win23h2".
+func ParseWindows(input string) (string, error) {
+	var (
+		release                  string
+		server, r2, hyperV, nano bool
+		previous                 string
+	)
+	tokens := windowsInputTokens(input)
+	annual := windowsAnnualChannelRelease(tokens)
+	for _, token := range tokens {
+		switch token {
+		case "server":
+			server = true
+		case "r2":
+			r2 = true
+		case "hv", "hyperv":
+			hyperV = true
+		case "nano":
+			nano = true
+		case "2008", "2012", "2016", "2019", "2022":
+			release = token
+			server = true
+		case "7", "8", "10", "81":
+			if release == "" {
+				release = token
+			}
+		case "11":
+			// "3.11" is split into "3" and "11".
+			if release == "" && previous != "3" {
+				release = token
+			}
+		case "1":
+			// "8.1" is split into "8" and "1".
+			if previous == "8" && release == "8" {
+				release = "81"
+			}
+		case "2":
+			// "R 2" and "2012r2" leave the "2" on its own.
+			if previous == "r" {
+				r2 = true
+			}
+		case "v":
+			if previous == "hyper" {
+				hyperV = true
+			}
+		}
+		previous = token
+	}
+
+	var series string
+	switch {
+	case annual != "" && (server || release == ""):
+		series = windowsAnnualChannelSeries[annual]
+	case release == "":
+	case !server:
+		series = "win" + release
+	case release == "2008" && r2:
+		series = "win2008r2"
+	case release == "2012" && hyperV && r2:
+		series = "win2012hvr2"
+	case release == "2012" && hyperV:
+		series = "win2012hv"
+	case release == "2012" && r2:
+		series = "win2012r2"
+	case release == "2012":
+		series = "win2012"
+	case release == "2016" && hyperV:
+		series = "win2016hv"
+	case release == "2016" && nano:
+		series = "win2016nano"
+	case release == "2016", release == "2019", release == "2022":
+		series = "win" + release
+	}
+	if series == "" {
+		return "", errors.NotValidf("windows series %q", input)
+	}
+	return series, nil
+}
+
+// windowsAnnualChannelRelease returns the Annual Channel release, such as
+// "23h2", named by the tokens, or "" if they name none. The version is
+// split into "23", "h" and "2" by windowsInputTokens.
+func windowsAnnualChannelRelease(tokens []string) string {
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i+1] != "h" {
+			continue
+		}
+		version := tokens[i] + "h" + tokens[i+2]
+		if _, ok := windowsAnnualChannelSeries[version]; ok {
+			return version
+		}
+	}
+	return ""
+}
+
+// windowsInputTokens splits input into lowercase words and numbers,
+// separating letters from digits so that "win2012r2" gives "win", "2012",
+// "r" and "2".
+func windowsInputTokens(input string) []string {
+	input = strings.ToLower(normalizeWindowsProductName(input))
+	var tokens []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, string(current))
+			current = current[:0]
+		}
+	}
+	for _, r := range input {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case len(current) > 0 && unicode.IsDigit(r) != unicode.IsDigit(current[len(current)-1]):
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+	return tokens
+}
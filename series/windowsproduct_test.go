@@ -0,0 +1,113 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package series
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type windowsProductSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&windowsProductSuite{})
+
+func (s *windowsProductSuite) TestSeriesFromLocalizedProductName(c *gc.C) {
+	for i, test := range []struct {
+		name   string
+		nano   bool
+		series string
+	}{
+		{name: "Windows Server 2019 Datacenter", series: "win2019"},
+		{name: "Microsoft Windows 10 专业版", series: "win10"},
+		{name: "Майкрософт Windows 10 Корпоративная", series: "win10"},
+		{name: "Microsoft Windows 8.1 Professionnel", series: "win81"},
+		{name: "Windows Server® 2008 R2 Standard", series: "win2008r2"},
+		{name: "Windows Server(R) 2008 R2 Enterprise", series: "win2008r2"},
+		{name: "Ｗｉｎｄｏｗｓ　Ｓｅｒｖｅｒ　２０１６　Ｓｔａｎｄａｒｄ", series: "win2016"},
+		{name: "Windows  Server 2012 R2", series: "win2012r2"},
+		{name: "Windows Server 2016 Datacenter", nano: true, series: "win2016nano"},
+		{name: "Windows Server 2019", nano: true},
+		{name: "Windows 3.11 für Workgroups"},
+	} {
+		c.Logf("test %d: %s", i, test.name)
+		series, ok := windowsSeriesFromProductName(test.name, test.nano)
+		c.Check(ok, gc.Equals, test.series != "")
+		c.Check(series, gc.Equals, test.series)
+	}
+}
+
+func (s *windowsProductSuite) TestSeriesFromBuild(c *gc.C) {
+	for i, test := range []struct {
+		build  windowsBuild
+		series string
+	}{
+		{build: windowsBuild{Number: 7601}, series: "win7"},
+		{build: windowsBuild{Number: 7601, Server: true}, series: "win2008r2"},
+		{build: windowsBuild{Number: 9200, Server: true, HyperV: true}, series: "win2012hv"},
+		{build: windowsBuild{Number: 9600}, series: "win81"},
+		{build: windowsBuild{Number: 9600, Server: true, HyperV: true}, series: "win2012hvr2"},
+		{build: windowsBuild{Number: 14393, Server: true}, series: "win2016"},
+		{build: windowsBuild{Number: 17763, Server: true}, series: "win2019"},
+		{build: windowsBuild{Number: 19041}, series: "win10"},
+		{build: windowsBuild{Number: 20348, Server: true}, series: "win2022"},
+		{build: windowsBuild{Number: 22631}, series: "win11"},
+		{build: windowsBuild{Number: 25398, Server: true}, series: "win23h2"},
+		{build: windowsBuild{Number: 6001}},
+	} {
+		c.Logf("test %d: %+v", i, test.build)
+		series, ok := windowsSeriesFromBuild(test.build)
+		c.Check(ok, gc.Equals, test.series != "")
+		c.Check(series, gc.Equals, test.series)
+	}
+}
+
+func (s *windowsProductSuite) TestParseWindows(c *gc.C) {
+	for i, test := range []struct {
+		input  string
+		series string
+	}{
+		{input: "Windows Server 2019 Datacenter", series: "win2019"},
+		{input: "windows2019", series: "win2019"},
+		{input: "Win 2019", series: "win2019"},
+		{input: "win2019", series: "win2019"},
+		{input: "WIN2012R2", series: "win2012r2"},
+		{input: "Microsoft Windows Server 2012 R2 Standard", series: "win2012r2"},
+		{input: "Hyper-V Server 2012 R2", series: "win2012hvr2"},
+		{input: "win2012hv", series: "win2012hv"},
+		{input: "Windows Server 2012", series: "win2012"},
+		{input: "Windows Server 2008 R2 Enterprise", series: "win2008r2"},
+		{input: "Windows Server 2016 Nano", series: "win2016nano"},
+		{input: "Hyper-V Server 2016", series: "win2016hv"},
+		{input: "Windows 10 Pro", series: "win10"},
+		{input: "win 10", series: "win10"},
+		{input: "Windows 8.1 Enterprise", series: "win81"},
+		{input: "win81", series: "win81"},
+		{input: "Windows 7 Professional", series: "win7"},
+		{input: "Windows 8", series: "win8"},
+		{input: "Windows Server, version 23H2 Datacenter", series: "win23h2"},
+		{input: "win23h2", series: "win23h2"},
+		{input: "23H2", series: "win23h2"},
+		{input: "Windows 10 22H2", series: "win10"},
+		{input: "Windows Server 2022 Datacenter", series: "win2022"},
+		{input: "win2022", series: "win2022"},
+		{input: "Windows 11 Pro", series: "win11"},
+		{input: "win11", series: "win11"},
+	} {
+		c.Logf("test %d: %s", i, test.input)
+		series, err := ParseWindows(test.input)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(series, gc.Equals, test.series)
+	}
+}
+
+func (s *windowsProductSuite) TestParseWindowsInvalid(c *gc.C) {
+	for _, input := range []string{"", "Windows Server 2008", "Windows 3.11", "focal", "Windows Server", "Windows Server 20H2"} {
+		_, err := ParseWindows(input)
+		c.Check(err, jc.Satisfies, errors.IsNotValid, gc.Commentf("input %q", input))
+	}
+}